@@ -3,11 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/Aditya251610/pingify/internal/config"
 	"github.com/Aditya251610/pingify/internal/monitor"
 	"github.com/Aditya251610/pingify/internal/openai"
+	"github.com/Aditya251610/pingify/internal/secret"
 	"github.com/spf13/cobra"
 )
 
@@ -25,41 +26,44 @@ Example:
 		explain, _ := cmd.Flags().GetBool("explain")
 
 		if url == "" {
-			fmt.Println("❌ Please provide a --url to analyze.")
+			fmt.Fprintln(secret.Stdout, "❌ Please provide a --url to analyze.")
 			return
 		}
 
-		logFile := filepath.Join("logs", fmt.Sprintf("monitor_%s.json", monitor.HashURL(url)))
+		logFile := monitor.LogPath(url)
 		var summary string
 
 		// Step 1: Check if logs exist
 		if _, err := os.Stat(logFile); err == nil {
-			fmt.Println("📁 Log file found. Using it for analysis...")
+			fmt.Fprintln(secret.Stdout, "📁 Log file found. Using it for analysis...")
 			content, err := os.ReadFile(logFile)
 			if err != nil {
-				fmt.Println("❌ Failed to read log file:", err)
+				fmt.Fprintln(secret.Stdout, "❌ Failed to read log file:", err)
 				return
 			}
 			summary = openai.GetSuggestionsFromLogs(string(content), explain)
 		} else {
 			// Step 2: If not, perform a long monitor
-			fmt.Println("🔍 No logs found. Monitoring API for analysis (~3m)...")
+			fmt.Fprintln(secret.Stdout, "🔍 No logs found. Monitoring API for analysis (~3m)...")
 
-			_, _, _, _, _, _, _, _ = monitor.ExecuteMonitor(
-				url, "GET", "", "", "5s", false,
-				10*time.Second, 3*time.Minute, 1*time.Second,
-				false, "",
-			)
+			check := config.Check{
+				URL:       url,
+				Method:    "GET",
+				Timeout:   "5s",
+				Interval:  config.Duration(10 * time.Second),
+				Threshold: config.Duration(1 * time.Second),
+			}
+			_, _, _, _, _, _, _, _ = monitor.ExecuteMonitor(check, false, 3*time.Minute, false, "")
 
 			content, err := os.ReadFile(logFile)
 			if err != nil {
-				fmt.Println("❌ Monitoring failed or log not generated.")
+				fmt.Fprintln(secret.Stdout, "❌ Monitoring failed or log not generated.")
 				return
 			}
 			summary = openai.GetSuggestionsFromLogs(string(content), explain)
 		}
 
-		fmt.Println("🤖 AI Suggestions:\n\n" + summary)
+		fmt.Fprintln(secret.Stdout, "🤖 AI Suggestions:\n\n"+summary)
 	},
 }
 
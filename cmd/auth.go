@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/spf13/cobra"
+)
+
+// addAuthFlags registers the credential options shared by call and monitor.
+func addAuthFlags(c *cobra.Command) {
+	c.Flags().String("user", "", "Basic auth as user:password (the password may be env:NAME or file:path)")
+	c.Flags().String("bearer", "", "Bearer token, or env:NAME / file:path to read it from")
+	c.Flags().String("api-key", "", "API key as name=value (the value may be env:NAME or file:path)")
+	c.Flags().String("api-key-in", "header", "Send the API key as a header or query parameter")
+	c.Flags().String("oauth2-token-url", "", "OAuth2 token endpoint for the client-credentials or refresh-token flow")
+	c.Flags().String("client-id", "", "OAuth2 client ID")
+	c.Flags().String("client-secret", "", "OAuth2 client secret (may be env:NAME or file:path)")
+	c.Flags().StringSlice("scope", nil, "OAuth2 scopes to request")
+	c.Flags().String("refresh-token", "", "OAuth2 refresh token; switches to the refresh-token flow (may be env:NAME or file:path)")
+}
+
+// authOptionsFromFlags collects the credentials given on the command line.
+func authOptionsFromFlags(c *cobra.Command) requester.AuthOptions {
+	user, _ := c.Flags().GetString("user")
+	bearer, _ := c.Flags().GetString("bearer")
+	apiKey, _ := c.Flags().GetString("api-key")
+	apiKeyIn, _ := c.Flags().GetString("api-key-in")
+	tokenURL, _ := c.Flags().GetString("oauth2-token-url")
+	clientID, _ := c.Flags().GetString("client-id")
+	clientSecret, _ := c.Flags().GetString("client-secret")
+	scopes, _ := c.Flags().GetStringSlice("scope")
+	refreshToken, _ := c.Flags().GetString("refresh-token")
+
+	username, password, _ := strings.Cut(user, ":")
+	keyName, keyValue, _ := strings.Cut(apiKey, "=")
+	opts := requester.AuthOptions{
+		Username:     username,
+		Password:     password,
+		Token:        bearer,
+		APIKeyName:   keyName,
+		APIKeyValue:  keyValue,
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		RefreshToken: refreshToken,
+	}
+	if keyName != "" {
+		opts.APIKeyIn = apiKeyIn
+	}
+	return opts
+}
+
+// authFromFlags is authOptionsFromFlags in config form, or nil when no credentials were given.
+func authFromFlags(c *cobra.Command) *config.Auth {
+	o := authOptionsFromFlags(c)
+	if o.Username == "" && o.Token == "" && o.APIKeyName == "" && o.TokenURL == "" {
+		return nil
+	}
+	return &config.Auth{
+		Username:     o.Username,
+		Password:     o.Password,
+		Token:        o.Token,
+		APIKeyName:   o.APIKeyName,
+		APIKeyValue:  o.APIKeyValue,
+		APIKeyIn:     o.APIKeyIn,
+		TokenURL:     o.TokenURL,
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		Scopes:       o.Scopes,
+		RefreshToken: o.RefreshToken,
+	}
+}
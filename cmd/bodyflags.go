@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/spf13/cobra"
+)
+
+// addBodyFlags registers the body sources that complement --body on call and monitor.
+func addBodyFlags(c *cobra.Command) {
+	c.Flags().String("data-binary", "", "Send this data byte for byte; @file reads a file, @- reads stdin")
+	c.Flags().StringArray("form", nil, "Multipart form field key=value, or key=@path[;type=mime] to upload a file (repeatable)")
+	c.Flags().Bool("urlencoded", false, "Send the --form fields as application/x-www-form-urlencoded")
+}
+
+// bodyOptionsFromFlags collects --body and the other body sources given on the command line.
+func bodyOptionsFromFlags(c *cobra.Command) requester.BodyOptions {
+	body, _ := c.Flags().GetString("body")
+	binary, _ := c.Flags().GetString("data-binary")
+	form, _ := c.Flags().GetStringArray("form")
+	urlencoded, _ := c.Flags().GetBool("urlencoded")
+	return requester.BodyOptions{Data: body, Binary: binary, Form: form, URLEncoded: urlencoded}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/spf13/cobra"
+)
+
+// echoBody answers with the request's Content-Type and a description of its body: multipart
+// parts as name[filename type]=value and url-encoded fields as name=value, in name order.
+func echoBody(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	var fields []string
+	switch {
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		reader, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			field := part.FormName()
+			if part.FileName() != "" {
+				field += "[" + part.FileName() + " " + part.Header.Get("Content-Type") + "]"
+			}
+			fields = append(fields, field+"="+string(data))
+		}
+	case contentType == "application/x-www-form-urlencoded":
+		r.ParseForm()
+		for name, values := range r.PostForm {
+			fields = append(fields, name+"="+strings.Join(values, ","))
+		}
+	default:
+		data, _ := io.ReadAll(r.Body)
+		fields = append(fields, string(data))
+	}
+	sort.Strings(fields)
+	io.WriteString(w, strings.Split(contentType, ";")[0]+" "+strings.Join(fields, " "))
+}
+
+func TestBodyFlags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(echoBody))
+	defer srv.Close()
+	dir := t.TempDir()
+	for name, content := range map[string]string{"body.json": `{"id":1}`, "logo.png": "PNG", "notes.txt": "hi", "blob": "\x00\x01"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string // what echoBody answers
+		errText string
+	}{
+		{"inline body", []string{"--body", `{"a":1}`}, `application/json {"a":1}`, ""},
+		{"body file", []string{"--body", "@" + file("body.json")}, `application/json {"id":1}`, ""},
+		{"binary file typed by extension", []string{"--data-binary", "@" + file("logo.png")}, "image/png PNG", ""},
+		{"binary file without extension", []string{"--data-binary", "@" + file("blob")}, "application/octet-stream \x00\x01", ""},
+		{"inline binary", []string{"--data-binary", "raw"}, "application/octet-stream raw", ""},
+		{"multipart fields and files", []string{"--form", "title=Report", "--form", "logo=@" + file("logo.png"), "--form", "notes=@" + file("notes.txt") + ";type=text/markdown"},
+			"multipart/form-data logo[logo.png image/png]=PNG notes[notes.txt text/markdown]=hi title=Report", ""},
+		{"multipart file without extension", []string{"--form", "data=@" + file("blob")}, "multipart/form-data data[blob application/octet-stream]=\x00\x01", ""},
+		{"urlencoded with file value", []string{"--form", "q=a b", "--form", "q=c", "--form", "notes=@" + file("notes.txt"), "--urlencoded"},
+			"application/x-www-form-urlencoded notes=hi q=a b,c", ""},
+		{"two sources", []string{"--body", "{}", "--form", "a=b"}, "", "only one of body, binary data and form fields"},
+		{"form field without value", []string{"--form", "title"}, "", "must be key=value or key=@path"},
+		{"missing form file", []string{"--form", "f=@" + file("missing.bin")}, "", "failed to read body file"},
+		{"missing body file", []string{"--body", "@" + file("missing.json")}, "", "failed to read body file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cobra.Command{}
+			c.Flags().String("body", "", "")
+			addBodyFlags(c)
+			if err := c.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			body, contentType, err := bodyOptionsFromFlags(c).Load()
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := requester.Do(requester.Request{URL: srv.URL, Method: http.MethodPost, Body: body, ContentType: contentType, Timeout: "5s"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Body != tt.want {
+				t.Errorf("server saw %q, want %q", resp.Body, tt.want)
+			}
+		})
+	}
+}
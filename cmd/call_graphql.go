@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Aditya251610/pingify/internal/graphql"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// callGraphQL sends the --query file as a GraphQL operation, or introspects the schema with --introspect.
+func callGraphQL(cmd *cobra.Command, url, headers, timeout string, auth requester.Authenticator, signer requester.Signer, tlsOpts *requester.TLSOptions, netOpts *requester.NetOptions, httpOpts *requester.HTTPOptions, maxBody int64) {
+	queryFile, _ := cmd.Flags().GetString("query")
+	variables, _ := cmd.Flags().GetString("variables")
+	operation, _ := cmd.Flags().GetString("operation")
+	introspect, _ := cmd.Flags().GetBool("introspect")
+
+	req := graphql.Request{
+		URL:           url,
+		Variables:     variables,
+		OperationName: operation,
+		Headers:       headers,
+		Timeout:       timeout,
+		Retry:         retryPolicyFromFlags(cmd),
+		Auth:          auth,
+		Signer:        signer,
+		TLS:           tlsOpts,
+		Net:           netOpts,
+		HTTP:          httpOpts,
+		MaxBody:       maxBody,
+	}
+
+	if introspect {
+		span := telemetry.StartCheck("pingify.call.graphql", "POST", url)
+		req.Header = span.Header()
+		schema, result, err := graphql.Introspect(req)
+		if result != nil {
+			span.End(result.Response, err)
+		} else {
+			span.End(nil, err)
+		}
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			return
+		}
+		fmt.Fprintf(secret.Stdout, "✅ HTTP %d\n", result.Response.StatusCode)
+		fmt.Fprintln(secret.Stdout, "🧬 Schema:")
+		fmt.Fprintln(secret.Stdout, schema.Summary())
+		fmt.Fprintln(secret.Stdout, "⏱️ Execution Time:", result.Response.Duration)
+		return
+	}
+
+	if queryFile == "" {
+		fmt.Fprintln(secret.Stdout, "❌ Error: --graphql needs --query <file> or --introspect")
+		return
+	}
+	query, err := graphql.LoadQuery(queryFile)
+	if err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+		return
+	}
+	req.Query = query
+
+	span := telemetry.StartCheck("pingify.call.graphql", "POST", url)
+	req.Header = span.Header()
+	result, err := graphql.Do(req)
+	if result != nil {
+		span.End(result.Response, err)
+	} else {
+		span.End(nil, err)
+	}
+	if result != nil {
+		printAttempts(result.Response.Attempts)
+	}
+	if err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+		return
+	}
+
+	if problem := result.Problem(); problem != "" {
+		fmt.Fprintf(secret.Stdout, "❌ HTTP %d: %s\n", result.Response.StatusCode, problem)
+	} else {
+		fmt.Fprintf(secret.Stdout, "✅ HTTP %d\n", result.Response.StatusCode)
+	}
+	for _, e := range result.Errors {
+		fmt.Fprintln(secret.Stdout, "⚠️ ", e)
+	}
+	if result.Envelope {
+		fmt.Fprintln(secret.Stdout, result.PrettyData())
+	} else {
+		fmt.Fprintln(secret.Stdout, result.Response.Body)
+	}
+	fmt.Fprintln(secret.Stdout, "⏱️ Execution Time:", result.Response.Duration)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// callStream prints events as they arrive and asserts that every --expect appears in some event.
+func callStream(cmd *cobra.Command, req requester.Request) {
+	maxEvents, _ := cmd.Flags().GetInt("max-events")
+	expects, _ := cmd.Flags().GetStringArray("expect")
+
+	seen := make([]bool, len(expects))
+	span := telemetry.StartCheck("pingify.call.stream", req.Method, req.URL)
+	req.Header = span.Header()
+	result, err := requester.Stream(req, requester.StreamOptions{MaxEvents: maxEvents}, func(ev requester.Event) {
+		label := ""
+		if ev.Type != "" {
+			label = "[" + ev.Type + "] "
+		}
+		fmt.Fprintf(secret.Stdout, "📨 %s%s  (+%s)\n", label, ev.Data, ev.Delay)
+		for i, want := range expects {
+			if strings.Contains(ev.Data, want) {
+				seen[i] = true
+			}
+		}
+	})
+	if result != nil {
+		span.End(&requester.Response{StatusCode: result.StatusCode, Duration: result.Duration, Phases: result.Phases}, err)
+		printAttempts(result.Attempts)
+	} else {
+		span.End(nil, err)
+	}
+	if err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+		if result == nil || result.StatusCode == 0 {
+			return
+		}
+	}
+
+	fmt.Fprintf(secret.Stdout, "%s HTTP %d\n", statusMarker(result.StatusCode), result.StatusCode)
+	fmt.Fprintln(secret.Stdout, "📊 Events:           ", result.Events)
+	fmt.Fprintln(secret.Stdout, "⏱️ First Event:      ", result.TimeToFirstEvent)
+	fmt.Fprintln(secret.Stdout, "⏱️ Max / Avg Gap:    ", result.MaxGap, "/", result.AvgGap)
+	fmt.Fprintln(secret.Stdout, "⏱️ Stream Duration:  ", result.Duration)
+	if result.Truncated {
+		fmt.Fprintln(secret.Stdout, "✂️  Stopped at --max-events, --max-body or --timeout")
+	}
+	for i, ok := range seen {
+		if !ok {
+			fmt.Fprintf(secret.Stdout, "❌ No event contained %q\n", expects[i])
+		}
+	}
+}
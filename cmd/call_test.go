@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCheckCallFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		wantErr bool
+	}{
+		{"plain http with auth", map[string]string{"bearer": "tok"}, false},
+		{"graphql with signing", map[string]string{"graphql": "true", "aws-sigv4": "true"}, false},
+		{"grpc without credentials", map[string]string{"grpc": "true", "headers": `{"authorization":"Bearer x"}`}, false},
+		{"grpc with bearer", map[string]string{"grpc": "true", "bearer": "tok"}, true},
+		{"grpc with oauth2", map[string]string{"grpc": "true", "oauth2-token-url": "https://auth.example.com/token"}, true},
+		{"ws with hmac", map[string]string{"ws": "true", "hmac-secret": "s"}, true},
+		{"ws with basic auth", map[string]string{"ws": "true", "user": "a:b"}, true},
+		{"stream with har", map[string]string{"stream": "true", "har": "out.har"}, true},
+		{"plain http with har", map[string]string{"har": "out.har"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cobra.Command{}
+			for _, name := range []string{"grpc", "ws", "stream", "graphql"} {
+				c.Flags().Bool(name, false, "")
+			}
+			c.Flags().String("har", "", "")
+			c.Flags().String("headers", "", "")
+			addAuthFlags(c)
+			addSignFlags(c)
+			for name, value := range tt.flags {
+				if err := c.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := checkCallFlags(c); (err != nil) != tt.wantErr {
+				t.Errorf("checkCallFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNetFlagsIPVersion(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{[]string{"-4"}, 4, false},
+		{[]string{"-6"}, 6, false},
+		{[]string{"-4", "-6"}, 0, true},
+	}
+	for _, tt := range tests {
+		c := &cobra.Command{}
+		addNetFlags(c)
+		if err := c.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ValidateFlagGroups(); (err != nil) != tt.wantErr {
+			t.Errorf("%v: ValidateFlagGroups() error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if opts := netOptionsFromFlags(c); !tt.wantErr && opts.IPVersion != tt.want {
+			t.Errorf("%v: IPVersion = %d, want %d", tt.args, opts.IPVersion, tt.want)
+		}
+	}
+}
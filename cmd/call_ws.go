@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/wscall"
+	"github.com/spf13/cobra"
+)
+
+// callWebSocket runs a scripted exchange when --send is given, otherwise an interactive session
+// that sends each stdin line and prints incoming messages.
+func callWebSocket(cmd *cobra.Command, url, headers, timeout string, tlsConf *tls.Config, netOpts *requester.NetOptions) {
+	sends, _ := cmd.Flags().GetStringArray("send")
+	expects, _ := cmd.Flags().GetStringArray("expect")
+	wait, _ := cmd.Flags().GetDuration("wait")
+
+	header, err := requester.ParseHeaders(headers)
+	if err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+		return
+	}
+	d, _ := time.ParseDuration(timeout)
+
+	opts := wscall.Options{Timeout: d, TLS: tlsConf}
+	if netOpts != nil {
+		if opts.NetDial, err = netOpts.Dialer(); err == nil {
+			opts.Proxy, err = netOpts.ProxyFunc()
+		}
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			return
+		}
+	}
+
+	session, err := wscall.Dial(url, header, opts)
+	if err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+		return
+	}
+	defer session.Close()
+	fmt.Fprintln(secret.Stdout, "🔌 Connected in", session.Handshake)
+
+	if len(sends) == 0 && len(expects) == 0 {
+		interactiveWebSocket(session, wait)
+		return
+	}
+
+	// Each --expect is awaited after the --send at the same position; extra expects wait for more messages.
+	steps := max(len(sends), len(expects))
+	for i := 0; i < steps; i++ {
+		if i < len(sends) {
+			if err := session.Send(sends[i]); err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Send failed:", err)
+				return
+			}
+			fmt.Fprintln(secret.Stdout, "➡️ ", sends[i])
+		}
+		if i < len(expects) {
+			msg, elapsed, err := session.Expect(expects[i], wait)
+			if err != nil {
+				fmt.Fprintln(secret.Stdout, "❌", err)
+				return
+			}
+			fmt.Fprintf(secret.Stdout, "⬅️  %s (%s)\n", msg, elapsed)
+		}
+	}
+	if err := session.UnexpectedClose(); err != nil {
+		fmt.Fprintln(secret.Stdout, "❌", err)
+		return
+	}
+	fmt.Fprintln(secret.Stdout, "✅ WebSocket exchange complete")
+}
+
+// interactiveWebSocket sends stdin lines until EOF, then keeps printing replies until the
+// connection has been quiet for a moment (or wait elapses), so piped scripts see their answers.
+func interactiveWebSocket(session *wscall.Session, wait time.Duration) {
+	fmt.Fprintln(secret.Stdout, "💬 Type messages to send; Ctrl+D to close.")
+
+	activity := make(chan struct{}, 1)
+	go func() {
+		for msg := range session.Messages() {
+			fmt.Fprintln(secret.Stdout, "⬅️ ", msg)
+			select {
+			case activity <- struct{}{}:
+			default:
+			}
+		}
+		if err := session.UnexpectedClose(); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌", err)
+			exit(1)
+		}
+	}()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if err := session.Send(scanner.Text()); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Send failed:", err)
+			return
+		}
+	}
+
+	deadline := time.After(wait)
+	for {
+		select {
+		case <-activity:
+		case <-time.After(300 * time.Millisecond):
+			return
+		case <-deadline:
+			return
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/curl"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+// applyCurl parses a curl command (or - to read one from stdin) and sets the matching call
+// flags. Flags given explicitly on the command line win over the imported values.
+func applyCurl(c *cobra.Command, command string) error {
+	if command == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		command = string(data)
+	}
+	r, warnings, err := curl.Parse(command)
+	if err != nil {
+		return fmt.Errorf("--from-curl: %w", err)
+	}
+
+	headers := map[string]string{}
+	for _, h := range r.Headers {
+		if v, ok := headers[h.Name]; ok {
+			warnings = append(warnings, fmt.Sprintf("repeated header %s joined with a comma", h.Name))
+			h.Value = v + ", " + h.Value
+		}
+		headers[h.Name] = h.Value
+	}
+
+	type flagValue struct{ name, value string }
+	values := []flagValue{
+		{"url", r.URL},
+		{"method", r.Method},
+		{"body", r.Body},
+		{"data-binary", r.Data},
+		{"user", r.User},
+		{"bearer", r.Bearer},
+		{"cookie-jar", r.CookieJar},
+		{"http-version", r.HTTPVersion},
+		{"timeout", r.Timeout},
+		{"proxy", r.Proxy},
+		{"no-proxy", r.NoProxy},
+		{"interface", r.Interface},
+		{"unix-socket", r.UnixSocket},
+		{"cert", r.Cert},
+		{"key", r.Key},
+		{"pkcs12", r.PKCS12},
+		{"pkcs12-password", r.PKCS12Password},
+		{"cacert", r.CACert},
+		{"tls-min", r.TLSMin},
+		{"tls-max", r.TLSMax},
+		{"ciphers", strings.Join(r.Ciphers, ",")},
+		{"aws-region", r.AWSRegion},
+		{"aws-service", r.AWSService},
+		{"output", r.Output},
+	}
+	if len(headers) > 0 {
+		raw, _ := json.Marshal(headers)
+		values = append(values, flagValue{"headers", string(raw)})
+	}
+	for _, v := range r.Form {
+		values = append(values, flagValue{"form", v})
+	}
+	for _, v := range r.Resolve {
+		values = append(values, flagValue{"resolve", v})
+	}
+	for _, v := range r.ConnectTo {
+		values = append(values, flagValue{"connect-to", v})
+	}
+	for name, on := range map[string]bool{"insecure": r.Insecure, "no-follow": r.NoFollow, "aws-sigv4": r.AWSSigV4, "ipv4": r.IPVersion == 4, "ipv6": r.IPVersion == 6} {
+		if on {
+			values = append(values, flagValue{name, "true"})
+		}
+	}
+	for name, n := range map[string]int64{"max-redirects": int64(r.MaxRedirects), "retries": int64(r.Retries), "max-body": r.MaxBody} {
+		if n > 0 {
+			values = append(values, flagValue{name, strconv.FormatInt(n, 10)})
+		}
+	}
+	// Checked up front: setting a repeatable flag once marks it changed.
+	explicit := map[string]bool{}
+	for _, v := range values {
+		explicit[v.name] = c.Flags().Changed(v.name)
+	}
+	for _, v := range values {
+		if v.value == "" || explicit[v.name] {
+			continue
+		}
+		if err := c.Flags().Set(v.name, v.value); err != nil {
+			return fmt.Errorf("--from-curl: --%s: %w", v.name, err)
+		}
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintln(secret.Stdout, "⚠️  curl:", w)
+	}
+	return nil
+}
+
+// curlFromFlags builds the curl equivalent of a call, with notes on options curl cannot express.
+func curlFromFlags(c *cobra.Command) (*curl.Request, []string, error) {
+	for _, name := range []string{"grpc", "ws", "graphql"} {
+		if on, _ := c.Flags().GetBool(name); on {
+			return nil, nil, fmt.Errorf("--to-curl does not support --%s", name)
+		}
+	}
+
+	r := &curl.Request{}
+	r.URL, _ = c.Flags().GetString("url")
+	r.Method, _ = c.Flags().GetString("method")
+	r.Method = strings.ToUpper(r.Method)
+	if r.URL == "" {
+		return nil, nil, fmt.Errorf("--url is required")
+	}
+
+	raw, _ := c.Flags().GetString("headers")
+	header, err := requester.ParseHeaders(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.Headers = append(r.Headers, curl.Header{Name: name, Value: header.Get(name)})
+	}
+
+	body := bodyOptionsFromFlags(c)
+	r.Body, r.Data, r.Form, r.URLEncoded = body.Data, body.Binary, body.Form, body.URLEncoded
+
+	var notes []string
+	auth := authOptionsFromFlags(c)
+	if auth.Username != "" {
+		r.User = auth.Username + ":" + auth.Password
+	}
+	r.Bearer = auth.Token
+	if auth.APIKeyName != "" {
+		switch {
+		case auth.APIKeyIn != "query":
+			r.Headers = append(r.Headers, curl.Header{Name: auth.APIKeyName, Value: auth.APIKeyValue, Secret: true})
+		case strings.HasPrefix(auth.APIKeyValue, "env:") || strings.HasPrefix(auth.APIKeyValue, "file:"):
+			notes = append(notes, fmt.Sprintf("add the API key to the URL yourself: %s=<%s>", auth.APIKeyName, auth.APIKeyValue))
+		default:
+			sep := "?"
+			if strings.Contains(r.URL, "?") {
+				sep = "&"
+			}
+			r.URL += sep + url.QueryEscape(auth.APIKeyName) + "=" + url.QueryEscape(auth.APIKeyValue)
+		}
+	}
+	if auth.TokenURL != "" {
+		notes = append(notes, "curl cannot fetch OAuth2 tokens; get one first and pass it with --oauth2-bearer")
+	}
+
+	// The HMAC separator is the only option that can fail, and curl can't sign HMAC anyway.
+	sign, _ := signOptionsFromFlags(c)
+	switch sign.Type {
+	case "aws-sigv4":
+		r.AWSSigV4, r.AWSRegion, r.AWSService = true, sign.Region, sign.Service
+		if sign.Profile != "" {
+			notes = append(notes, fmt.Sprintf("export the credentials of AWS profile %s as AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY", sign.Profile))
+		}
+	case "hmac":
+		notes = append(notes, "curl cannot compute HMAC signatures; the signature header is left out")
+	}
+
+	if h := httpFromFlags(c); h != nil {
+		r.NoFollow, r.MaxRedirects, r.CookieJar, r.HTTPVersion = h.NoRedirects, h.MaxRedirects, h.CookieJar, h.Version
+	}
+	r.Timeout, _ = c.Flags().GetString("timeout")
+
+	if n := netOptionsFromFlags(c); n != nil {
+		r.Proxy, r.NoProxy, r.Resolve, r.ConnectTo = n.Proxy, n.NoProxy, n.Resolve, n.ConnectTo
+		r.IPVersion, r.Interface, r.UnixSocket = n.IPVersion, n.LocalAddress, n.UnixSocket
+	}
+	if t := tlsOptionsFromFlags(c); t != nil {
+		r.Cert, r.Key, r.PKCS12, r.PKCS12Password, r.CACert = t.CertFile, t.KeyFile, t.PKCS12File, t.PKCS12Password, t.CAFile
+		r.TLSMin, r.TLSMax, r.Ciphers, r.Insecure = t.MinVersion, t.MaxVersion, t.CipherSuites, t.Insecure
+		if t.ServerName != "" {
+			notes = append(notes, fmt.Sprintf("--sni %s has no direct curl equivalent; use --connect-to to reach another address under that name", t.ServerName))
+		}
+	}
+
+	r.Retries, _ = c.Flags().GetInt("retries")
+	r.Output, _ = c.Flags().GetString("output")
+	if r.MaxBody, err = maxBodyFromFlags(c); err != nil {
+		return nil, nil, err
+	}
+	r.Select, _ = c.Flags().GetStringArray("select")
+	if stream, _ := c.Flags().GetBool("stream"); stream {
+		notes = append(notes, "add -N to see streamed events as they arrive")
+	}
+	return r, append(notes, r.Notes()...), nil
+}
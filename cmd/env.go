@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// envVars holds the variables and secrets of the --env environment, for commands that expand
+// references themselves, such as test.
+var envVars = environment.Vars{}
+
+// exit finishes up and exits with code; use it instead of os.Exit.
+func exit(code int) {
+	finish()
+	os.Exit(code)
+}
+
+// finish exports the telemetry still queued and prints any output held back for masking.
+func finish() {
+	telemetry.Flush(5 * time.Second)
+	secret.Flush()
+}
+
+// applyEnvironment loads --env and expands {{name}}, {{$dynamic}} and ${ENV} references in the
+// flags given to the command, once, before it runs. Without --env flags are left as they are, so
+// bodies and queries containing {{ or ${ are sent unchanged.
+func applyEnvironment(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("env")
+	if name == "" {
+		return nil
+	}
+	env, err := environment.Load(name)
+	if err != nil {
+		return err
+	}
+	if envVars, err = env.Vars(); err != nil {
+		return fmt.Errorf("%s: %w", env.Path, err)
+	}
+
+	var failed error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if failed != nil || f.Name == "env" {
+			return
+		}
+		if list, ok := f.Value.(pflag.SliceValue); ok {
+			values := list.GetSlice()
+			changed := false
+			for i, v := range values {
+				if !environment.HasReferences(v) {
+					continue
+				}
+				if values[i], failed = envVars.Expand(v); failed != nil {
+					failed = fmt.Errorf("--%s: %w", f.Name, failed)
+					return
+				}
+				changed = true
+			}
+			if changed {
+				failed = list.Replace(values)
+			}
+			return
+		}
+		if f.Value.Type() != "string" || !environment.HasReferences(f.Value.String()) {
+			return
+		}
+		value, err := envVars.Expand(f.Value.String())
+		if err != nil {
+			failed = fmt.Errorf("--%s: %w", f.Name, err)
+			return
+		}
+		failed = f.Value.Set(value)
+	})
+	return failed
+}
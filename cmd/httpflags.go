@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+// addHTTPFlags registers the redirect, cookie and protocol options shared by call and monitor.
+func addHTTPFlags(c *cobra.Command) {
+	c.Flags().Bool("no-follow", false, "Return 3xx responses instead of following redirects")
+	c.Flags().Int("max-redirects", 0, "Follow at most this many redirects (default 10)")
+	c.Flags().String("cookie-jar", "", "Netscape cookie file to load cookies from and save them to after each request")
+	c.Flags().String("http-version", "", "Force the HTTP version: 1.1, 2 (prior knowledge on http:// URLs) or h2c")
+}
+
+// httpOptionsFromFlags collects the redirect, cookie and protocol options, opening the cookie jar.
+func httpOptionsFromFlags(c *cobra.Command) (*requester.HTTPOptions, error) {
+	h := httpFromFlags(c)
+	if h == nil {
+		return nil, nil
+	}
+	if err := requester.ValidateHTTPVersion(h.Version); err != nil {
+		return nil, err
+	}
+	if h.MaxRedirects < 0 {
+		return nil, fmt.Errorf("--max-redirects must not be negative")
+	}
+	opts := &requester.HTTPOptions{
+		NoRedirects:  h.NoRedirects,
+		MaxRedirects: h.MaxRedirects,
+		Version:      h.Version,
+	}
+	if h.CookieJar != "" {
+		jar, err := requester.OpenCookieJar(h.CookieJar)
+		if err != nil {
+			return nil, err
+		}
+		opts.Jar = jar
+	}
+	return opts, nil
+}
+
+// httpFromFlags returns the options in config form, or nil when none were given.
+func httpFromFlags(c *cobra.Command) *config.HTTP {
+	noFollow, _ := c.Flags().GetBool("no-follow")
+	maxRedirects, _ := c.Flags().GetInt("max-redirects")
+	cookieJar, _ := c.Flags().GetString("cookie-jar")
+	version, _ := c.Flags().GetString("http-version")
+
+	h := config.HTTP{NoRedirects: noFollow, MaxRedirects: maxRedirects, CookieJar: cookieJar, Version: version}
+	if h == (config.HTTP{}) {
+		return nil
+	}
+	return &h
+}
+
+// printRedirects lists the hops followed before the final response.
+func printRedirects(hops []requester.Hop) {
+	for _, h := range hops {
+		fmt.Fprintf(secret.Stdout, "↪️  %d %s -> %s (%s)\n", h.StatusCode, h.URL, h.Location, h.Duration)
+	}
+}
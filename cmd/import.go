@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/importer"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Convert Postman and Insomnia collections into Pingify collections",
+	Long: `Converts requests exported from Postman or Insomnia into a collection file for "pingify test"
+and "pingify monitor --type transaction", and their environments into environments/<name>.yaml
+for --env.
+
+Folders become request name prefixes ("Orders / create"), and auth set on the collection or a
+folder is copied to the requests that inherit it. {{variables}} keep working; Postman's
+{{$guid}} and {{$timestamp}} and Insomnia's {{ _.name }} and {% uuid %} are rewritten.
+
+Anything without an equivalent, such as pre-request and test scripts, is listed at the end so it
+can be redone by hand, e.g. as assertions.
+
+Examples:
+  pingify import postman orders.postman_collection.json
+  pingify import postman orders.postman_collection.json staging.postman_environment.json prod.postman_environment.json
+  pingify import insomnia insomnia-export.json --out tests/api.yaml`,
+}
+
+var importPostmanCmd = &cobra.Command{
+	Use:   "postman <collection.json> [environment.json...]",
+	Short: "Import a Postman collection (v2.0 or v2.1) and its environments",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := importer.Postman(args)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Import failed:", err)
+			exit(1)
+		}
+		saveImport(cmd, result, args[0])
+	},
+}
+
+var importInsomniaCmd = &cobra.Command{
+	Use:   "insomnia <export.json>",
+	Short: "Import an Insomnia export (v4 JSON) and its environments",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := importer.Insomnia(args[0])
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Import failed:", err)
+			exit(1)
+		}
+		saveImport(cmd, result, args[0])
+	},
+}
+
+// saveImport writes the converted collection and environments and lists what was not converted.
+func saveImport(cmd *cobra.Command, result *importer.Result, source string) {
+	out, _ := cmd.Flags().GetString("out")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if c := result.Collection; c != nil {
+		if out == "" {
+			out = importer.Slug(c.Name) + ".yaml"
+		}
+		var err error
+		if len(c.Requests) == 0 {
+			err = errors.New("the collection has no requests")
+		} else if err = c.Validate(); err == nil {
+			err = importer.WriteYAML(out, c, force)
+		}
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Import failed:", err)
+			exit(1)
+		}
+		fmt.Fprintf(secret.Stdout, "✅ Imported %d requests from %s into %s\n", len(c.Requests), source, out)
+	}
+
+	names := make([]string, 0, len(result.Environments))
+	for name := range result.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(environment.Dir, importer.Slug(name)+".yaml")
+		if err := importer.WriteYAML(path, result.Environments[name], force); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Import failed:", err)
+			exit(1)
+		}
+		fmt.Fprintf(secret.Stdout, "🌍 Environment %s saved to %s\n", name, path)
+	}
+
+	if len(result.Unsupported) > 0 {
+		fmt.Fprintf(secret.Stdout, "⚠️  %d item(s) need attention:\n", len(result.Unsupported))
+		for _, msg := range result.Unsupported {
+			fmt.Fprintln(secret.Stdout, "   •", msg)
+		}
+	}
+	if result.Collection != nil {
+		run := "pingify test " + out
+		if len(names) > 0 {
+			run += " --env " + importer.Slug(names[0])
+		}
+		fmt.Fprintln(secret.Stdout, "▶️  Run it with:", run)
+	}
+}
+
+func init() {
+	for _, c := range []*cobra.Command{importPostmanCmd, importInsomniaCmd} {
+		c.Flags().String("out", "", "Collection file to write (default: the collection's name, e.g. orders-api.yaml)")
+		c.Flags().Bool("force", false, "Replace existing collection and environment files")
+	}
+	importCmd.AddCommand(importPostmanCmd, importInsomniaCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Aditya251610/pingify/internal/config"
 	"github.com/Aditya251610/pingify/internal/monitor"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
 	"github.com/spf13/cobra"
 )
 
@@ -24,13 +31,67 @@ This command pings a given API endpoint repeatedly at set intervals, checking fo
   - Use --email to specify the recipient for email alerts.
   - Alerts are only sent if duration is longer than 2 minutes and a threshold is exceeded.
 
-📁 All logs are saved to 'logs/monitor_<url_hash>.json'
+🔒 Check Types:
+  - --type http (default) sends the request and measures the response. --select with a JSONPath
+    or jq-style expression (e.g. '$.status', '.items | length') fails the check when nothing
+    matches, and --expect requires the body or selected value to contain some text.
+  - --type tls inspects the certificate chain and fails when it expires within --warn-days,
+    does not match the hostname, or is served over a weak protocol.
+  - --type tcp connects to --url host:port, optionally sending --send and expecting --expect.
+  - --type udp sends --send in one datagram and expects a reply containing --expect.
+  - --type dns resolves --url for --record-type, optionally via --resolver, checking --answers.
+  - --type grpc runs a grpc.health.v1 check (or a unary --grpc-method) and checks --expect-code.
+  - --type ws measures the WebSocket handshake and a ping (or --send/--expect) round trip.
+  - --type stream reads SSE/NDJSON until it ends, --max-events arrive or --timeout passes, logging
+    time-to-first-event (compared to --threshold), gaps and event count.
+  - --type graphql POSTs the --query file with --variables/--operation and fails when the
+    response carries GraphQL errors, even with HTTP 200.
+  - --type transaction runs the request collection file given as --url (see 'pingify test') as
+    one synthetic transaction, e.g. log in, create, fetch and delete. Values captured in one step
+    are used by the next, each step's status and time is logged, and --threshold applies to the
+    total. Timeouts come from the file, and --env selects the environment it runs against.
+
+🔑 Authentication (http, graphql and stream checks):
+  - --user, --bearer, --api-key name=value (--api-key-in header|query), or OAuth2 with
+    --oauth2-token-url, --client-id, --client-secret, --scope and optionally --refresh-token.
+  - Secrets can be given as env:NAME or file:path. OAuth2 tokens are cached and refreshed
+    before they expire, so long runs keep working.
+  - --aws-sigv4 or --hmac-secret sign every check afresh, so signatures never go stale.
+
+🔐 TLS client options:
+  - --cert/--key or --pkcs12 for mutual TLS, --cacert for private CAs, --sni, --tls-min/--tls-max
+    and --ciphers apply to http, graphql, stream, grpc and ws checks.
+  - --insecure skips certificate verification; every result is marked insecure in the output and logs.
+
+🧭 Connection routing (http, graphql, stream, grpc and ws checks):
+  - --proxy (http/https/socks5/socks5h) with --no-proxy, --resolve host:port:addr,
+    --connect-to host:port:host2:port2, -4/-6, --interface and --unix-socket.
+
+↪️  Redirects, cookies and HTTP version (http, graphql and stream checks):
+  - --no-follow checks the 3xx response itself; --max-redirects caps the hops, which are logged.
+  - --cookie-jar file keeps cookies between runs in a Netscape (curl-compatible) cookie file.
+  - --http-version 1.1, 2 or h2c forces the protocol; the negotiated one is logged.
+
+🔁 Retries:
+  - --retries N retries timeouts, refused/reset connections and 408/425/429/5xx responses
+    (or --retry-on codes) with exponential backoff and jitter, honouring Retry-After.
+  - A check that passes after retrying counts as passed; its attempts are kept in the log
+    and 'pingify report' shows the retry rate.
+
+🗂️  HAR capture (http and graphql checks):
+  - --har-on-failure file.har appends the request, response, headers, bodies and timings of every
+    failed check to a HAR 1.2 file for browser devtools or HAR viewers. Credentials are redacted.
+
+📁 All logs are saved to 'logs/monitor_<url_hash>.jsonl'
 
 🔧 Example:
   pingify monitor --url https://example.com/api --interval 10s --duration 3m --threshold 800ms --alert --email user@example.com
+  pingify monitor --type tcp --url localhost:6379 --send 'PING\r\n' --expect +PONG
+  pingify monitor --type dns --url example.com --record-type A --resolver 1.1.1.1 --answers 93.184.215.14
+  pingify monitor --type transaction --url checkout.yaml --interval 1m --threshold 2s
 `,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("📡 Monitoring API endpoint...")
+		fmt.Fprintln(secret.Stdout, "📡 Monitoring API endpoint...")
 
 		url, _ := cmd.Flags().GetString("url")
 		method, _ := cmd.Flags().GetString("method")
@@ -43,37 +104,224 @@ This command pings a given API endpoint repeatedly at set intervals, checking fo
 		threshold, _ := cmd.Flags().GetDuration("threshold")
 		alert, _ := cmd.Flags().GetBool("alert")
 		email, _ := cmd.Flags().GetString("email")
+		checkType, _ := cmd.Flags().GetString("type")
+		warnDays, _ := cmd.Flags().GetInt("warn-days")
+		send, _ := cmd.Flags().GetString("send")
+		expect, _ := cmd.Flags().GetString("expect")
+		recordType, _ := cmd.Flags().GetString("record-type")
+		resolver, _ := cmd.Flags().GetString("resolver")
+		answers, _ := cmd.Flags().GetStringSlice("answers")
+		grpcService, _ := cmd.Flags().GetString("grpc-service")
+		grpcMethod, _ := cmd.Flags().GetString("grpc-method")
+		protoset, _ := cmd.Flags().GetString("protoset")
+		plaintext, _ := cmd.Flags().GetBool("plaintext")
+		expectCode, _ := cmd.Flags().GetString("expect-code")
+		maxEvents, _ := cmd.Flags().GetInt("max-events")
+		queryFile, _ := cmd.Flags().GetString("query")
+		variables, _ := cmd.Flags().GetString("variables")
+		operation, _ := cmd.Flags().GetString("operation")
+		bodyOpts := bodyOptionsFromFlags(cmd)
+		maxBody, _ := cmd.Flags().GetString("max-body")
+		selectExpr, _ := cmd.Flags().GetString("select")
+		harOnFailure, _ := cmd.Flags().GetString("har-on-failure")
 
 		// Warn user if alert is on but email is missing
 		if alert && email == "" {
-			fmt.Println("⚠️  You enabled --alert but did not provide an --email address.")
-			fmt.Println("    Please pass --email user@example.com to receive alerts.")
+			fmt.Fprintln(secret.Stdout, "⚠️  You enabled --alert but did not provide an --email address.")
+			fmt.Fprintln(secret.Stdout, "    Please pass --email user@example.com to receive alerts.")
+		}
+
+		if err := validateMonitorFlags(cmd, checkType, url); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌", err)
+			return
+		}
+		send, err := unescape("send", send)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌", err)
+			return
+		}
+		expect, err = unescape("expect", expect)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌", err)
+			return
+		}
+		sign, err := signFromFlags(cmd)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌", err)
+			return
+		}
+
+		check := config.Check{
+			Type:      checkType,
+			URL:       url,
+			Method:    method,
+			Headers:   headers,
+			Body:      body,
+			Timeout:   timeout,
+			Interval:  config.Duration(interval),
+			Threshold: config.Duration(threshold),
+			WarnDays:  warnDays,
+			Retry:     retryFromFlags(cmd),
+			Auth:      authFromFlags(cmd),
+			Sign:      sign,
+			TLS:       tlsFromFlags(cmd),
+			Network:   netFromFlags(cmd),
+			HTTP:      httpFromFlags(cmd),
+
+			DataBinary: bodyOpts.Binary,
+			Form:       bodyOpts.Form,
+			URLEncoded: bodyOpts.URLEncoded,
+			MaxBody:    maxBody,
+			Select:     selectExpr,
+
+			HAROnFailure: harOnFailure,
+
+			Send:       send,
+			Expect:     expect,
+			RecordType: recordType,
+			Resolver:   resolver,
+			Answers:    answers,
+
+			GRPCService: grpcService,
+			GRPCMethod:  grpcMethod,
+			Protoset:    protoset,
+			Plaintext:   plaintext,
+			ExpectCode:  expectCode,
+
+			MaxEvents: maxEvents,
+
+			QueryFile: queryFile,
+			Variables: variables,
+			Operation: operation,
+		}
+		if checkType == config.TypeTransaction {
+			check.Environment, _ = cmd.Flags().GetString("env")
+		}
+		if checkType == config.TypeTLS && !cmd.Flags().Changed("threshold") {
+			check.Threshold = 0
 		}
 
 		msg, monitoredURL, totalDuration, lastStatus, lastStatusText, endTime, err, success := monitor.ExecuteMonitor(
-			url, method, headers, body, timeout, pretty, interval, duration, threshold, alert, email,
+			check, pretty, duration, alert, email,
 		)
 
-		fmt.Println("=====================================")
+		fmt.Fprintln(secret.Stdout, "=====================================")
 		if err != nil {
-			fmt.Println("❌ Monitor ended with error:", err)
+			fmt.Fprintln(secret.Stdout, "❌ Monitor ended with error:", err)
 		} else {
-			fmt.Println("✅ Monitoring Complete")
-			fmt.Println("🔗 URL:        ", monitoredURL)
-			fmt.Println("📦 Message:    ", msg)
-			fmt.Println("📊 Duration:   ", totalDuration)
-			fmt.Println("📅 End Time:   ", endTime.Format(time.RFC1123))
-			fmt.Println("📈 Last Status:", lastStatus, "-", lastStatusText)
+			fmt.Fprintln(secret.Stdout, "✅ Monitoring Complete")
+			fmt.Fprintln(secret.Stdout, "🔗 URL:        ", monitoredURL)
+			fmt.Fprintln(secret.Stdout, "📦 Message:    ", msg)
+			fmt.Fprintln(secret.Stdout, "📊 Duration:   ", totalDuration)
+			fmt.Fprintln(secret.Stdout, "📅 End Time:   ", endTime.Format(time.RFC1123))
+			fmt.Fprintln(secret.Stdout, "📈 Last Status:", lastStatus, "-", lastStatusText)
 			if success {
-				fmt.Println("🎯 Thresholds respected.")
+				fmt.Fprintln(secret.Stdout, "🎯 Thresholds respected.")
 			} else {
-				fmt.Println("⚠️ Thresholds exceeded.")
+				fmt.Fprintln(secret.Stdout, "⚠️ Thresholds exceeded.")
 			}
-			fmt.Println("📁 Logs saved under /logs directory.")
+			fmt.Fprintln(secret.Stdout, "📁 Logs saved under /logs directory.")
 		}
 	},
 }
 
+// validateMonitorFlags reports the first flag that doesn't fit the check type.
+func validateMonitorFlags(cmd *cobra.Command, checkType, url string) error {
+	if url == "" {
+		return errors.New("--url is required")
+	}
+	known := false
+	for _, t := range monitor.CheckTypes() {
+		known = known || t == checkType
+	}
+	if !known {
+		return fmt.Errorf("--type must be one of %s", strings.Join(monitor.CheckTypes(), ", "))
+	}
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	duration, _ := cmd.Flags().GetDuration("duration")
+	threshold, _ := cmd.Flags().GetDuration("threshold")
+	if interval <= 0 || duration <= 0 {
+		return errors.New("--interval and --duration must be positive")
+	}
+	if threshold < 0 {
+		return errors.New("--threshold must not be negative")
+	}
+
+	switch checkType {
+	case config.TypeTCP, config.TypeUDP:
+		if _, _, err := net.SplitHostPort(config.HostPort(url)); err != nil {
+			return fmt.Errorf("--url must be host:port for %s checks", checkType)
+		}
+		if send, _ := cmd.Flags().GetString("send"); checkType == config.TypeUDP && send == "" {
+			return errors.New("--send is required for udp checks")
+		}
+	case config.TypeDNS:
+		recordType, _ := cmd.Flags().GetString("record-type")
+		switch strings.ToUpper(recordType) {
+		case "A", "AAAA", "CNAME", "MX", "NS", "TXT":
+		default:
+			return fmt.Errorf("--record-type %q is not supported (A, AAAA, CNAME, MX, NS or TXT)", recordType)
+		}
+	case config.TypeGraphQL:
+		if query, _ := cmd.Flags().GetString("query"); query == "" {
+			return errors.New("--query is required for graphql checks")
+		}
+	case config.TypeGRPC, config.TypeWS:
+		for _, name := range credentialFlags {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s does not apply to %s checks; send credentials in --headers instead", name, checkType)
+			}
+		}
+	}
+
+	bodies := 0
+	for _, name := range []string{"body", "data-binary", "form"} {
+		if cmd.Flags().Changed(name) {
+			bodies++
+		}
+	}
+	if bodies > 1 {
+		return errors.New("only one of --body, --data-binary and --form can be given")
+	}
+	if retries, _ := cmd.Flags().GetInt("retries"); retries < 0 {
+		return errors.New("--retries must not be negative")
+	}
+	if h := httpFromFlags(cmd); h != nil {
+		if err := requester.ValidateHTTPVersion(h.Version); err != nil {
+			return err
+		}
+		if h.MaxRedirects < 0 {
+			return errors.New("--max-redirects must not be negative")
+		}
+	}
+	return nil
+}
+
+// unescape interprets Go-style escapes (\r, \n, \x00) typed in the named flag. A backslash that
+// doesn't start a valid escape, including a trailing one, is an error; write \\ for a literal one.
+func unescape(flag, s string) (string, error) {
+	var b strings.Builder
+	for rest := s; rest != ""; {
+		if rest[0] == '"' {
+			b.WriteByte('"')
+			rest = rest[1:]
+			continue
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(rest, '"')
+		if err != nil {
+			return "", fmt.Errorf("--%s: invalid escape sequence at %q", flag, rest)
+		}
+		if multibyte {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(byte(r))
+		}
+		rest = tail
+	}
+	return b.String(), nil
+}
+
 func init() {
 	rootCmd.AddCommand(monitorCmd)
 
@@ -82,10 +330,36 @@ func init() {
 	monitorCmd.Flags().Duration("interval", 10*time.Second, "Interval between checks (e.g., 10s)")
 	monitorCmd.Flags().Duration("duration", 1*time.Minute, "Total monitoring duration (e.g., 3m)")
 	monitorCmd.Flags().String("headers", "", "JSON string representing headers to send")
-	monitorCmd.Flags().String("body", "", "JSON body string to include in the request")
+	monitorCmd.Flags().String("body", "", "JSON body to include in the request; @file is re-read on every check, @- reads stdin once")
 	monitorCmd.Flags().String("timeout", "5s", "Request timeout duration (e.g., 5s)")
-	monitorCmd.Flags().Duration("threshold", 500*time.Millisecond, "Alert if response time exceeds this value (e.g., 800ms)")
+	monitorCmd.Flags().Duration("threshold", 500*time.Millisecond, "Alert if response time exceeds this value (e.g., 800ms); tls checks have none unless set")
 	monitorCmd.Flags().Bool("pretty", false, "Format and colorize the response output")
 	monitorCmd.Flags().Bool("alert", false, "Enable alerting when threshold is exceeded")
 	monitorCmd.Flags().String("email", "", "User email address to send alerts to")
+	monitorCmd.Flags().String("type", "http", "Check type: http, tls, tcp, udp, dns, grpc, ws, stream, graphql or transaction")
+	monitorCmd.Flags().Int("warn-days", 14, "For tls checks, fail when the certificate expires within this many days")
+	monitorCmd.Flags().String("send", "", "For tcp/udp/ws checks, payload to send (escapes like \\r\\n are supported; \\\\ is a backslash)")
+	monitorCmd.Flags().String("expect", "", "Text the reply must contain: the body (or --select result) for http checks, the reply for tcp/udp/ws, some event for stream")
+	monitorCmd.Flags().String("record-type", "A", "For dns checks, record type to query (A, AAAA, CNAME, MX, NS, TXT)")
+	monitorCmd.Flags().String("resolver", "", "For dns checks, resolver to query (e.g. 1.1.1.1:53); defaults to the system resolver")
+	monitorCmd.Flags().StringSlice("answers", nil, "For dns checks, answers that must be present (comma-separated)")
+	monitorCmd.Flags().String("grpc-service", "", "For grpc checks, service name for the health check")
+	monitorCmd.Flags().String("grpc-method", "", "For grpc checks, unary method to call instead of the health check")
+	monitorCmd.Flags().String("protoset", "", "For grpc checks, FileDescriptorSet to use instead of server reflection")
+	monitorCmd.Flags().Bool("plaintext", false, "For grpc checks, connect without TLS")
+	monitorCmd.Flags().String("expect-code", "", "For grpc checks, expected status code (default OK)")
+	addRetryFlags(monitorCmd)
+	addAuthFlags(monitorCmd)
+	addSignFlags(monitorCmd)
+	addTLSFlags(monitorCmd)
+	addNetFlags(monitorCmd)
+	addHTTPFlags(monitorCmd)
+	addBodyFlags(monitorCmd)
+	addMaxBodyFlag(monitorCmd)
+	monitorCmd.Flags().String("select", "", "For http checks, JSONPath/jq-style expression or header:Name that must match (and contain --expect)")
+	monitorCmd.Flags().String("har-on-failure", "", "For http and graphql checks, append each failed request and response to this HAR file")
+	monitorCmd.Flags().Int("max-events", 0, "For stream checks, stop reading after this many events")
+	monitorCmd.Flags().String("query", "", "For graphql checks, file containing the query document")
+	monitorCmd.Flags().String("variables", "", "For graphql checks, JSON object of variables")
+	monitorCmd.Flags().String("operation", "", "For graphql checks, operation name to run")
 }
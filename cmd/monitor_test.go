@@ -0,0 +1,29 @@
+package cmd
+
+import "testing"
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{`PING\r\n`, "PING\r\n", false},
+		{`\x00\x01`, "\x00\x01", false},
+		{`say "hi"`, `say "hi"`, false},
+		{`C:\\dir`, `C:\dir`, false},
+		{`héllo`, "héllo", false},
+		{`dangling\`, "", true},
+		{`bad \q escape`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := unescape("send", tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("unescape(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("unescape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
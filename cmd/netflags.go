@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/spf13/cobra"
+)
+
+// addNetFlags registers the connection routing options shared by call and monitor.
+func addNetFlags(c *cobra.Command) {
+	c.Flags().String("proxy", "", "Proxy URL: http://, https://, socks5:// or socks5h://, optionally with user:password@")
+	c.Flags().String("no-proxy", "", "Comma-separated hosts, .domains and CIDRs to reach without the proxy")
+	c.Flags().StringArray("resolve", nil, "Pin host:port to an address, e.g. api.example.com:443:10.0.0.5 (repeatable)")
+	c.Flags().StringArray("connect-to", nil, "Connect to another host:port instead, e.g. api.example.com:443:backend-2:8443 (repeatable)")
+	c.Flags().BoolP("ipv4", "4", false, "Only use IPv4")
+	c.Flags().BoolP("ipv6", "6", false, "Only use IPv6")
+	c.Flags().String("interface", "", "Local IP address or interface name to send from")
+	c.Flags().String("unix-socket", "", "Connect through this Unix domain socket")
+	c.MarkFlagsMutuallyExclusive("ipv4", "ipv6")
+}
+
+// netOptionsFromFlags collects the routing options given on the command line.
+func netOptionsFromFlags(c *cobra.Command) *requester.NetOptions {
+	proxy, _ := c.Flags().GetString("proxy")
+	noProxy, _ := c.Flags().GetString("no-proxy")
+	resolve, _ := c.Flags().GetStringArray("resolve")
+	connectTo, _ := c.Flags().GetStringArray("connect-to")
+	ipv4, _ := c.Flags().GetBool("ipv4")
+	ipv6, _ := c.Flags().GetBool("ipv6")
+	iface, _ := c.Flags().GetString("interface")
+	unixSocket, _ := c.Flags().GetString("unix-socket")
+
+	opts := &requester.NetOptions{
+		Proxy:        proxy,
+		NoProxy:      noProxy,
+		Resolve:      resolve,
+		ConnectTo:    connectTo,
+		LocalAddress: iface,
+		UnixSocket:   unixSocket,
+	}
+	switch {
+	case ipv4:
+		opts.IPVersion = 4
+	case ipv6:
+		opts.IPVersion = 6
+	}
+	if opts.IsZero() {
+		return nil
+	}
+	return opts
+}
+
+// netFromFlags is netOptionsFromFlags in config form, or nil when no routing flags were given.
+func netFromFlags(c *cobra.Command) *config.Network {
+	o := netOptionsFromFlags(c)
+	if o == nil {
+		return nil
+	}
+	return &config.Network{
+		Proxy:        o.Proxy,
+		NoProxy:      o.NoProxy,
+		Resolve:      o.Resolve,
+		ConnectTo:    o.ConnectTo,
+		IPVersion:    o.IPVersion,
+		LocalAddress: o.LocalAddress,
+		UnixSocket:   o.UnixSocket,
+	}
+}
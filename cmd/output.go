@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/jsonpath"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+// addMaxBodyFlag registers --max-body, shared by call and monitor.
+func addMaxBodyFlag(c *cobra.Command) {
+	c.Flags().String("max-body", "", "Stop reading responses after this size, e.g. 512KB or 10MB (monitor default 10MB)")
+}
+
+// maxBodyFromFlags parses --max-body; zero means unlimited.
+func maxBodyFromFlags(c *cobra.Command) (int64, error) {
+	raw, _ := c.Flags().GetString("max-body")
+	if raw == "" {
+		return 0, nil
+	}
+	return requester.ParseSize(raw)
+}
+
+// progressPrinter returns a Progress callback that redraws a download counter on stderr at most
+// ten times a second, and a func that ends the line.
+func progressPrinter() (func(received, total int64), func()) {
+	var last time.Time
+	var drawn bool
+	draw := func(received, total int64) {
+		if total > 0 {
+			fmt.Fprintf(secret.Stderr, "\r⬇️  %s / %s (%d%%)   ", requester.FormatSize(received), requester.FormatSize(total), received*100/total)
+		} else {
+			fmt.Fprintf(secret.Stderr, "\r⬇️  %s   ", requester.FormatSize(received))
+		}
+		drawn = true
+	}
+	var lastReceived, lastTotal int64
+	progress := func(received, total int64) {
+		lastReceived, lastTotal = received, total
+		if time.Since(last) >= 100*time.Millisecond {
+			last = time.Now()
+			draw(received, total)
+		}
+	}
+	done := func() {
+		if drawn {
+			draw(lastReceived, lastTotal)
+			fmt.Fprintln(secret.Stderr)
+		}
+	}
+	return progress, done
+}
+
+// printBody prints the response body, or a summary when it is binary or was saved to a file,
+// followed by its size, encoding and charset.
+func printBody(resp *requester.Response, output string) {
+	switch {
+	case output != "":
+		fmt.Fprintf(secret.Stdout, "💾 Saved %s to %s\n", requester.FormatSize(resp.Size), output)
+	case resp.Binary:
+		fmt.Fprintf(secret.Stdout, "📦 Binary body (%s) not shown; use --output to save it\n", contentTypeOf(resp))
+	default:
+		fmt.Fprintln(secret.Stdout, resp.Body)
+	}
+
+	if resp.Encoding != "" {
+		fmt.Fprintf(secret.Stdout, "📏 Size: %s (%s, %s on the wire)\n", requester.FormatSize(resp.Size), resp.Encoding, requester.FormatSize(resp.WireSize))
+	} else if output == "" {
+		fmt.Fprintln(secret.Stdout, "📏 Size:", requester.FormatSize(resp.Size))
+	}
+	if resp.Charset != "" {
+		fmt.Fprintln(secret.Stdout, "🔤 Converted from", resp.Charset, "to UTF-8")
+	}
+	if resp.Truncated {
+		fmt.Fprintln(secret.Stdout, "✂️  Body truncated at --max-body", requester.FormatSize(resp.Size))
+	}
+}
+
+// printSelection prints what each --select expression matched; with several expressions each
+// result is labelled. It reports whether every expression matched something.
+func printSelection(resp *requester.Response, exprs []string) bool {
+	ok := true
+	for _, expr := range exprs {
+		values, err := jsonpath.Select(expr, resp.Body, resp.Header)
+		switch {
+		case err != nil:
+			fmt.Fprintf(secret.Stdout, "❌ --select %s: %v\n", expr, err)
+			ok = false
+			continue
+		case len(values) == 0:
+			fmt.Fprintf(secret.Stdout, "⚠️  --select %s matched nothing\n", expr)
+			ok = false
+			continue
+		}
+		if len(exprs) > 1 {
+			fmt.Fprintln(secret.Stdout, "🔎", expr)
+		}
+		fmt.Fprintln(secret.Stdout, jsonpath.Format(values))
+	}
+	return ok
+}
+
+func contentTypeOf(resp *requester.Response) string {
+	if t := resp.Header.Get("Content-Type"); t != "" {
+		return t + ", " + requester.FormatSize(resp.Size)
+	}
+	return requester.FormatSize(resp.Size)
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/Aditya251610/pingify/internal/config"
 	"github.com/Aditya251610/pingify/internal/report"
+	"github.com/Aditya251610/pingify/internal/secret"
 	"github.com/spf13/cobra"
 )
 
@@ -12,33 +14,48 @@ var reportCmd = &cobra.Command{
 	Short: "View summary report of monitored API",
 	Long: `Generates a summary report based on previously logged monitor data for a specific API.
 
+Checks run by 'pingify serve' are logged under their name; pass --check to report on one.
+
 Example:
-  pingify report --url https://example.com/api`,
+  pingify report --url https://example.com/api
+  pingify report --check orders-api`,
 	Run: func(cmd *cobra.Command, args []string) {
 		url, _ := cmd.Flags().GetString("url")
-		if url == "" {
-			fmt.Println("❌ Please provide a URL using --url")
+		name, _ := cmd.Flags().GetString("check")
+		if (url == "") == (name == "") {
+			fmt.Fprintln(secret.Stdout, "❌ Please provide either a URL using --url or a check name using --check")
 			return
 		}
+		key := url
+		if name != "" {
+			key = config.Check{Name: name}.LogKey()
+		}
 
-		report, err := report.GenerateReport(url)
+		report, err := report.GenerateReport(key)
 		if err != nil {
-			fmt.Println("❌ Error reading logs:", err)
+			fmt.Fprintln(secret.Stdout, "❌ Error reading logs:", err)
 			return
 		}
 
-		fmt.Println("\n📄 API Monitoring Report")
-		fmt.Println("🔗 URL:       ", url)
-		fmt.Println("📅 From:      ", report.StartTime.Format("2006-01-02 15:04:05"))
-		fmt.Println("📅 To:        ", report.EndTime.Format("2006-01-02 15:04:05"))
-		fmt.Println("📊 Total:     ", report.TotalChecks)
-		fmt.Println("✅ Success:   ", report.SuccessCount)
-		fmt.Println("⚠️ Exceeded: ", report.ExceededCount)
-		fmt.Println("⏱️ Avg Time:  ", report.AvgExecutionTime)
+		fmt.Fprintln(secret.Stdout, "\n📄 API Monitoring Report")
+		if name != "" {
+			fmt.Fprintln(secret.Stdout, "🏷️ Check:     ", name)
+		} else {
+			fmt.Fprintln(secret.Stdout, "🔗 URL:       ", url)
+		}
+		fmt.Fprintln(secret.Stdout, "📅 From:      ", report.StartTime.Format("2006-01-02 15:04:05"))
+		fmt.Fprintln(secret.Stdout, "📅 To:        ", report.EndTime.Format("2006-01-02 15:04:05"))
+		fmt.Fprintln(secret.Stdout, "📊 Total:     ", report.TotalChecks)
+		fmt.Fprintln(secret.Stdout, "✅ Success:   ", report.SuccessCount)
+		fmt.Fprintln(secret.Stdout, "⚠️ Exceeded: ", report.ExceededCount)
+		fmt.Fprintln(secret.Stdout, "⏱️ Avg Time:  ", report.AvgExecutionTime)
+		fmt.Fprintf(secret.Stdout, "🔁 Retried:    %d (%.1f%%), %d passed after retrying, %d retries in total\n",
+			report.RetriedCount, report.RetryRate*100, report.RecoveredCount, report.TotalRetries)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(reportCmd)
-	reportCmd.Flags().String("url", "", "The URL of the monitored API (required)")
+	reportCmd.Flags().String("url", "", "The URL of the monitored API")
+	reportCmd.Flags().String("check", "", "Name of a check run by 'pingify serve'")
 }
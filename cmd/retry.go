@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+// addRetryFlags registers the retry options shared by call and monitor.
+func addRetryFlags(c *cobra.Command) {
+	c.Flags().Int("retries", 0, "Retry transient failures this many times before giving up")
+	c.Flags().Duration("retry-backoff", requester.DefaultBackoff, "Delay before the first retry; doubles per retry, with jitter")
+	c.Flags().Duration("retry-max-backoff", requester.DefaultMaxBackoff, "Longest delay between retries (a longer Retry-After stops retrying)")
+	c.Flags().IntSlice("retry-on", nil, "Status codes to retry (default 408,425,429,500,502,503,504)")
+}
+
+// retryFromFlags returns the retry settings given on the command line, or nil without --retries.
+func retryFromFlags(c *cobra.Command) *config.Retry {
+	retries, _ := c.Flags().GetInt("retries")
+	if retries <= 0 {
+		return nil
+	}
+	backoff, _ := c.Flags().GetDuration("retry-backoff")
+	maxBackoff, _ := c.Flags().GetDuration("retry-max-backoff")
+	on, _ := c.Flags().GetIntSlice("retry-on")
+	return &config.Retry{
+		Attempts:   retries + 1,
+		Backoff:    config.Duration(backoff),
+		MaxBackoff: config.Duration(maxBackoff),
+		OnStatus:   on,
+	}
+}
+
+// retryPolicyFromFlags is retryFromFlags for requests made directly with requester.
+func retryPolicyFromFlags(c *cobra.Command) requester.RetryPolicy {
+	r := retryFromFlags(c)
+	if r == nil {
+		return requester.RetryPolicy{}
+	}
+	return requester.RetryPolicy{
+		MaxAttempts: r.Attempts,
+		Backoff:     time.Duration(r.Backoff),
+		MaxBackoff:  time.Duration(r.MaxBackoff),
+		RetryOn:     r.OnStatus,
+	}
+}
+
+// printAttempts lists the retried attempts of a request.
+func printAttempts(attempts []requester.Attempt) {
+	for _, a := range attempts {
+		if a.Retry == "" {
+			continue
+		}
+		fmt.Fprintf(secret.Stdout, "🔁 Attempt %d: %s, retried after %s\n", a.Number, a.Retry, a.Wait)
+	}
+}
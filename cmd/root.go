@@ -5,8 +5,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/telemetry"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +30,14 @@ Features include:
 
 Whether you're building REST APIs, microservices, or backend systems,
 pingify helps you catch issues early and improve development speed.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		otlpEndpoint, _ := cmd.Flags().GetString("otlp-endpoint")
+		telemetry.SetEndpoint(otlpEndpoint)
+		if err := applyEnvironment(cmd); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			exit(1)
+		}
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -36,15 +45,20 @@ func Execute() {
 	// ✅ Load .env file at the very beginning
 	err := godotenv.Load()
 	if err != nil {
-		fmt.Println("⚠️  Could not load .env file (email alerts may fail):", err)
+		fmt.Fprintln(secret.Stdout, "⚠️  Could not load .env file (email alerts may fail):", err)
 	}
 
-	// Run the CLI
+	// Cobra's usage and error messages are masked like the rest of the output
+	rootCmd.SetOut(secret.Stdout)
+	rootCmd.SetErr(secret.Stderr)
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		exit(1)
 	}
+	finish()
 }
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().String("env", "", "Environment whose variables and secrets fill {{name}} references: a name in environments/ or a file")
+	rootCmd.PersistentFlags().String("otlp-endpoint", "", "OTLP/HTTP collector URL for traces and metrics (defaults to $OTEL_EXPORTER_OTLP_ENDPOINT)")
 }
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/har"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Replay recorded requests, e.g. from a HAR file exported by browser devtools",
+	Long: `The "run" command replays the requests recorded in a HAR (HTTP Archive) file, such as one saved
+from the browser's Network tab with "Save all as HAR", or written by 'pingify call --har' and
+'pingify monitor --har-on-failure'.
+
+Each request is sent as recorded, with its method, URL, headers and body, and the new status and
+timing are shown next to the recorded ones. Redirects are not followed because every hop is its
+own entry. The command exits with status 1 when a request fails or its status differs.
+
+Examples:
+  pingify run --har session.har
+  pingify run --har session.har --filter '/api/' --bearer env:API_TOKEN
+  pingify run --har failures.har --connect-to api.example.com:443:staging.example.com:443 --har-out replay.har
+
+Flags:
+  --har       HAR file to replay (required)
+  --filter    Only replay requests whose URL matches this regular expression
+  --timeout   Timeout for each request (default 30s)
+  --har-out   Record the replayed exchanges to a new HAR file
+  --user, --bearer, --api-key
+              Credentials for the replay; recorded ones are redacted by Pingify
+  --insecure, --cacert, --proxy, --resolve, --connect-to, ...
+              The TLS and routing options of 'pingify call' apply to every request`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, _ := cmd.Flags().GetString("har")
+		filter, _ := cmd.Flags().GetString("filter")
+		timeout, _ := cmd.Flags().GetString("timeout")
+		harOut, _ := cmd.Flags().GetString("har-out")
+		if path == "" {
+			fmt.Fprintln(secret.Stdout, "❌ Error: --har is required")
+			return
+		}
+		var match *regexp.Regexp
+		if filter != "" {
+			var err error
+			if match, err = regexp.Compile(filter); err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Error: invalid --filter:", err)
+				return
+			}
+		}
+		file, err := har.Read(path)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			return
+		}
+
+		auth, err := requester.NewAuthenticator(authOptionsFromFlags(cmd))
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			return
+		}
+		tlsOpts := tlsOptionsFromFlags(cmd)
+		warnInsecure(tlsOpts)
+		netOpts := netOptionsFromFlags(cmd)
+		httpOpts := &requester.HTTPOptions{NoRedirects: true}
+
+		var entries []har.Entry
+		var toReplay []har.Entry
+		for _, e := range file.Log.Entries {
+			u := e.Request.URL
+			if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+				continue // data:, blob:, chrome-extension: and the like
+			}
+			if match == nil || match.MatchString(u) {
+				toReplay = append(toReplay, e)
+			}
+		}
+		if len(toReplay) == 0 {
+			fmt.Fprintln(secret.Stdout, "⚠️  No HTTP requests to replay in", path)
+			return
+		}
+
+		fmt.Fprintf(secret.Stdout, "▶️  Replaying %d of %d recorded requests from %s\n", len(toReplay), len(file.Log.Entries), path)
+		var matched, differed, failed int
+		warnedRedacted := false
+		for i, e := range toReplay {
+			fmt.Fprintf(secret.Stdout, "\n[%d/%d] %s %s\n", i+1, len(toReplay), e.Request.Method, e.Request.URL)
+			if e.Request.Redacted() && auth == nil && !warnedRedacted {
+				fmt.Fprintln(secret.Stdout, "⚠️  Credentials were redacted when this was recorded; pass --user, --bearer or --api-key to send your own.")
+				warnedRedacted = true
+			}
+			body, contentType, err := e.Request.Body()
+			if err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+				failed++
+				continue
+			}
+			headers, _ := json.Marshal(e.Request.Header())
+
+			resp, err := requester.Do(requester.Request{
+				URL:         e.Request.URL,
+				Method:      e.Request.Method,
+				Headers:     string(headers),
+				Body:        body,
+				ContentType: contentType,
+				Timeout:     timeout,
+				Auth:        auth,
+				TLS:         tlsOpts,
+				Net:         netOpts,
+				HTTP:        httpOpts,
+			})
+			if harOut != "" {
+				entries = append(entries, har.FromResponse(resp, err)...)
+			}
+			recorded := time.Duration(e.Time * float64(time.Millisecond)).Round(time.Millisecond)
+			if err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+				failed++
+				continue
+			}
+
+			took := resp.Duration
+			switch {
+			case e.Response.Status == 0:
+				fmt.Fprintf(secret.Stdout, "✅ HTTP %d (no response was recorded) ⏱️ %s\n", resp.StatusCode, took)
+				matched++
+			case resp.StatusCode == e.Response.Status:
+				fmt.Fprintf(secret.Stdout, "✅ HTTP %d ⏱️ %s (recorded %s)\n", resp.StatusCode, took, recorded)
+				matched++
+			default:
+				fmt.Fprintf(secret.Stdout, "❌ HTTP %d, recorded %d ⏱️ %s (recorded %s)\n", resp.StatusCode, e.Response.Status, took, recorded)
+				differed++
+			}
+		}
+
+		if harOut != "" {
+			if err := har.New(entries...).Write(harOut); err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Failed to write HAR:", err)
+			} else {
+				fmt.Fprintln(secret.Stdout, "\n🗂️  Replay saved to", harOut)
+			}
+		}
+		fmt.Fprintln(secret.Stdout, "=====================================")
+		fmt.Fprintf(secret.Stdout, "📊 %d matched, %d differed, %d failed\n", matched, differed, failed)
+		if differed > 0 || failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().String("har", "", "HAR 1.2 file whose requests are replayed (required)")
+	runCmd.Flags().String("filter", "", "Only replay requests whose URL matches this regular expression")
+	runCmd.Flags().String("timeout", "30s", "Timeout for each request")
+	runCmd.Flags().String("har-out", "", "Write the replayed requests and responses to this HAR file")
+	addAuthFlags(runCmd)
+	addTLSFlags(runCmd)
+	addNetFlags(runCmd)
+}
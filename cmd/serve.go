@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run checks continuously and manage them through a local REST API",
+	Long: `🛰️ Pingify daemon: run every check from a config file indefinitely.
+
+Checks are loaded from --config (YAML, or JSON when the file ends in .json) and the
+file is reloaded automatically whenever it changes. A local REST API lets you manage
+checks without editing the file by hand; changes made through the API are written back.
+
+{{name}} references in check fields are not filled in; a transaction check runs its
+collection file with the variables of its "environment".
+
+📄 Config example (pingify.yaml):
+  checks:
+    - name: posts
+      url: https://jsonplaceholder.typicode.com/posts
+      interval: 30s
+      threshold: 800ms
+      alert: true
+      email: user@example.com
+
+🔌 API:
+  GET    /api/checks                 list checks and their latest result
+  POST   /api/checks                 create a check
+  GET    /api/checks/{name}          show one check
+  PUT    /api/checks/{name}          replace a check
+  DELETE /api/checks/{name}          delete a check
+  POST   /api/checks/{name}/pause    pause a check
+  POST   /api/checks/{name}/resume   resume a check
+  POST   /api/checks/{name}/run      run a check immediately
+  GET    /api/checks/{name}/results  logged results (?limit=N)
+  GET    /api/checks/{name}/report   summary report
+
+🔑 With --token (or PINGIFY_API_TOKEN), every /api request except GET /api/status needs
+"Authorization: Bearer <token>". The status page and heartbeat pings stay open, and checks
+are returned with their literal credentials shown as ****.
+Listening on anything but a loopback address requires a token.
+
+🔧 Example:
+  pingify serve --config pingify.yaml --addr 127.0.0.1:8080
+  pingify serve --config pingify.yaml --addr :8080 --token env:PINGIFY_TOKEN
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, _ := cmd.Flags().GetString("config")
+		addr, _ := cmd.Flags().GetString("addr")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv("PINGIFY_API_TOKEN")
+		}
+		token, err := requester.ResolveSecret(token)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Invalid --token:", err)
+			exit(1)
+		}
+		if token == "" && !isLoopback(addr) {
+			fmt.Fprintf(secret.Stdout, "❌ Refusing to serve the API on %s without --token: anyone who can reach it could change checks.\n", addr)
+			exit(1)
+		}
+
+		srv, err := server.New(configPath)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to load config:", err)
+			exit(1)
+		}
+		srv.RequireToken(token)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Fprintf(secret.Stdout, "🛰️  Pingify daemon listening on http://%s (config: %s)\n", addr, configPath)
+		if err := srv.Run(ctx, addr); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Server error:", err)
+			exit(1)
+		}
+		fmt.Fprintln(secret.Stdout, "👋 Pingify daemon stopped.")
+	},
+}
+
+// isLoopback reports whether addr only listens on the local machine.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().String("config", "pingify.yaml", "Path to the checks config file (YAML or JSON)")
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address for the REST API to listen on")
+	serveCmd.Flags().String("token", "", "Bearer token required to change checks or post incidents (or env:NAME / file:path)")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/spf13/cobra"
+)
+
+// addSignFlags registers the request signing options shared by call and monitor.
+func addSignFlags(c *cobra.Command) {
+	c.Flags().Bool("aws-sigv4", false, "Sign requests with AWS Signature V4 (credentials from AWS_* env vars or ~/.aws/credentials)")
+	c.Flags().String("aws-region", "", "Region for --aws-sigv4 (inferred from *.amazonaws.com hosts)")
+	c.Flags().String("aws-service", "", "Service for --aws-sigv4, e.g. execute-api (inferred from *.amazonaws.com hosts)")
+	c.Flags().String("aws-profile", "", "Profile in the shared credentials file for --aws-sigv4")
+	c.Flags().String("hmac-secret", "", "Sign requests with an HMAC using this secret (may be env:NAME or file:path)")
+	c.Flags().String("hmac-header", "X-Signature", "Header the HMAC signature is sent in")
+	c.Flags().String("hmac-algorithm", "sha256", "HMAC hash: sha256, sha1 or sha512")
+	c.Flags().String("hmac-encoding", "hex", "HMAC signature encoding: hex or base64")
+	c.Flags().String("hmac-prefix", "", "Prefix for the HMAC signature value, e.g. sha256=")
+	c.Flags().String("hmac-timestamp-header", "", "Send the Unix time in this header and include it in the signature")
+	c.Flags().StringSlice("hmac-sign", nil, "Parts signed, in order: body, timestamp, method, path, query, url, header:Name")
+	c.Flags().String("hmac-separator", `\n`, "Separator between signed parts (escapes like \\n are supported)")
+}
+
+// signOptionsFromFlags collects the signer given on the command line.
+func signOptionsFromFlags(c *cobra.Command) (requester.SignOptions, error) {
+	sigv4, _ := c.Flags().GetBool("aws-sigv4")
+	region, _ := c.Flags().GetString("aws-region")
+	service, _ := c.Flags().GetString("aws-service")
+	profile, _ := c.Flags().GetString("aws-profile")
+	secret, _ := c.Flags().GetString("hmac-secret")
+	header, _ := c.Flags().GetString("hmac-header")
+	algorithm, _ := c.Flags().GetString("hmac-algorithm")
+	encoding, _ := c.Flags().GetString("hmac-encoding")
+	prefix, _ := c.Flags().GetString("hmac-prefix")
+	timestampHeader, _ := c.Flags().GetString("hmac-timestamp-header")
+	parts, _ := c.Flags().GetStringSlice("hmac-sign")
+	separator, _ := c.Flags().GetString("hmac-separator")
+
+	switch {
+	case sigv4:
+		return requester.SignOptions{Type: requester.SignAWSSigV4, Region: region, Service: service, Profile: profile}, nil
+	case secret != "":
+		separator, err := unescape("hmac-separator", separator)
+		if err != nil {
+			return requester.SignOptions{}, err
+		}
+		return requester.SignOptions{
+			Type:            requester.SignHMAC,
+			Secret:          secret,
+			Header:          header,
+			Algorithm:       algorithm,
+			Encoding:        encoding,
+			Prefix:          prefix,
+			TimestampHeader: timestampHeader,
+			Parts:           parts,
+			Separator:       separator,
+		}, nil
+	}
+	return requester.SignOptions{}, nil
+}
+
+// signFromFlags is signOptionsFromFlags in config form, or nil when no signer was given.
+func signFromFlags(c *cobra.Command) (*config.Sign, error) {
+	o, err := signOptionsFromFlags(c)
+	if err != nil || o.Type == "" {
+		return nil, err
+	}
+	return &config.Sign{
+		Type:            o.Type,
+		Region:          o.Region,
+		Service:         o.Service,
+		Profile:         o.Profile,
+		Secret:          o.Secret,
+		Header:          o.Header,
+		Algorithm:       o.Algorithm,
+		Encoding:        o.Encoding,
+		Prefix:          o.Prefix,
+		TimestampHeader: o.TimestampHeader,
+		Parts:           o.Parts,
+		Separator:       o.Separator,
+	}, nil
+}
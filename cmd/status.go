@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/status"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Build a self-hosted status page from monitor data",
+	Long: `Generates a public status page from the checks in a config file and their stored logs.
+
+Components are configured under the "status" section of the config file; without it
+every check is shown as its own component. The page shows the current state, 90-day
+uptime bars, incidents recorded by "pingify serve" and manually posted notes.
+
+The same page is served live by "pingify serve" at /status.
+
+Examples:
+  pingify status build --config pingify.yaml --out public
+  pingify status note --title "Database maintenance" --component API --state scheduled`,
+}
+
+var statusBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Generate the status page as a static site",
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, _ := cmd.Flags().GetString("config")
+		out, _ := cmd.Flags().GetString("out")
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to load config:", err)
+			return
+		}
+
+		page, err := status.Build(cfg)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to build status page:", err)
+			return
+		}
+
+		if err := status.WriteSite(out, page); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to write status page:", err)
+			return
+		}
+		fmt.Fprintln(secret.Stdout, "✅ Status page written to", out)
+		fmt.Fprintln(secret.Stdout, "📊 Overall state:", page.State)
+	},
+}
+
+var statusNoteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Post a manual incident note to the status page",
+	Run: func(cmd *cobra.Command, args []string) {
+		title, _ := cmd.Flags().GetString("title")
+		message, _ := cmd.Flags().GetString("message")
+		component, _ := cmd.Flags().GetString("component")
+		state, _ := cmd.Flags().GetString("state")
+
+		note, err := status.AddNote(status.Note{Title: title, Message: message, Component: component, State: state})
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to post note:", err)
+			return
+		}
+		fmt.Fprintln(secret.Stdout, "📝 Incident note posted at", note.Time.Format("2006-01-02 15:04:05"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.AddCommand(statusBuildCmd, statusNoteCmd)
+
+	statusBuildCmd.Flags().String("config", "pingify.yaml", "Path to the checks config file (YAML or JSON)")
+	statusBuildCmd.Flags().String("out", "public", "Directory to write the static site to")
+
+	statusNoteCmd.Flags().String("title", "", "Incident title (required)")
+	statusNoteCmd.Flags().String("message", "", "Incident details")
+	statusNoteCmd.Flags().String("component", "", "Affected component")
+	statusNoteCmd.Flags().String("state", "", "Incident state (e.g. investigating, identified, resolved)")
+}
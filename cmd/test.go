@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test <suite.yaml> [more suites...]",
+	Short: "Run a collection of requests and check their responses against assertions",
+	Long: `The "test" command runs the requests of one or more YAML or JSON test files in order and checks
+each response against its assertions on status, headers, body fields, JSON Schema and latency.
+
+The command exits with status 1 when any assertion fails or a request gets no response, so it
+can gate CI pipelines; --junit writes a report that CI systems display per request.
+
+A test file looks like this:
+
+  name: Users API
+  base_url: https://api.example.com
+  defaults:
+    headers:
+      Accept: application/json
+    timeout: 10s
+  requests:
+    - name: list users
+      url: /users?limit=5
+      assert:
+        status: 200                # or [200, 201], or 2xx; by default 4xx and 5xx fail
+        latency: 500ms
+        headers:
+          Content-Type: { contains: application/json }
+        body:
+          $.users: { type: array, length: 5 }
+          $.users[0].email: { matches: '@example\.com$' }
+          .total: { gte: 5 }
+        schema: schemas/users.json  # relative to the test file, or written inline
+    - name: create user
+      method: POST
+      url: /users
+      body: { name: Ada, role: admin }
+      assert:
+        status: 201
+        body:
+          $.name: Ada
+
+Requests can pass values along, e.g. log in, use the token, create a resource, fetch it and
+delete it. "capture" saves part of a response in a variable, and {{name}} uses it in the URL,
+headers, body, form fields and expected values of later requests:
+
+  setup:                           # runs first; when it fails the requests are skipped
+    - name: log in
+      method: POST
+      url: /login
+      body: { user: ci, password: secret }
+      capture:
+        token: $.access_token      # JSONPath or jq-style expression
+  defaults:
+    headers:
+      Authorization: Bearer {{token}}   # sent once token is captured
+  requests:
+    - name: create
+      method: POST
+      url: /items
+      capture:
+        id: .id
+        location: { header: Location }
+        etag: { regex: 'version (\d+)' }
+    - name: fetch
+      url: /items/{{id}}
+      assert:
+        body:
+          $.id: "{{id}}"
+  teardown:                        # always runs; skipped when its variables were never captured
+    - name: delete
+      method: DELETE
+      url: /items/{{id}}
+
+Body assertions take a JSONPath or jq-style expression (or header:Name) and either a value that
+must be equal or a mapping of: equals, not_equals, one_of, contains, matches, exists, type,
+length, gt, gte, lt, lte. "contains" under assert checks the raw body for a substring.
+
+"auth" under defaults or on a request replaces the auth flags, with the fields of a monitor
+config's auth; "type: none" sends a request without credentials.
+
+--env staging loads environments/staging.yaml, whose variables and secrets override the file's
+own "variables". {{$uuid}}, {{$timestamp}}, {{$randomInt 1 10}} and {{$hmac sha256 key msg}} are
+computed on each use, and ${NAME} reads an environment variable.
+
+Examples:
+  pingify test api.yaml
+  pingify test tests/*.yaml --junit reports/pingify.xml
+  pingify test smoke.json --bearer env:API_TOKEN --bail
+  pingify test api.yaml --env staging
+
+Flags:
+  --junit     Write a JUnit XML report to this file
+  --bail      Skip the remaining requests of a file after the first failure
+  --timeout   Override the timeout of every request
+  --user, --bearer, --api-key, --oauth2-*
+              Credentials added to every request
+  --insecure, --cacert, --proxy, --resolve, --cookie-jar, ...
+              The TLS, routing and redirect options of 'pingify call' apply to every request`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		junit, _ := cmd.Flags().GetString("junit")
+		bail, _ := cmd.Flags().GetBool("bail")
+		timeout, _ := cmd.Flags().GetString("timeout")
+		if timeout != "" {
+			if _, err := time.ParseDuration(timeout); err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Error: invalid --timeout:", err)
+				return
+			}
+		}
+
+		var collections []*collection.Collection
+		for _, path := range args {
+			c, err := collection.Load(path)
+			if err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+				exit(1)
+			}
+			collections = append(collections, c)
+		}
+
+		auth, err := requester.NewAuthenticator(authOptionsFromFlags(cmd))
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			return
+		}
+		httpOpts, err := httpOptionsFromFlags(cmd)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Error:", err)
+			return
+		}
+		tlsOpts := tlsOptionsFromFlags(cmd)
+		warnInsecure(tlsOpts)
+		opts := collection.Options{
+			Auth:     auth,
+			TLS:      tlsOpts,
+			Net:      netOptionsFromFlags(cmd),
+			HTTP:     httpOpts,
+			Vars:     envVars,
+			Timeout:  timeout,
+			Bail:     bail,
+			Progress: printTestResult,
+		}
+
+		var suites []collection.SuiteResult
+		var passed, failed, skipped int
+		for _, c := range collections {
+			name := c.Name
+			if name == "" {
+				name = c.Path
+			}
+			fmt.Fprintf(secret.Stdout, "\n🧪 Running %s (%d requests)\n", name, len(c.Requests))
+			suite := collection.Run(c, opts)
+			suites = append(suites, suite)
+			p, f, s := suite.Counts()
+			passed, failed, skipped = passed+p, failed+f, skipped+s
+		}
+
+		if junit != "" {
+			if err := collection.WriteJUnit(junit, suites); err != nil {
+				fmt.Fprintln(secret.Stdout, "❌ Failed to write JUnit report:", err)
+				failed++
+			} else {
+				fmt.Fprintln(secret.Stdout, "\n📄 JUnit report saved to", junit)
+			}
+		}
+		fmt.Fprintln(secret.Stdout, "=====================================")
+		summary := fmt.Sprintf("📊 %d passed, %d failed", passed, failed)
+		if skipped > 0 {
+			summary += fmt.Sprintf(", %d skipped", skipped)
+		}
+		fmt.Fprintln(secret.Stdout, summary)
+		if failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+// printTestResult prints one request's outcome as it completes.
+func printTestResult(r collection.Result) {
+	switch {
+	case r.Skipped:
+		fmt.Fprintf(secret.Stdout, "⏭️  %s (skipped)\n", r.Label())
+	case r.Error != "":
+		fmt.Fprintf(secret.Stdout, "❌ %s — %s %s\n   • %s\n", r.Label(), r.Method, r.URL, r.Error)
+	case r.Passed():
+		fmt.Fprintf(secret.Stdout, "✅ %s — HTTP %d in %s\n", r.Label(), r.StatusCode, r.Duration)
+	default:
+		fmt.Fprintf(secret.Stdout, "❌ %s — HTTP %d in %s\n", r.Label(), r.StatusCode, r.Duration)
+		for _, f := range r.Failures {
+			fmt.Fprintln(secret.Stdout, "   •", f)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().String("junit", "", "Write a JUnit XML report of the results to this file")
+	testCmd.Flags().Bool("bail", false, "Skip the remaining requests of a file after the first failure")
+	testCmd.Flags().String("timeout", "", "Timeout for every request, overriding the test files")
+	addAuthFlags(testCmd)
+	addTLSFlags(testCmd)
+	addNetFlags(testCmd)
+	addHTTPFlags(testCmd)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/spf13/cobra"
+)
+
+// addTLSFlags registers the TLS client options shared by call and monitor.
+func addTLSFlags(c *cobra.Command) {
+	c.Flags().String("cert", "", "Client certificate (PEM) for mutual TLS; may also contain the key")
+	c.Flags().String("key", "", "Private key (PEM) for --cert")
+	c.Flags().String("pkcs12", "", "Client certificate and key as a PKCS#12 (.p12/.pfx) bundle")
+	c.Flags().String("pkcs12-password", "", "Password for --pkcs12 (may be env:NAME or file:path)")
+	c.Flags().String("cacert", "", "PEM bundle of extra CAs to trust (e.g. a private CA)")
+	c.Flags().String("sni", "", "Server name to send in SNI and verify the certificate against")
+	c.Flags().String("tls-min", "", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	c.Flags().String("tls-max", "", "Maximum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	c.Flags().StringSlice("ciphers", nil, "Allowed TLS 1.2 cipher suites, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	c.Flags().BoolP("insecure", "k", false, "Skip TLS certificate verification (flagged in output and logs)")
+}
+
+// tlsOptionsFromFlags collects the TLS client settings given on the command line.
+func tlsOptionsFromFlags(c *cobra.Command) *requester.TLSOptions {
+	cert, _ := c.Flags().GetString("cert")
+	key, _ := c.Flags().GetString("key")
+	p12, _ := c.Flags().GetString("pkcs12")
+	p12Password, _ := c.Flags().GetString("pkcs12-password")
+	ca, _ := c.Flags().GetString("cacert")
+	sni, _ := c.Flags().GetString("sni")
+	minVersion, _ := c.Flags().GetString("tls-min")
+	maxVersion, _ := c.Flags().GetString("tls-max")
+	ciphers, _ := c.Flags().GetStringSlice("ciphers")
+	insecure, _ := c.Flags().GetBool("insecure")
+
+	opts := &requester.TLSOptions{
+		CertFile:       cert,
+		KeyFile:        key,
+		PKCS12File:     p12,
+		PKCS12Password: p12Password,
+		CAFile:         ca,
+		ServerName:     sni,
+		MinVersion:     minVersion,
+		MaxVersion:     maxVersion,
+		CipherSuites:   ciphers,
+		Insecure:       insecure,
+	}
+	if opts.IsZero() {
+		return nil
+	}
+	return opts
+}
+
+// tlsFromFlags is tlsOptionsFromFlags in config form, or nil when no TLS flags were given.
+func tlsFromFlags(c *cobra.Command) *config.TLS {
+	o := tlsOptionsFromFlags(c)
+	if o == nil {
+		return nil
+	}
+	return &config.TLS{
+		Cert:           o.CertFile,
+		Key:            o.KeyFile,
+		PKCS12:         o.PKCS12File,
+		PKCS12Password: o.PKCS12Password,
+		CA:             o.CAFile,
+		ServerName:     o.ServerName,
+		MinVersion:     o.MinVersion,
+		MaxVersion:     o.MaxVersion,
+		Ciphers:        o.CipherSuites,
+		Insecure:       o.Insecure,
+	}
+}
+
+// warnInsecure prints the --insecure warning, once per command.
+func warnInsecure(opts *requester.TLSOptions) {
+	if opts != nil && opts.Insecure {
+		fmt.Fprintln(secret.Stdout, "⚠️  INSECURE: TLS certificate verification is disabled (--insecure). Do not trust this result.")
+	}
+}
+
+// printNegotiatedTLS shows the protocol and cipher of a connection made with custom TLS settings.
+func printNegotiatedTLS(state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+	fmt.Fprintf(secret.Stdout, "🔒 %s, %s", tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+	if len(state.PeerCertificates) > 0 {
+		fmt.Fprintf(secret.Stdout, ", server cert %q", state.PeerCertificates[0].Subject.CommonName)
+	}
+	fmt.Fprintln(secret.Stdout)
+}
@@ -0,0 +1,484 @@
+package collection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/jsonpath"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Assert lists what a response must satisfy. Without a status assertion, 4xx and 5xx fail.
+type Assert struct {
+	Status Status `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// Headers and Body map header names and JSONPath or jq-style expressions to matchers.
+	Headers map[string]Matcher `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body    map[string]Matcher `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// Contains must appear somewhere in the response body.
+	Contains string `json:"contains,omitempty" yaml:"contains,omitempty"`
+
+	// Schema is a JSON Schema file, relative to the collection, or a schema written inline.
+	Schema any `json:"schema,omitempty" yaml:"schema,omitempty"`
+
+	// Latency is the longest the request may take, e.g. "300ms".
+	Latency config.Duration `json:"latency,omitempty" yaml:"latency,omitempty"`
+
+	paths  map[string]*jsonpath.Expr
+	schema *jsonschema.Schema
+}
+
+var statusPattern = regexp.MustCompile(`^[1-5]([0-9]{2}|xx)$`)
+
+// Status lists accepted status codes; "2xx" accepts a whole class. It is written as a single
+// value or a list.
+type Status []string
+
+func (s *Status) UnmarshalYAML(node *yaml.Node) error {
+	var list []string
+	if node.Kind == yaml.SequenceNode {
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+	} else {
+		list = []string{node.Value}
+	}
+	for _, code := range list {
+		if !statusPattern.MatchString(strings.ToLower(code)) {
+			return fmt.Errorf("invalid status %q: use a code like 200 or a class like 2xx", code)
+		}
+	}
+	*s = list
+	return nil
+}
+
+func (s Status) match(code int) bool {
+	if len(s) == 0 {
+		return code > 0 && code < 400
+	}
+	got := strconv.Itoa(code)
+	for _, want := range s {
+		want = strings.ToLower(want)
+		if want == got || (strings.HasSuffix(want, "xx") && want[0] == got[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Status) String() string {
+	if len(s) == 0 {
+		return "below 400"
+	}
+	return strings.Join(s, " or ")
+}
+
+// Matcher checks one selected value. A plain value must equal it; a mapping combines the
+// operators below, all of which must hold.
+type Matcher struct {
+	Equals    any    `yaml:"equals"`
+	NotEquals any    `yaml:"not_equals"`
+	Contains  any    `yaml:"contains"`
+	Matches   string `yaml:"matches"`
+	Exists    *bool  `yaml:"exists"`
+	GT        any    `yaml:"gt"`
+	GTE       any    `yaml:"gte"`
+	LT        any    `yaml:"lt"`
+	LTE       any    `yaml:"lte"`
+	Length    *int   `yaml:"length"`
+	Type      string `yaml:"type"`
+	OneOf     []any  `yaml:"one_of"`
+	hasEquals bool
+	hasNot    bool
+	expanded  bool
+	pattern   *regexp.Regexp
+}
+
+// matcherFields are the operator names, so misspelt ones are reported instead of ignored.
+var matcherFields = map[string]bool{
+	"equals": true, "not_equals": true, "contains": true, "matches": true, "exists": true, "gt": true,
+	"gte": true, "lt": true, "lte": true, "length": true, "type": true, "one_of": true,
+}
+
+func (m *Matcher) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		var v any
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		*m = Matcher{Equals: jsonValue(v), hasEquals: true}
+		return nil
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !matcherFields[key] {
+			return fmt.Errorf("line %d: unknown assertion %q", node.Content[i].Line, key)
+		}
+		m.hasEquals = m.hasEquals || key == "equals"
+		m.hasNot = m.hasNot || key == "not_equals"
+	}
+	type plain Matcher
+	if err := node.Decode((*plain)(m)); err != nil {
+		return err
+	}
+	m.Equals, m.NotEquals, m.Contains = jsonValue(m.Equals), jsonValue(m.NotEquals), jsonValue(m.Contains)
+	m.GT, m.GTE, m.LT, m.LTE = jsonValue(m.GT), jsonValue(m.GTE), jsonValue(m.LT), jsonValue(m.LTE)
+	for i, v := range m.OneOf {
+		m.OneOf[i] = jsonValue(v)
+	}
+	return nil
+}
+
+// compile checks the expressions, patterns and schema once, before anything is sent.
+func (a *Assert) compile(dir string) error {
+	a.paths = map[string]*jsonpath.Expr{}
+	for expr, m := range a.Body {
+		e, err := jsonpath.Compile(expr)
+		if err != nil {
+			return err
+		}
+		a.paths[expr] = e
+		if err := m.compile(); err != nil {
+			return fmt.Errorf("body %s: %w", expr, err)
+		}
+		a.Body[expr] = m
+	}
+	for name, m := range a.Headers {
+		if err := m.compile(); err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+		a.Headers[name] = m
+	}
+
+	switch s := a.Schema.(type) {
+	case nil:
+	case string:
+		schema, err := jsonschema.Compile(resolvePath(dir, s))
+		if err != nil {
+			return fmt.Errorf("invalid schema: %w", err)
+		}
+		a.schema = schema
+	default:
+		data, err := json.Marshal(jsonValue(s))
+		if err != nil {
+			return fmt.Errorf("invalid schema: %w", err)
+		}
+		schema, err := jsonschema.CompileString("inline.json", string(data))
+		if err != nil {
+			return fmt.Errorf("invalid schema: %w", err)
+		}
+		a.schema = schema
+	}
+	return nil
+}
+
+func (m *Matcher) compile() error {
+	if m.Matches != "" {
+		re, err := regexp.Compile(m.Matches)
+		if err != nil {
+			return fmt.Errorf("invalid matches pattern: %w", err)
+		}
+		m.pattern = re
+	}
+	switch m.Type {
+	case "", "string", "number", "boolean", "array", "object", "null":
+	default:
+		return fmt.Errorf("unknown type %q", m.Type)
+	}
+	return nil
+}
+
+// check returns what is wrong with the response; nothing means every assertion passed. Expected
+// values may reference variables.
+func (a *Assert) check(resp *requester.Response, vars environment.Vars) []string {
+	var failures []string
+	if !a.Status.match(resp.StatusCode) {
+		failures = append(failures, fmt.Sprintf("status %d, expected %s", resp.StatusCode, a.Status))
+	}
+	if a.Latency > 0 && resp.Duration > a.Latency.Std() {
+		failures = append(failures, fmt.Sprintf("took %s, over the %s latency limit", resp.Duration.Round(time.Millisecond), a.Latency))
+	}
+	for _, name := range sortedKeys(a.Headers) {
+		values := resp.Header.Values(name)
+		var actual []any
+		if len(values) > 0 {
+			actual = []any{strings.Join(values, ", ")}
+		}
+		m, err := a.Headers[name].expand(vars)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("header %s: %v", http.CanonicalHeaderKey(name), err))
+			continue
+		}
+		if problem := m.check(actual, true); problem != "" {
+			failures = append(failures, fmt.Sprintf("header %s %s", http.CanonicalHeaderKey(name), problem))
+		}
+	}
+	if a.Contains != "" {
+		if want, err := vars.Expand(a.Contains); err != nil {
+			failures = append(failures, fmt.Sprintf("contains: %v", err))
+		} else if !strings.Contains(resp.Body, want) {
+			failures = append(failures, fmt.Sprintf("body does not contain %q", want))
+		}
+	}
+	for _, expr := range sortedKeys(a.Body) {
+		values, err := a.paths[expr].Select(resp.Body, resp.Header)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("body %s: %v", expr, err))
+			continue
+		}
+		m, err := a.Body[expr].expand(vars)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("body %s: %v", expr, err))
+			continue
+		}
+		if problem := m.check(values, false); problem != "" {
+			failures = append(failures, fmt.Sprintf("body %s %s", expr, problem))
+		}
+	}
+	if a.schema != nil {
+		failures = append(failures, validateSchema(a.schema, resp.Body)...)
+	}
+	return failures
+}
+
+// expand returns the matcher with variables replaced in its expected strings. Captured values are
+// text, so a matcher using them compares loosely, e.g. the captured id "42" equals the number 42.
+func (m Matcher) expand(vars environment.Vars) (Matcher, error) {
+	var err error
+	for _, v := range []*any{&m.Equals, &m.NotEquals, &m.Contains, &m.GT, &m.GTE, &m.LT, &m.LTE} {
+		if s, ok := (*v).(string); ok && environment.HasReferences(s) {
+			m.expanded = true
+		}
+		if *v, err = vars.ExpandValue(*v); err != nil {
+			return m, err
+		}
+	}
+	oneOf := make([]any, len(m.OneOf))
+	for i, v := range m.OneOf {
+		if oneOf[i], err = vars.ExpandValue(v); err != nil {
+			return m, err
+		}
+	}
+	m.OneOf = oneOf
+	return m, nil
+}
+
+// check tests the values an expression selected; several values are checked as one array. Header
+// values are text, so with loose they are compared by their text to numbers and booleans.
+func (m Matcher) check(values []any, loose bool) string {
+	loose = loose || m.expanded
+	if len(values) == 0 {
+		if m.Exists != nil && !*m.Exists {
+			return ""
+		}
+		return "is missing"
+	}
+	if m.Exists != nil && !*m.Exists {
+		return fmt.Sprintf("exists (%s)", show(values[0]))
+	}
+	actual := values[0]
+	if len(values) > 1 {
+		actual = values
+	}
+
+	equal := func(a, b any) bool {
+		if jsonpath.Equal(a, b) {
+			return true
+		}
+		return loose && jsonpath.FormatValue(a) == jsonpath.FormatValue(b)
+	}
+	if m.hasEquals && !equal(actual, m.Equals) {
+		return fmt.Sprintf("is %s, expected %s", show(actual), show(m.Equals))
+	}
+	if m.hasNot && equal(actual, m.NotEquals) {
+		return fmt.Sprintf("is %s, expected anything else", show(actual))
+	}
+	if len(m.OneOf) > 0 {
+		found := false
+		for _, v := range m.OneOf {
+			found = found || equal(actual, v)
+		}
+		if !found {
+			return fmt.Sprintf("is %s, expected one of %s", show(actual), show(m.OneOf))
+		}
+	}
+	if m.Contains != nil && !contains(actual, m.Contains) {
+		return fmt.Sprintf("is %s, which does not contain %s", show(actual), show(m.Contains))
+	}
+	if m.pattern != nil && !m.pattern.MatchString(jsonpath.FormatValue(actual)) {
+		return fmt.Sprintf("is %s, which does not match /%s/", show(actual), m.Matches)
+	}
+	if m.Type != "" && typeOf(actual) != m.Type {
+		return fmt.Sprintf("is a %s, expected a %s", typeOf(actual), m.Type)
+	}
+	if m.Length != nil {
+		n, ok := length(actual)
+		if !ok {
+			return fmt.Sprintf("is a %s, which has no length", typeOf(actual))
+		}
+		if n != *m.Length {
+			return fmt.Sprintf("has length %d, expected %d", n, *m.Length)
+		}
+	}
+	for _, c := range []struct {
+		op   string
+		want any
+		ok   func(int) bool
+	}{
+		{">", m.GT, func(c int) bool { return c > 0 }},
+		{">=", m.GTE, func(c int) bool { return c >= 0 }},
+		{"<", m.LT, func(c int) bool { return c < 0 }},
+		{"<=", m.LTE, func(c int) bool { return c <= 0 }},
+	} {
+		if c.want == nil {
+			continue
+		}
+		got, want := actual, c.want
+		if loose {
+			got, want = numeric(got), numeric(want)
+		}
+		cmp, ok := jsonpath.Compare(got, want)
+		if !ok || !c.ok(cmp) {
+			return fmt.Sprintf("is %s, expected %s %s", show(actual), c.op, show(c.want))
+		}
+	}
+	return ""
+}
+
+// numeric turns text holding a number into that number.
+func numeric(v any) any {
+	if s, ok := v.(string); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// validateSchema lists where the body breaks the schema.
+func validateSchema(schema *jsonschema.Schema, body string) []string {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return []string{fmt.Sprintf("schema: response is not JSON: %v", err)}
+	}
+	err := schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []string{fmt.Sprintf("schema: %v", err)}
+	}
+	var failures []string
+	var walk func(*jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			at := e.InstanceLocation
+			if at == "" {
+				at = "/"
+			}
+			failures = append(failures, fmt.Sprintf("schema: %s %s", at, e.Message))
+		}
+		for _, c := range e.Causes {
+			walk(c)
+		}
+	}
+	walk(ve)
+	return failures
+}
+
+func contains(haystack, needle any) bool {
+	switch h := haystack.(type) {
+	case string:
+		s, ok := needle.(string)
+		return ok && strings.Contains(h, s)
+	case []any:
+		for _, v := range h {
+			if jsonpath.Equal(v, needle) {
+				return true
+			}
+		}
+	case map[string]any:
+		s, ok := needle.(string)
+		_, has := h[s]
+		return ok && has
+	}
+	return false
+}
+
+func length(v any) (int, bool) {
+	switch t := v.(type) {
+	case string:
+		return len([]rune(t)), true
+	case []any:
+		return len(t), true
+	case map[string]any:
+		return len(t), true
+	}
+	return 0, false
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// show renders a value compactly for failure messages.
+func show(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(data) > 120 {
+		return string(data[:117]) + "..."
+	}
+	return string(data)
+}
+
+func sortedKeys(m map[string]Matcher) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolvePath makes a path written in a collection relative to the collection's directory.
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) || strings.Contains(path, "://") {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
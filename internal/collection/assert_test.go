@@ -0,0 +1,145 @@
+package collection
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"gopkg.in/yaml.v3"
+)
+
+var testResponse = &requester.Response{
+	StatusCode: 201,
+	Duration:   120 * time.Millisecond,
+	Header: http.Header{
+		"Content-Type":   {"application/json"},
+		"Content-Length": {"120"},
+		"X-Tags":         {"a", "b"},
+	},
+	Body: `{"id": 42, "name": "Widget", "price": 9.5, "tags": ["new", "sale"], "owner": null,
+	        "stock": {"count": 3, "ok": true}, "items": [{"n": 1}, {"n": 2}]}`,
+}
+
+// parseAssert decodes an assert block written as in a collection file and compiles it.
+func parseAssert(t *testing.T, dir, src string) (*Assert, error) {
+	t.Helper()
+	var a Assert
+	if err := yaml.Unmarshal([]byte(src), &a); err != nil {
+		return nil, err
+	}
+	return &a, a.compile(dir)
+}
+
+func TestAssert(t *testing.T) {
+	vars := environment.Vars{"id": "42", "name": "Widget"}
+	tests := []struct {
+		name   string
+		assert string
+		want   []string // one substring per expected failure
+	}{
+		{"default status", `{}`, nil},
+		{"status code", `status: 201`, nil},
+		{"status class", `status: 2xx`, nil},
+		{"status list", `status: [200, 204]`, []string{"status 201, expected 200 or 204"}},
+		{"latency", `latency: 100ms`, []string{"over the 100ms latency limit"}},
+		{"contains", `contains: Widget`, nil},
+		{"contains missing", `contains: Gadget`, []string{`body does not contain "Gadget"`}},
+
+		{"equals", `body: {$.id: 42, $.name: Widget, .stock.ok: true, $.owner: null}`, nil},
+		{"equals mismatch", `body: {$.id: 43}`, []string{"body $.id is 42, expected 43"}},
+		{"equals object", `body: {$.stock: {equals: {count: 3, ok: true}}}`, nil},
+		{"missing", `body: {$.nope: 1}`, []string{"body $.nope is missing"}},
+		{"exists", `body: {$.name: {exists: true}, $.nope: {exists: false}}`, nil},
+		{"exists false", `body: {$.name: {exists: false}}`, []string{`body $.name exists ("Widget")`}},
+		{"not equals", `body: {$.name: {not_equals: Gadget}}`, nil},
+		{"not equals same", `body: {$.name: {not_equals: Widget}}`, []string{"expected anything else"}},
+		{"one of", `body: {$.name: {one_of: [Gadget, Widget]}}`, nil},
+		{"one of none", `body: {$.id: {one_of: [1, 2]}}`, []string{"expected one of [1,2]"}},
+		{"contains in string and array", `body: {$.name: {contains: idg}, $.tags: {contains: sale}, $.stock: {contains: count}}`, nil},
+		{"contains absent", `body: {$.tags: {contains: old}}`, []string{"does not contain \"old\""}},
+		{"matches", `body: {$.name: {matches: "^W\\w+$"}}`, nil},
+		{"matches not", `body: {$.name: {matches: "^G"}}`, []string{"does not match /^G/"}},
+		{"type", `body: {$.id: {type: number}, $.tags: {type: array}, $.owner: {type: "null"}, $.stock: {type: object}}`, nil},
+		{"type wrong", `body: {$.id: {type: string}}`, []string{"is a number, expected a string"}},
+		{"length", `body: {$.tags: {length: 2}, $.name: {length: 6}}`, nil},
+		{"length wrong", `body: {$.tags: {length: 3}}`, []string{"has length 2, expected 3"}},
+		{"length of number", `body: {$.id: {length: 1}}`, []string{"which has no length"}},
+		{"comparisons", `body: {$.price: {gt: 9, lt: 10}, $.id: {gte: 42, lte: 42}}`, nil},
+		{"comparison fails", `body: {$.price: {gt: 10}}`, []string{"is 9.5, expected > 10"}},
+		{"several values as an array", `body: {"$.items[*].n": [1, 2], ".items | length": 2}`, nil},
+		{"variables compare loosely", `body: {$.id: "{{id}}", $.name: "{{name}}"}`, nil},
+		{"undefined variable", `body: {$.id: "{{missing}}"}`, []string{"body $.id:"}},
+
+		{"header", `headers: {content-type: application/json}`, nil},
+		{"header joined", `headers: {x-tags: "a, b"}`, nil},
+		{"header loose number", `headers: {content-length: {gt: 100, equals: 120}}`, nil},
+		{"header missing", `headers: {x-missing: {exists: false}, etag: "x"}`, []string{"header Etag is missing"}},
+		{"header mismatch", `headers: {content-type: {matches: xml}}`, []string{"header Content-Type is \"application/json\", which does not match /xml/"}},
+
+		{"inline schema", `schema: {type: object, required: [id, name], properties: {id: {type: integer}}}`, nil},
+		{"inline schema fails", `schema: {type: object, required: [sku], properties: {name: {type: number}}}`, []string{"schema:", "schema: /name"}},
+
+		{"failures sorted", `{status: 200, body: {$.name: Gadget, $.id: 1}}`, []string{"status 201", "body $.id", "body $.name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := parseAssert(t, t.TempDir(), tt.assert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertFailures(t, a.check(testResponse, vars), tt.want)
+		})
+	}
+}
+
+func assertFailures(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("failures = %q, want %d matching %q", got, len(want), want)
+	}
+	for i := range want {
+		if !strings.Contains(got[i], want[i]) {
+			t.Errorf("failure %d = %q, want it to mention %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAssertSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{"type": "object", "properties": {"price": {"type": "number", "maximum": 5}}}`
+	if err := os.WriteFile(filepath.Join(dir, "item.json"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := parseAssert(t, dir, `schema: item.json`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFailures(t, a.check(testResponse, nil), []string{"schema: /price"})
+	assertFailures(t, a.check(&requester.Response{StatusCode: 200, Body: "<html>"}, nil), []string{"schema: response is not JSON"})
+}
+
+func TestAssertErrors(t *testing.T) {
+	tests := []struct {
+		assert, want string
+	}{
+		{`status: 600`, "invalid status"},
+		{`status: [200, ok]`, "invalid status"},
+		{`body: {$.id: {equal: 1}}`, `unknown assertion "equal"`},
+		{`body: {$.id: {type: integer}}`, `unknown type "integer"`},
+		{`body: {$.id: {matches: "("}}`, "invalid matches pattern"},
+		{`body: {"$.items[": 1}`, "invalid expression"},
+		{`schema: missing.json`, "invalid schema"},
+		{`schema: {type: 12}`, "invalid schema"},
+	}
+	for _, tt := range tests {
+		_, err := parseAssert(t, t.TempDir(), tt.assert)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want it to mention %q", tt.assert, err, tt.want)
+		}
+	}
+}
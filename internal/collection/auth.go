@@ -0,0 +1,87 @@
+package collection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/requester"
+)
+
+// AuthNone as a request's auth type sends it without credentials.
+const AuthNone = "none"
+
+func validateAuth(a *config.Auth) error {
+	if a == nil {
+		return nil
+	}
+	switch strings.ToLower(a.Type) {
+	case "", AuthNone, requester.AuthBasic, requester.AuthBearer, requester.AuthAPIKey, requester.AuthOAuth2:
+		return nil
+	}
+	return fmt.Errorf("unknown auth type %q (supported: basic, bearer, apikey, oauth2, none)", a.Type)
+}
+
+// authenticator returns the credentials of r: its own auth, else the collection's, else fallback
+// from the command's flags. Variables in the auth are replaced; like default headers, the
+// collection's auth is left off until its variables are set. Requests with the same credentials
+// share an authenticator, so OAuth2 tokens are fetched once per run.
+func (c *Collection) authenticator(r Request, vars environment.Vars, fallback requester.Authenticator, cache map[string]requester.Authenticator) (requester.Authenticator, error) {
+	a := r.Auth
+	if a == nil {
+		a = c.Defaults.Auth
+	}
+	if a == nil {
+		return fallback, nil
+	}
+	if strings.EqualFold(a.Type, AuthNone) {
+		return nil, nil
+	}
+
+	expanded := *a
+	expanded.Scopes = append([]string(nil), a.Scopes...)
+	fields := []*string{
+		&expanded.Username, &expanded.Password, &expanded.Token, &expanded.APIKeyName, &expanded.APIKeyValue,
+		&expanded.TokenURL, &expanded.ClientID, &expanded.ClientSecret, &expanded.RefreshToken,
+	}
+	for i := range expanded.Scopes {
+		fields = append(fields, &expanded.Scopes[i])
+	}
+	for _, field := range fields {
+		value, err := vars.Expand(*field)
+		if err != nil {
+			if r.Auth == nil && errors.Is(err, environment.ErrUndefined) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+		*field = value
+	}
+
+	key, _ := json.Marshal(expanded)
+	if auth, ok := cache[string(key)]; ok {
+		return auth, nil
+	}
+	auth, err := requester.NewAuthenticator(requester.AuthOptions{
+		Type:         expanded.Type,
+		Username:     expanded.Username,
+		Password:     expanded.Password,
+		Token:        expanded.Token,
+		APIKeyName:   expanded.APIKeyName,
+		APIKeyValue:  expanded.APIKeyValue,
+		APIKeyIn:     expanded.APIKeyIn,
+		TokenURL:     expanded.TokenURL,
+		ClientID:     expanded.ClientID,
+		ClientSecret: expanded.ClientSecret,
+		Scopes:       expanded.Scopes,
+		RefreshToken: expanded.RefreshToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	cache[string(key)] = auth
+	return auth, nil
+}
@@ -0,0 +1,103 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/jsonpath"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"gopkg.in/yaml.v3"
+)
+
+// Capture saves part of a response in a variable for later requests. It is written as a JSONPath
+// or jq-style expression (or header:Name), or as a mapping with one of json, header or regex.
+type Capture struct {
+	JSON   string `json:"json,omitempty" yaml:"json,omitempty"`
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+	// Regex is matched against the body; the first group is captured, or the whole match without one.
+	Regex string `json:"regex,omitempty" yaml:"regex,omitempty"`
+
+	expr    *jsonpath.Expr
+	pattern *regexp.Regexp
+}
+
+func (c *Capture) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = Capture{JSON: node.Value}
+		return nil
+	}
+	type plain Capture
+	return node.Decode((*plain)(c))
+}
+
+func (c *Capture) compile() error {
+	set := 0
+	for _, given := range []bool{c.JSON != "", c.Header != "", c.Regex != ""} {
+		if given {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("set exactly one of json, header and regex")
+	}
+	var err error
+	switch {
+	case c.JSON != "":
+		c.expr, err = jsonpath.Compile(c.JSON)
+	case c.Regex != "":
+		c.pattern, err = regexp.Compile(c.Regex)
+	}
+	return err
+}
+
+// extract returns the captured value. Selected strings are kept as is and other values as JSON;
+// several values are captured as a JSON array.
+func (c *Capture) extract(resp *requester.Response) (string, error) {
+	switch {
+	case c.Header != "":
+		values := resp.Header.Values(c.Header)
+		if len(values) == 0 {
+			return "", fmt.Errorf("no %s header", c.Header)
+		}
+		return strings.Join(values, ", "), nil
+	case c.pattern != nil:
+		m := c.pattern.FindStringSubmatch(resp.Body)
+		if m == nil {
+			return "", fmt.Errorf("/%s/ does not match the body", c.Regex)
+		}
+		if len(m) > 1 {
+			return m[1], nil
+		}
+		return m[0], nil
+	}
+	values, err := c.expr.Select(resp.Body, resp.Header)
+	if err != nil {
+		return "", err
+	}
+	switch len(values) {
+	case 0:
+		return "", fmt.Errorf("%s matched nothing", c.JSON)
+	case 1:
+		return compactValue(values[0]), nil
+	}
+	return compactValue(values), nil
+}
+
+// compactValue renders a captured value for use in a URL, header or body: strings as is,
+// everything else as single-line JSON.
+func compactValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,291 @@
+// Package collection loads and runs request collections: YAML or JSON files of named HTTP
+// requests with assertions on their responses, as used by `pingify test`.
+package collection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"gopkg.in/yaml.v3"
+)
+
+// Collection is a test file. JSON files are read the same way as YAML, of which JSON is a subset.
+type Collection struct {
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+
+	// Variables are defaults for {{name}} references; environment variables override them.
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+
+	Defaults Defaults  `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Requests []Request `json:"requests" yaml:"requests"`
+
+	// Setup runs before the requests and Teardown after them, even when something failed, e.g.
+	// to log in first and delete what the requests created.
+	Setup    []Request `json:"setup,omitempty" yaml:"setup,omitempty"`
+	Teardown []Request `json:"teardown,omitempty" yaml:"teardown,omitempty"`
+
+	// Path is the file the collection was loaded from; schema and body files are relative to it.
+	Path string `json:"-" yaml:"-"`
+}
+
+// Defaults apply to every request of the collection; request headers override default ones. A
+// default header is only sent once the variables it references are set.
+type Defaults struct {
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Timeout string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Auth applies to requests without their own, in place of the command's auth flags.
+	Auth *config.Auth `json:"auth,omitempty" yaml:"auth,omitempty"`
+}
+
+// Request is one named request and the assertions its response must pass. The URL, headers, body
+// and form fields may reference variables as {{name}}.
+type Request struct {
+	Name    string            `json:"name" yaml:"name"`
+	Method  string            `json:"method,omitempty" yaml:"method,omitempty"`
+	URL     string            `json:"url" yaml:"url"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Body is sent as is when it is a string (@file reads a file) and as JSON otherwise, so
+	// objects can be written inline. Form and URLEncoded work as in `pingify call`.
+	Body       any      `json:"body,omitempty" yaml:"body,omitempty"`
+	Form       []string `json:"form,omitempty" yaml:"form,omitempty"`
+	URLEncoded bool     `json:"urlencoded,omitempty" yaml:"urlencoded,omitempty"`
+
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Auth overrides the collection's auth; type "none" sends the request without credentials.
+	Auth *config.Auth `json:"auth,omitempty" yaml:"auth,omitempty"`
+
+	Assert Assert `json:"assert,omitempty" yaml:"assert,omitempty"`
+
+	// Capture saves values from the response in variables, by name, for the requests after it.
+	Capture map[string]Capture `json:"capture,omitempty" yaml:"capture,omitempty"`
+}
+
+// DefaultTimeout applies to requests when neither they nor the collection set one.
+const DefaultTimeout = "30s"
+
+// Load reads and validates a collection file.
+func Load(path string) (*Collection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+	var c Collection
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	c.Path = path
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Validate reports the first problem that would prevent the collection from running, and
+// compiles the assertions and captures.
+func (c *Collection) Validate() error {
+	if len(c.Requests) == 0 {
+		return errors.New("the collection has no requests")
+	}
+	if err := validateAuth(c.Defaults.Auth); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	for _, list := range [][]Request{c.Setup, c.Requests, c.Teardown} {
+		for i := range list {
+			if err := c.validate(&list[i], i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Collection) validate(r *Request, i int) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("request %d: name is required", i+1)
+	}
+	if r.URL == "" {
+		return fmt.Errorf("request %q: url is required", r.Name)
+	}
+	if r.Body != nil && len(r.Form) > 0 {
+		return fmt.Errorf("request %q: only one of body and form can be set", r.Name)
+	}
+	if r.URLEncoded && len(r.Form) == 0 {
+		return fmt.Errorf("request %q: urlencoded needs form fields", r.Name)
+	}
+	if err := validateAuth(r.Auth); err != nil {
+		return fmt.Errorf("request %q: %w", r.Name, err)
+	}
+	if err := r.Assert.compile(c.dir()); err != nil {
+		return fmt.Errorf("request %q: %w", r.Name, err)
+	}
+	for name, capture := range r.Capture {
+		if !environment.ValidName(name) {
+			return fmt.Errorf("request %q: %q is not a valid variable name", r.Name, name)
+		}
+		if err := capture.compile(); err != nil {
+			return fmt.Errorf("request %q: capture %s: %w", r.Name, name, err)
+		}
+		r.Capture[name] = capture
+	}
+	return nil
+}
+
+// dir is the directory relative paths in the collection are resolved against.
+func (c *Collection) dir() string {
+	if c.Path == "" {
+		return "."
+	}
+	return filepath.Dir(c.Path)
+}
+
+// resolve makes a path written in the collection relative to its file.
+func (c *Collection) resolve(path string) string {
+	if path == "-" {
+		return path
+	}
+	return resolvePath(c.dir(), path)
+}
+
+// url joins a relative request URL to base, the collection's base_url after its variables are
+// replaced.
+func url(base, rawURL string) string {
+	if base == "" || strings.Contains(rawURL, "://") {
+		return rawURL
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(rawURL, "/")
+}
+
+// headers merges the default headers with the request's into the JSON object requester expects.
+func (c *Collection) headers(r Request, vars environment.Vars) (string, error) {
+	if len(c.Defaults.Headers) == 0 && len(r.Headers) == 0 {
+		return "", nil
+	}
+	merged := map[string]string{}
+	for k, v := range c.Defaults.Headers {
+		// Default headers wait for the variables requests capture, e.g. an Authorization header
+		// using a token that a setup request captures is left off that setup request. Any other
+		// failure, such as a misspelled name, is reported.
+		if c.awaitsCapture(v, vars) {
+			continue
+		}
+		value, err := vars.Expand(v)
+		if err != nil {
+			return "", fmt.Errorf("default header %s: %w", k, err)
+		}
+		merged[k] = value
+	}
+	for k, v := range r.Headers {
+		value, err := vars.Expand(v)
+		if err != nil {
+			return "", fmt.Errorf("header %s: %w", k, err)
+		}
+		merged[k] = value
+	}
+	data, err := json.Marshal(merged)
+	return string(data), err
+}
+
+// awaitsCapture reports whether s references a variable that is not set yet but that a request
+// in the collection captures.
+func (c *Collection) awaitsCapture(s string, vars environment.Vars) bool {
+	for _, name := range environment.Names(s) {
+		if _, ok := vars[name]; ok {
+			continue
+		}
+		for _, list := range [][]Request{c.Setup, c.Requests, c.Teardown} {
+			for _, r := range list {
+				if _, ok := r.Capture[name]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// body returns where the request body comes from, with files resolved against the collection.
+func (c *Collection) body(r Request, vars environment.Vars) (requester.BodyOptions, error) {
+	opts := requester.BodyOptions{URLEncoded: r.URLEncoded}
+	for _, field := range r.Form {
+		field, err := vars.Expand(field)
+		if err != nil {
+			return opts, fmt.Errorf("form: %w", err)
+		}
+		if key, path, ok := strings.Cut(field, "=@"); ok {
+			field = key + "=@" + c.resolve(path)
+		}
+		opts.Form = append(opts.Form, field)
+	}
+	body, err := vars.ExpandValue(jsonValue(r.Body))
+	if err != nil {
+		return opts, fmt.Errorf("body: %w", err)
+	}
+	switch b := body.(type) {
+	case nil:
+	case string:
+		if path, ok := strings.CutPrefix(b, "@"); ok {
+			b = "@" + c.resolve(path)
+		}
+		opts.Data = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return opts, fmt.Errorf("body is not JSON: %w", err)
+		}
+		opts.Data = string(data)
+	}
+	return opts, nil
+}
+
+func (c *Collection) timeout(r Request) string {
+	switch {
+	case r.Timeout != "":
+		return r.Timeout
+	case c.Defaults.Timeout != "":
+		return c.Defaults.Timeout
+	}
+	return DefaultTimeout
+}
+
+// jsonValue converts YAML-decoded values for encoding/json and jsonpath: maps get string keys
+// and integers become float64. It returns a copy, leaving the collection as it was loaded.
+func jsonValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, x := range t {
+			m[k] = jsonValue(x)
+		}
+		return m
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, x := range t {
+			m[fmt.Sprint(k)] = jsonValue(x)
+		}
+		return m
+	case []any:
+		out := make([]any, len(t))
+		for i, x := range t {
+			out[i] = jsonValue(x)
+		}
+		return out
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package collection
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+// junitSuites is the JUnit XML layout read by CI systems (Jenkins, GitLab, GitHub Actions
+// reporters): one testsuite per collection and one testcase per request.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	File      string      `xml:"file,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results of one or more runs as a JUnit XML report. Failed assertions are
+// failures and requests that got no response are errors. Secret values are masked.
+func WriteJUnit(path string, suites []SuiteResult) error {
+	report := junitSuites{Name: "pingify"}
+	var total float64
+	for _, s := range suites {
+		js := junitSuite{
+			Name:      s.Name,
+			Time:      seconds(s.Duration.Seconds()),
+			Timestamp: s.Started.UTC().Format("2006-01-02T15:04:05"),
+			File:      s.Path,
+		}
+		for _, r := range s.Results {
+			jc := junitCase{Name: r.Label(), Classname: s.Name, Time: seconds(r.Duration.Seconds())}
+			switch {
+			case r.Skipped:
+				jc.Skipped = &junitMessage{Message: "skipped after an earlier failure"}
+				js.Skipped++
+			case r.Error != "":
+				jc.Error = &junitMessage{Message: r.Error, Text: fmt.Sprintf("%s %s\n%s", r.Method, r.URL, r.Error)}
+				js.Errors++
+			case len(r.Failures) > 0:
+				jc.Failure = &junitMessage{
+					Message: r.Failures[0],
+					Text:    fmt.Sprintf("%s %s -> HTTP %d\n%s", r.Method, r.URL, r.StatusCode, strings.Join(r.Failures, "\n")),
+				}
+				js.Failures++
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		js.Tests = len(js.Cases)
+		report.Tests += js.Tests
+		report.Failures += js.Failures
+		report.Errors += js.Errors
+		report.Skipped += js.Skipped
+		total += s.Duration.Seconds()
+		report.Suites = append(report.Suites, js)
+	}
+	report.Time = seconds(total)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(secret.MaskBytes(data), '\n')...), 0o644)
+}
+
+func seconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
@@ -0,0 +1,231 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/environment"
+	"github.com/Aditya251610/pingify/internal/requester"
+)
+
+// Options apply to every request of a run.
+type Options struct {
+	Auth   requester.Authenticator
+	Signer requester.Signer
+	TLS    *requester.TLSOptions
+	Net    *requester.NetOptions
+	HTTP   *requester.HTTPOptions
+
+	// Vars are the environment's variables and secrets, which override the collection's variables.
+	Vars environment.Vars
+
+	// Timeout, when set, overrides the timeouts written in the collection.
+	Timeout string
+
+	// Bail skips the remaining requests after the first failure. Teardown still runs.
+	Bail bool
+
+	// Progress, when set, is called with each result as soon as it is known.
+	Progress func(Result)
+}
+
+// Result is the outcome of one request.
+type Result struct {
+	// Stage is "setup" or "teardown" for those requests and empty for the others.
+	Stage      string
+	Name       string
+	Method     string
+	URL        string
+	StatusCode int
+	Duration   time.Duration
+
+	// Failures lists the assertions that did not hold; Error is set when no response was received.
+	Failures []string
+	Error    string
+
+	// Skipped is set for requests not sent because setup failed, or an earlier request failed
+	// with Bail.
+	Skipped bool
+}
+
+// Passed reports whether the request was sent and every assertion held.
+func (r Result) Passed() bool {
+	return !r.Skipped && r.Error == "" && len(r.Failures) == 0
+}
+
+// Label is the request's name, prefixed with its stage for setup and teardown requests.
+func (r Result) Label() string {
+	if r.Stage == "" {
+		return r.Name
+	}
+	return r.Stage + ": " + r.Name
+}
+
+// SuiteResult is the outcome of running a collection.
+type SuiteResult struct {
+	Name     string
+	Path     string
+	Started  time.Time
+	Duration time.Duration
+	Results  []Result
+}
+
+// Counts returns how many requests passed, failed and were skipped.
+func (s SuiteResult) Counts() (passed, failed, skipped int) {
+	for _, r := range s.Results {
+		switch {
+		case r.Skipped:
+			skipped++
+		case r.Passed():
+			passed++
+		default:
+			failed++
+		}
+	}
+	return passed, failed, skipped
+}
+
+// Run sends the setup requests, the requests and the teardown requests in order and checks their
+// assertions. Values captured from a response are available to every request after it.
+func Run(c *Collection, opts Options) SuiteResult {
+	suite := SuiteResult{Name: c.Name, Path: c.Path, Started: time.Now()}
+	if suite.Name == "" {
+		suite.Name = c.Path
+	}
+	vars := environment.Vars(c.Variables).Merge(opts.Vars)
+	auths := map[string]requester.Authenticator{}
+	report := func(result Result) {
+		suite.Results = append(suite.Results, result)
+		if opts.Progress != nil {
+			opts.Progress(result)
+		}
+	}
+
+	setupFailed := false
+	for _, r := range c.Setup {
+		if setupFailed {
+			report(c.skip(r, "setup"))
+			continue
+		}
+		result := c.send(r, "setup", opts, vars, auths)
+		setupFailed = !result.Passed()
+		report(result)
+	}
+	failed := false
+	for _, r := range c.Requests {
+		if setupFailed || (failed && opts.Bail) {
+			report(c.skip(r, ""))
+			continue
+		}
+		result := c.send(r, "", opts, vars, auths)
+		failed = failed || !result.Passed()
+		report(result)
+	}
+	for _, r := range c.Teardown {
+		report(c.send(r, "teardown", opts, vars, auths))
+	}
+	suite.Duration = time.Since(suite.Started)
+	return suite
+}
+
+func (c *Collection) skip(r Request, stage string) Result {
+	return Result{Stage: stage, Name: r.Name, Method: method(r), URL: url(c.BaseURL, r.URL), Skipped: true}
+}
+
+// send makes one request, checks its response and captures values from it into vars. Teardown
+// requests that need a variable no earlier request captured are skipped, as there is nothing to
+// clean up.
+func (c *Collection) send(r Request, stage string, opts Options, vars environment.Vars, auths map[string]requester.Authenticator) Result {
+	result := Result{Stage: stage, Name: r.Name, Method: method(r), URL: url(c.BaseURL, r.URL)}
+	headers, body, contentType, err := c.prepare(r, vars, &result)
+	auth := opts.Auth
+	if err == nil {
+		auth, err = c.authenticator(r, vars, opts.Auth, auths)
+	}
+	if err != nil {
+		if stage == "teardown" && errors.Is(err, environment.ErrUndefined) {
+			result.Skipped = true
+			return result
+		}
+		result.Error = err.Error()
+		return result
+	}
+	timeout := c.timeout(r)
+	if opts.Timeout != "" {
+		timeout = opts.Timeout
+	}
+
+	resp, err := requester.Do(requester.Request{
+		URL:         result.URL,
+		Method:      result.Method,
+		Headers:     headers,
+		Body:        body,
+		ContentType: contentType,
+		Timeout:     timeout,
+		Auth:        auth,
+		Signer:      opts.Signer,
+		TLS:         opts.TLS,
+		Net:         opts.Net,
+		HTTP:        opts.HTTP,
+	})
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+		result.Duration = resp.Duration
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Failures = r.Assert.check(resp, vars)
+	for _, name := range sortedCaptures(r.Capture) {
+		capture := r.Capture[name]
+		value, err := capture.extract(resp)
+		if err != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("capture %s: %v", name, err))
+			continue
+		}
+		vars[name] = value
+	}
+	return result
+}
+
+func method(r Request) string {
+	if r.Method == "" {
+		return "GET"
+	}
+	return strings.ToUpper(r.Method)
+}
+
+// prepare replaces the variables in the request, setting result.URL, and loads its body.
+func (c *Collection) prepare(r Request, vars environment.Vars, result *Result) (headers, body, contentType string, err error) {
+	base, err := vars.Expand(c.BaseURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("base_url: %w", err)
+	}
+	rawURL, err := vars.Expand(r.URL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("url: %w", err)
+	}
+	result.URL = url(base, rawURL)
+	if headers, err = c.headers(r, vars); err != nil {
+		return "", "", "", err
+	}
+	bodyOpts, err := c.body(r, vars)
+	if err != nil {
+		return "", "", "", err
+	}
+	body, contentType, err = bodyOpts.Load()
+	return headers, body, contentType, err
+}
+
+func sortedCaptures(m map[string]Capture) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,241 @@
+package collection
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/environment"
+)
+
+// itemsAPI is a small API behind a login: POST /login returns a token, POST /items creates an item
+// and DELETE /items/{id} removes it. It records every request as "METHOD path".
+func itemsAPI(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/login" {
+			io.WriteString(w, `{"token": "t0k"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0k" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/items":
+			var item map[string]any
+			json.NewDecoder(r.Body).Decode(&item)
+			item["id"] = 7
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(item)
+		case r.Method == http.MethodDelete && r.URL.Path == "/items/7":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func writeCollection(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const itemsCollection = `
+name: items
+base_url: "{{base}}"
+variables:
+  item: Widget
+defaults:
+  headers:
+    Authorization: "Bearer {{token}}"
+setup:
+  - name: login
+    method: POST
+    url: /login
+    capture:
+      token: $.token
+requests:
+  - name: create
+    method: POST
+    url: /items
+    body: {name: "{{item}}", price: 3}
+    assert:
+      status: 201
+      headers: {content-type: application/json}
+      body:
+        $.name: "{{item}}"
+        $.price: {lt: 5}
+    capture:
+      id: $.id
+  - name: missing
+    url: /items/404
+    assert:
+      status: 200
+  - name: after failure
+    url: /items/7
+    assert:
+      status: 404
+teardown:
+  - name: delete
+    method: DELETE
+    url: /items/{{id}}
+    assert:
+      status: 204
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		bail    bool
+		results []string // label and outcome of each request
+	}{
+		{"all requests", false, []string{"setup: login pass", "create pass", "missing fail", "after failure pass", "teardown: delete pass"}},
+		{"bail", true, []string{"setup: login pass", "create pass", "missing fail", "after failure skip", "teardown: delete pass"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, seen := itemsAPI(t)
+			c, err := Load(writeCollection(t, itemsCollection))
+			if err != nil {
+				t.Fatal(err)
+			}
+			suite := Run(c, Options{Vars: environment.Vars{"base": srv.URL}, Bail: tt.bail})
+
+			var got []string
+			for _, r := range suite.Results {
+				outcome := "pass"
+				switch {
+				case r.Skipped:
+					outcome = "skip"
+				case !r.Passed():
+					outcome = "fail"
+				}
+				got = append(got, r.Label()+" "+outcome)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.results, ", ") {
+				t.Errorf("results = %q, want %q", got, tt.results)
+			}
+			if passed, failed, skipped := suite.Counts(); passed+failed+skipped != len(tt.results) || failed != 1 {
+				t.Errorf("counts = %d passed, %d failed, %d skipped", passed, failed, skipped)
+			}
+			if calls := seen(); calls[len(calls)-1] != "DELETE /items/7" {
+				t.Errorf("last call = %q, want the captured id to be deleted", calls[len(calls)-1])
+			}
+		})
+	}
+}
+
+func TestRunSetupFailure(t *testing.T) {
+	srv, seen := itemsAPI(t)
+	src := strings.Replace(itemsCollection, "url: /login", "url: /login\n    assert:\n      status: 500", 1)
+	c, err := Load(writeCollection(t, src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite := Run(c, Options{Vars: environment.Vars{"base": srv.URL}})
+	if passed, failed, skipped := suite.Counts(); passed != 0 || failed != 1 || skipped != 4 {
+		t.Errorf("counts = %d passed, %d failed, %d skipped, want 0, 1, 4", passed, failed, skipped)
+	}
+	// The teardown needs an id that was never captured, so nothing is deleted.
+	if calls := seen(); len(calls) != 1 {
+		t.Errorf("calls = %q, want only the login", calls)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{`name: empty`, "no requests"},
+		{`requests: [{url: /a}]`, "request 1: name is required"},
+		{`requests: [{name: a}]`, `request "a": url is required`},
+		{`requests: [{name: a, url: /a, body: x, form: [a=b]}]`, "only one of body and form"},
+		{`requests: [{name: a, url: /a, urlencoded: true}]`, "urlencoded needs form fields"},
+		{`requests: [{name: a, url: /a, assert: {status: abc}}]`, "invalid status"},
+		{`requests: [{name: a, url: /a, capture: {"1x": $.id}}]`, "not a valid variable name"},
+		{`requests: [{name: a, url: /a, capture: {id: {json: $.id, header: X-Id}}}]`, "exactly one of"},
+		{`requests: [`, "failed to parse"},
+	}
+	for _, tt := range tests {
+		_, err := Load(writeCollection(t, tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want it to mention %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestRunDefaultHeaderErrors(t *testing.T) {
+	srv, seen := itemsAPI(t)
+	src := strings.Replace(itemsCollection, "Bearer {{token}}", "Bearer {{tokn}}", 1)
+	c, err := Load(writeCollection(t, src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite := Run(c, Options{Vars: environment.Vars{"base": srv.URL}})
+	login := suite.Results[0]
+	if !strings.Contains(login.Error, "default header Authorization") || !strings.Contains(login.Error, "{{tokn}}") {
+		t.Errorf("login error = %q, want the misspelled default header reported", login.Error)
+	}
+	if calls := seen(); len(calls) != 0 {
+		t.Errorf("calls = %q, want none sent without the header", calls)
+	}
+}
+
+func TestRunTwice(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	c, err := Load(writeCollection(t, `
+base_url: "{{base}}"
+requests:
+  - name: create
+    method: POST
+    url: /items
+    body: {id: "{{$randomInt 1 1000000}}", tags: ["{{name}}"], count: 2}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if suite := Run(c, Options{Vars: environment.Vars{"base": srv.URL, "name": name}}); suite.Results[0].Error != "" {
+			t.Fatal(suite.Results[0].Error)
+		}
+	}
+	if len(bodies) != 2 || !strings.Contains(bodies[0], `"tags":["a"]`) || !strings.Contains(bodies[1], `"tags":["b"]`) {
+		t.Errorf("bodies = %q, want each run to expand the collection's own body", bodies)
+	}
+	body := c.Requests[0].Body.(map[string]any)
+	if tags := body["tags"].([]any); tags[0] != "{{name}}" || body["count"] != 2 {
+		t.Errorf("the collection's body was modified: %#v", body)
+	}
+}
@@ -0,0 +1,561 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the on-disk definition of the checks run by `pingify serve`.
+// Files ending in .json are read and written as JSON; everything else is YAML.
+type Config struct {
+	Checks     []Check     `json:"checks" yaml:"checks"`
+	Heartbeats []Heartbeat `json:"heartbeats,omitempty" yaml:"heartbeats,omitempty"`
+	Status     *StatusPage `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Heartbeat is a push check: a job pings /ping/<token> on the daemon at least once per Period,
+// and the heartbeat is considered down once Period plus Grace passes without a ping.
+type Heartbeat struct {
+	Name   string   `json:"name" yaml:"name"`
+	Token  string   `json:"token" yaml:"token"`
+	Period Duration `json:"period" yaml:"period"`
+	Grace  Duration `json:"grace,omitempty" yaml:"grace,omitempty"`
+	Alert  bool     `json:"alert,omitempty" yaml:"alert,omitempty"`
+	Email  string   `json:"email,omitempty" yaml:"email,omitempty"`
+}
+
+// LogURL is the key heartbeat runs are logged under, e.g. for `pingify report --url heartbeat://backup`.
+func (h Heartbeat) LogURL() string {
+	return "heartbeat://" + h.Name
+}
+
+// Validate reports the first problem that would prevent the heartbeat from being tracked.
+func (h Heartbeat) Validate() error {
+	if strings.TrimSpace(h.Name) == "" {
+		return errors.New("heartbeat name is required")
+	}
+	if h.Token == "" || strings.Contains(h.Token, "/") {
+		return fmt.Errorf("heartbeat %q: token is required and must not contain '/'", h.Name)
+	}
+	if h.Period <= 0 || h.Grace < 0 {
+		return fmt.Errorf("heartbeat %q: period must be positive", h.Name)
+	}
+	return nil
+}
+
+// LogKey returns the key the named check or heartbeat is logged under.
+func (c *Config) LogKey(name string) (string, bool) {
+	if i := c.Find(name); i >= 0 {
+		return c.Checks[i].LogKey(), true
+	}
+	for _, hb := range c.Heartbeats {
+		if hb.Name == name {
+			return hb.LogURL(), true
+		}
+	}
+	return "", false
+}
+
+// StatusPage configures the public status page. Without components every check is shown on its own.
+type StatusPage struct {
+	Title      string      `json:"title,omitempty" yaml:"title,omitempty"`
+	Components []Component `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// Component groups checks under one name on the status page.
+type Component struct {
+	Name   string   `json:"name" yaml:"name"`
+	Checks []string `json:"checks" yaml:"checks"`
+}
+
+// Built-in check types. An empty type means TypeHTTP.
+const (
+	TypeHTTP    = "http"
+	TypeTLS     = "tls"
+	TypeTCP     = "tcp"
+	TypeUDP     = "udp"
+	TypeDNS     = "dns"
+	TypeGRPC    = "grpc"
+	TypeWS      = "ws"
+	TypeStream  = "stream"
+	TypeGraphQL = "graphql"
+
+	// TypeTransaction checks run the request collection file at URL (see `pingify test`) as the
+	// steps of one synthetic transaction; Threshold applies to the total time of the steps.
+	TypeTransaction = "transaction"
+)
+
+// Check is a single monitored target.
+type Check struct {
+	Name      string   `json:"name" yaml:"name"`
+	Type      string   `json:"type,omitempty" yaml:"type,omitempty"`
+	URL       string   `json:"url" yaml:"url"`
+	Method    string   `json:"method,omitempty" yaml:"method,omitempty"`
+	Headers   string   `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body      string   `json:"body,omitempty" yaml:"body,omitempty"`
+	Timeout   string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Interval  Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Threshold Duration `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Alert     bool     `json:"alert,omitempty" yaml:"alert,omitempty"`
+	Email     string   `json:"email,omitempty" yaml:"email,omitempty"`
+	Paused    bool     `json:"paused,omitempty" yaml:"paused,omitempty"`
+	Retry     *Retry   `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Auth      *Auth    `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Sign      *Sign    `json:"sign,omitempty" yaml:"sign,omitempty"`
+	TLS       *TLS     `json:"tls,omitempty" yaml:"tls,omitempty"`
+	Network   *Network `json:"network,omitempty" yaml:"network,omitempty"`
+	HTTP      *HTTP    `json:"http,omitempty" yaml:"http,omitempty"`
+
+	// FailAfter is how many runs in a row must fail before the daemon counts the check as failing,
+	// alerts and opens an incident on the status page. The default of 1 reacts to the first failure.
+	FailAfter int `json:"fail_after,omitempty" yaml:"fail_after,omitempty"`
+
+	// WarnDays fails TLS checks whose certificate expires within this many days.
+	WarnDays int `json:"warn_days,omitempty" yaml:"warn_days,omitempty"`
+
+	// DataBinary and Form are alternatives to Body, sent as raw bytes or multipart/form-data
+	// (URL-encoded with URLEncoded). Body and DataBinary accept @file to read the body from a file.
+	DataBinary string   `json:"data_binary,omitempty" yaml:"data_binary,omitempty"`
+	Form       []string `json:"form,omitempty" yaml:"form,omitempty"`
+	URLEncoded bool     `json:"urlencoded,omitempty" yaml:"urlencoded,omitempty"`
+
+	// Select is a JSONPath or jq-style expression (or header:Name) evaluated on HTTP responses;
+	// the check fails when it matches nothing, or when Expect is set and the result lacks it.
+	Select string `json:"select,omitempty" yaml:"select,omitempty"`
+
+	// MaxBody caps how much of each response is read, e.g. "512KB"; monitors default to 10MB.
+	MaxBody string `json:"max_body,omitempty" yaml:"max_body,omitempty"`
+
+	// HAROnFailure appends the request and response of every failed HTTP or GraphQL check to this HAR file.
+	HAROnFailure string `json:"har_on_failure,omitempty" yaml:"har_on_failure,omitempty"`
+
+	// Send is written after connecting for TCP, UDP and WebSocket checks; Expect must appear in what is read back.
+	Send   string `json:"send,omitempty" yaml:"send,omitempty"`
+	Expect string `json:"expect,omitempty" yaml:"expect,omitempty"`
+
+	// RecordType, Resolver and Answers configure DNS checks, whose URL is the name to query.
+	RecordType string   `json:"record_type,omitempty" yaml:"record_type,omitempty"`
+	Resolver   string   `json:"resolver,omitempty" yaml:"resolver,omitempty"`
+	Answers    []string `json:"answers,omitempty" yaml:"answers,omitempty"`
+
+	// GRPCMethod ("pkg.Service/Method") makes a gRPC check call a unary method with Body as JSON;
+	// without it the check queries grpc.health.v1 for GRPCService. Headers are sent as metadata.
+	GRPCMethod  string `json:"grpc_method,omitempty" yaml:"grpc_method,omitempty"`
+	GRPCService string `json:"grpc_service,omitempty" yaml:"grpc_service,omitempty"`
+	Protoset    string `json:"protoset,omitempty" yaml:"protoset,omitempty"`
+	Plaintext   bool   `json:"plaintext,omitempty" yaml:"plaintext,omitempty"`
+	ExpectCode  string `json:"expect_code,omitempty" yaml:"expect_code,omitempty"`
+
+	// MaxEvents stops stream checks after this many events; the stream is otherwise read until
+	// it ends or Timeout passes. Expect must appear in at least one event.
+	MaxEvents int `json:"max_events,omitempty" yaml:"max_events,omitempty"`
+
+	// Query (or QueryFile, re-read on every run) is the GraphQL document for graphql checks,
+	// sent with the Variables JSON object and Operation name. Any errors in the response fail the check.
+	Query     string `json:"query,omitempty" yaml:"query,omitempty"`
+	QueryFile string `json:"query_file,omitempty" yaml:"query_file,omitempty"`
+	Variables string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
+
+	// Environment names the environment (see `pingify --env`) whose variables and secrets the
+	// collection of a transaction check uses.
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+}
+
+// Retry makes HTTP and GraphQL checks retry transient failures before counting the check as failed.
+type Retry struct {
+	// Attempts is the total number of attempts, including the first.
+	Attempts   int      `json:"attempts" yaml:"attempts"`
+	Backoff    Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	MaxBackoff Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+	// OnStatus lists the retried status codes; empty means 408, 425, 429, 500, 502, 503 and 504.
+	OnStatus []int `json:"on_status,omitempty" yaml:"on_status,omitempty"`
+}
+
+// Auth holds the credentials of HTTP, GraphQL and stream checks. Secrets can be written as
+// env:NAME or file:path instead of literally.
+type Auth struct {
+	Type string `json:"type,omitempty" yaml:"type,omitempty"` // basic, bearer, apikey or oauth2
+
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+
+	APIKeyName  string `json:"api_key_name,omitempty" yaml:"api_key_name,omitempty"`
+	APIKeyValue string `json:"api_key_value,omitempty" yaml:"api_key_value,omitempty"`
+	APIKeyIn    string `json:"api_key_in,omitempty" yaml:"api_key_in,omitempty"`
+
+	TokenURL     string   `json:"token_url,omitempty" yaml:"token_url,omitempty"`
+	ClientID     string   `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty" yaml:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	RefreshToken string   `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty"`
+}
+
+// Sign signs HTTP, GraphQL and stream checks afresh on every run.
+type Sign struct {
+	Type string `json:"type" yaml:"type"` // aws-sigv4 or hmac
+
+	Region  string `json:"region,omitempty" yaml:"region,omitempty"`
+	Service string `json:"service,omitempty" yaml:"service,omitempty"`
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	Secret          string   `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Header          string   `json:"header,omitempty" yaml:"header,omitempty"`
+	Algorithm       string   `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	Encoding        string   `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Prefix          string   `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	TimestampHeader string   `json:"timestamp_header,omitempty" yaml:"timestamp_header,omitempty"`
+	Parts           []string `json:"parts,omitempty" yaml:"parts,omitempty"`
+	Separator       string   `json:"separator,omitempty" yaml:"separator,omitempty"`
+}
+
+// TLS configures the TLS client of HTTP, GraphQL, stream, gRPC and WebSocket checks; tls checks use
+// only CA and ServerName. PKCS12Password may be env:NAME or file:path.
+type TLS struct {
+	Cert           string   `json:"cert,omitempty" yaml:"cert,omitempty"`
+	Key            string   `json:"key,omitempty" yaml:"key,omitempty"`
+	PKCS12         string   `json:"pkcs12,omitempty" yaml:"pkcs12,omitempty"`
+	PKCS12Password string   `json:"pkcs12_password,omitempty" yaml:"pkcs12_password,omitempty"`
+	CA             string   `json:"ca,omitempty" yaml:"ca,omitempty"`
+	ServerName     string   `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+	MinVersion     string   `json:"min_version,omitempty" yaml:"min_version,omitempty"`
+	MaxVersion     string   `json:"max_version,omitempty" yaml:"max_version,omitempty"`
+	Ciphers        []string `json:"ciphers,omitempty" yaml:"ciphers,omitempty"`
+	// Insecure disables certificate verification; every logged result is marked insecure.
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+}
+
+// Network routes the connections of HTTP, GraphQL, stream, gRPC and WebSocket checks.
+// Resolve and ConnectTo use curl's host:port:addr and host:port:connect-host:connect-port forms.
+type Network struct {
+	Proxy        string   `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	NoProxy      string   `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"`
+	Resolve      []string `json:"resolve,omitempty" yaml:"resolve,omitempty"`
+	ConnectTo    []string `json:"connect_to,omitempty" yaml:"connect_to,omitempty"`
+	IPVersion    int      `json:"ip_version,omitempty" yaml:"ip_version,omitempty"`
+	LocalAddress string   `json:"local_address,omitempty" yaml:"local_address,omitempty"`
+	UnixSocket   string   `json:"unix_socket,omitempty" yaml:"unix_socket,omitempty"`
+}
+
+// HTTP controls redirects, cookies and the protocol version of HTTP, GraphQL and stream checks.
+// CookieJar is a Netscape cookie file that is loaded once and rewritten after every run.
+type HTTP struct {
+	NoRedirects  bool   `json:"no_redirects,omitempty" yaml:"no_redirects,omitempty"`
+	MaxRedirects int    `json:"max_redirects,omitempty" yaml:"max_redirects,omitempty"`
+	CookieJar    string `json:"cookie_jar,omitempty" yaml:"cookie_jar,omitempty"`
+	Version      string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// Defaults used when a check leaves the field empty; they match the monitor command's flag defaults.
+const (
+	DefaultInterval  = 10 * time.Second
+	DefaultThreshold = 500 * time.Millisecond
+	DefaultTimeout   = "5s"
+	DefaultWarnDays  = 14
+)
+
+// WithDefaults returns a copy of the check with empty fields filled in.
+func (c Check) WithDefaults() Check {
+	if c.Type == "" {
+		c.Type = TypeHTTP
+	}
+	if c.Type == TypeTLS && c.WarnDays == 0 {
+		c.WarnDays = DefaultWarnDays
+	}
+	if c.Type == TypeDNS && c.RecordType == "" {
+		c.RecordType = "A"
+	}
+	if c.Method == "" {
+		c.Method = "GET"
+	}
+	if c.Interval == 0 {
+		c.Interval = Duration(DefaultInterval)
+	}
+	// A slow handshake says nothing about a certificate, so TLS checks only get a threshold when one is set.
+	if c.Threshold == 0 && c.Type != TypeTLS {
+		c.Threshold = Duration(DefaultThreshold)
+	}
+	if c.Timeout == "" {
+		c.Timeout = DefaultTimeout
+	}
+	if c.FailAfter == 0 {
+		c.FailAfter = 1
+	}
+	return c
+}
+
+// Redacted is the masked value that Check.Redacted puts in place of literal credentials.
+const Redacted = "****"
+
+// sensitiveHeaders are header name fragments whose values Check.Redacted hides.
+var sensitiveHeaders = []string{"authorization", "cookie", "token", "secret", "password", "key"}
+
+// Redacted returns a copy of the check that is safe to show over the API: literal passwords, tokens,
+// secrets and credential headers are replaced by Redacted. env:NAME and file:path references are kept,
+// as they only say where the secret is.
+func (c Check) Redacted() Check {
+	hide := func(s *string) {
+		if *s != "" && !strings.HasPrefix(*s, "env:") && !strings.HasPrefix(*s, "file:") {
+			*s = Redacted
+		}
+	}
+	if c.Auth != nil {
+		auth := *c.Auth
+		for _, s := range []*string{&auth.Password, &auth.Token, &auth.APIKeyValue, &auth.ClientSecret, &auth.RefreshToken} {
+			hide(s)
+		}
+		c.Auth = &auth
+	}
+	if c.Sign != nil {
+		sign := *c.Sign
+		hide(&sign.Secret)
+		c.Sign = &sign
+	}
+	if c.TLS != nil {
+		tls := *c.TLS
+		hide(&tls.PKCS12Password)
+		c.TLS = &tls
+	}
+	if c.Network != nil {
+		network := *c.Network
+		network.Proxy = redactURL(network.Proxy)
+		c.Network = &network
+	}
+	c.URL = redactURL(c.URL)
+	c.Headers = redactHeaders(c.Headers)
+	return c
+}
+
+// redactURL hides the password of a URL with user info.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), Redacted)
+	}
+	return u.String()
+}
+
+// redactHeaders hides the values of credential headers in a JSON headers object.
+func redactHeaders(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		return raw
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(raw), &headers); err != nil {
+		return Redacted
+	}
+	for name := range headers {
+		lower := strings.ToLower(name)
+		for _, s := range sensitiveHeaders {
+			if strings.Contains(lower, s) {
+				headers[name] = Redacted
+				break
+			}
+		}
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return Redacted
+	}
+	return string(data)
+}
+
+// LogKey is the key the check's runs are logged under, e.g. for `pingify report --check name`.
+// Checks named after their URL, like the ones `pingify monitor` runs, keep logging under the URL
+// so `pingify report --url` finds them.
+func (c Check) LogKey() string {
+	if c.Name == "" || c.Name == c.URL {
+		return c.URL
+	}
+	return "check://" + c.Name
+}
+
+// Validate reports the first problem that would prevent the check from running.
+func (c Check) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("check name is required")
+	}
+	if c.URL == "" {
+		return fmt.Errorf("check %q: url is required", c.Name)
+	}
+	switch c.Type {
+	case TypeTCP, TypeUDP:
+		if _, _, err := net.SplitHostPort(HostPort(c.URL)); err != nil {
+			return fmt.Errorf("check %q: %s checks need a host:port target", c.Name, c.Type)
+		}
+		if c.Type == TypeUDP && c.Send == "" {
+			return fmt.Errorf("check %q: udp checks need a payload to send", c.Name)
+		}
+	case TypeDNS:
+		switch strings.ToUpper(c.RecordType) {
+		case "", "A", "AAAA", "CNAME", "MX", "NS", "TXT":
+		default:
+			return fmt.Errorf("check %q: unsupported DNS record type %q", c.Name, c.RecordType)
+		}
+	case TypeGraphQL:
+		if strings.TrimSpace(c.Query) == "" && c.QueryFile == "" {
+			return fmt.Errorf("check %q: graphql checks need a query or query_file", c.Name)
+		}
+	case TypeGRPC, TypeWS:
+		if c.Auth != nil || c.Sign != nil {
+			return fmt.Errorf("check %q: auth and sign don't apply to %s checks; send credentials in headers", c.Name, c.Type)
+		}
+	}
+	if n := c.Network; n != nil && n.IPVersion != 0 && n.IPVersion != 4 && n.IPVersion != 6 {
+		return fmt.Errorf("check %q: ip_version must be 4 or 6", c.Name)
+	}
+	bodies := 0
+	for _, given := range []bool{c.Body != "", c.DataBinary != "", len(c.Form) > 0} {
+		if given {
+			bodies++
+		}
+	}
+	if bodies > 1 {
+		return fmt.Errorf("check %q: only one of body, data_binary and form can be set", c.Name)
+	}
+	if c.URLEncoded && len(c.Form) == 0 {
+		return fmt.Errorf("check %q: urlencoded needs form fields", c.Name)
+	}
+	if h := c.HTTP; h != nil {
+		switch h.Version {
+		case "", "1.1", "2", "h2c":
+		default:
+			return fmt.Errorf("check %q: http version must be 1.1, 2 or h2c", c.Name)
+		}
+		if h.MaxRedirects < 0 {
+			return fmt.Errorf("check %q: max_redirects must not be negative", c.Name)
+		}
+	}
+	if c.FailAfter < 0 {
+		return fmt.Errorf("check %q: fail_after must not be negative", c.Name)
+	}
+	if r := c.Retry; r != nil && (r.Attempts < 0 || r.Backoff < 0 || r.MaxBackoff < 0) {
+		return fmt.Errorf("check %q: retry settings must not be negative", c.Name)
+	}
+	if c.Interval < 0 || c.Threshold < 0 {
+		return fmt.Errorf("check %q: durations must be positive", c.Name)
+	}
+	return nil
+}
+
+// HostPort strips a scheme such as tcp:// or udp:// from a target.
+func HostPort(target string) string {
+	if i := strings.Index(target, "://"); i >= 0 {
+		return target[i+3:]
+	}
+	return target
+}
+
+// Find returns the index of the named check, or -1.
+func (c *Config) Find(name string) int {
+	for i, check := range c.Checks {
+		if check.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate checks every entry and rejects duplicate names.
+func (c *Config) Validate() error {
+	seen := map[string]bool{}
+	for _, check := range c.Checks {
+		if err := check.Validate(); err != nil {
+			return err
+		}
+		if seen[check.Name] {
+			return fmt.Errorf("duplicate check name %q", check.Name)
+		}
+		seen[check.Name] = true
+	}
+
+	tokens := map[string]bool{}
+	for _, hb := range c.Heartbeats {
+		if err := hb.Validate(); err != nil {
+			return err
+		}
+		if seen[hb.Name] {
+			return fmt.Errorf("duplicate check name %q", hb.Name)
+		}
+		if tokens[hb.Token] {
+			return fmt.Errorf("heartbeat %q: token is already used by another heartbeat", hb.Name)
+		}
+		seen[hb.Name] = true
+		tokens[hb.Token] = true
+	}
+
+	if c.Status != nil {
+		for _, component := range c.Status.Components {
+			if component.Name == "" {
+				return errors.New("status component name is required")
+			}
+			for _, name := range component.Checks {
+				if !seen[name] {
+					return fmt.Errorf("status component %q references unknown check %q", component.Name, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// Load reads and validates the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if isJSON(path) {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save writes the config to path, replacing the file atomically.
+func Save(path string, cfg *Config) error {
+	var data []byte
+	var err error
+	if isJSON(path) {
+		data, err = json.MarshalIndent(cfg, "", "  ")
+	} else {
+		data, err = yaml.Marshal(cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,275 @@
+// Package curl converts between curl command lines and Pingify requests.
+package curl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Header is one request header; Secret marks values written as env:NAME or file:path.
+type Header struct {
+	Name   string
+	Value  string
+	Secret bool
+}
+
+// Request is the part of a Pingify call that has a curl equivalent. Body is a JSON body and
+// Data raw bytes; both may be @file. Password, Bearer and PKCS12Password may use Pingify's
+// env:NAME and file:path forms.
+type Request struct {
+	URL     string
+	Method  string
+	Headers []Header
+
+	Body       string
+	Data       string
+	Form       []string
+	URLEncoded bool
+
+	User     string // user:password
+	Bearer   string
+	Insecure bool
+
+	NoFollow     bool
+	MaxRedirects int
+	CookieJar    string
+	HTTPVersion  string
+	Timeout      string // Go duration
+
+	Proxy      string
+	NoProxy    string
+	Resolve    []string
+	ConnectTo  []string
+	IPVersion  int
+	Interface  string
+	UnixSocket string
+
+	Cert           string
+	Key            string
+	PKCS12         string
+	PKCS12Password string
+	CACert         string
+	TLSMin         string
+	TLSMax         string
+	Ciphers        []string // Go names, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+
+	AWSSigV4   bool
+	AWSRegion  string
+	AWSService string
+
+	Output  string
+	MaxBody int64 // bytes
+	Retries int
+
+	// Select holds --select expressions; jq-style ones are exported as a pipe into jq.
+	Select []string
+}
+
+// Header returns the first value of the named header.
+func (r *Request) Header(name string) (string, bool) {
+	for _, h := range r.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value, true
+		}
+	}
+	return "", false
+}
+
+// split breaks a shell command into words the way bash would for a pasted curl command: single
+// and double quotes, $'...' ANSI-C strings, backslash escapes and line continuations.
+// $NAME and ${NAME} outside single quotes expand from the environment.
+func split(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			if s[i] == '\n' || (s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n') {
+				if s[i] == '\r' {
+					i++
+				}
+				continue
+			}
+			cur.WriteByte(s[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			cur.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			n, err := ansiC(s[i+2:], &cur)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 2
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				switch {
+				case s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0:
+					i++
+					if s[i] != '\n' {
+						cur.WriteByte(s[i])
+					}
+				case s[i] == '$':
+					n, err := expand(s[i:], &cur)
+					if err != nil {
+						return nil, err
+					}
+					i += n - 1
+				default:
+					cur.WriteByte(s[i])
+				}
+			}
+			if i >= len(s) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inWord = true
+		case c == '$':
+			n, err := expand(s[i:], &cur)
+			if err != nil {
+				return nil, err
+			}
+			i += n - 1
+			inWord = true
+		case c == '|' || c == ';' || c == '&' || c == '>' || c == '<':
+			if inWord {
+				words = append(words, cur.String())
+			}
+			// Pipes and redirections after the command (e.g. | jq .) are not part of it.
+			return words, nil
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// expand writes the value of a $NAME or ${NAME} reference at the start of s and returns its length.
+func expand(s string, out *strings.Builder) (int, error) {
+	if len(s) < 2 {
+		out.WriteByte('$')
+		return 1, nil
+	}
+	if s[1] == '(' {
+		return 0, errors.New("command substitution $(...) is not supported; run it first")
+	}
+	if s[1] == '{' {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return 0, errors.New("unterminated ${")
+		}
+		out.WriteString(os.Getenv(s[2:end]))
+		return end + 1, nil
+	}
+	n := 1
+	for n < len(s) && (s[n] == '_' || s[n] >= 'A' && s[n] <= 'Z' || s[n] >= 'a' && s[n] <= 'z' || n > 1 && s[n] >= '0' && s[n] <= '9') {
+		n++
+	}
+	if n == 1 {
+		out.WriteByte('$')
+		return 1, nil
+	}
+	out.WriteString(os.Getenv(s[1:n]))
+	return n, nil
+}
+
+// ansiC decodes the body of a $'...' string up to its closing quote, returning the bytes consumed.
+func ansiC(s string, out *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' {
+			return i + 1, nil
+		}
+		if c != '\\' || i+1 >= len(s) {
+			out.WriteByte(c)
+			continue
+		}
+		i++
+		switch e := s[i]; e {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '0':
+			out.WriteByte(0)
+		case 'x', 'u', 'U':
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+			j := i + 1
+			for j < len(s) && j < i+1+size && strings.IndexByte("0123456789abcdefABCDEF", s[j]) >= 0 {
+				j++
+			}
+			v, err := strconv.ParseUint(s[i+1:j], 16, 32)
+			if err != nil {
+				return 0, fmt.Errorf("invalid escape \\%c in $'...'", e)
+			}
+			if e == 'x' {
+				out.WriteByte(byte(v))
+			} else {
+				out.WriteRune(rune(v))
+			}
+			i = j - 1
+		default:
+			out.WriteByte(e) // \\ \' \" and anything else
+		}
+	}
+	return 0, errors.New("unterminated $'...' string")
+}
+
+// quote renders s as a single shell word, single-quoting it unless it is plainly safe.
+func quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := utf8.ValidString(s)
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("@%_+=:,./-", c)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// secret renders a value that may be env:NAME or file:path as a shell expression, with prefix
+// (e.g. "Authorization: Bearer ") prepended literally.
+func secret(prefix, value string) string {
+	var expr string
+	switch {
+	case strings.HasPrefix(value, "env:"):
+		expr = "${" + strings.TrimPrefix(value, "env:") + "}"
+	case strings.HasPrefix(value, "file:"):
+		expr = "$(cat " + quote(strings.TrimPrefix(value, "file:")) + ")"
+	default:
+		return quote(prefix + value)
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(prefix)
+	return `"` + escaped + expr + `"`
+}
@@ -0,0 +1,258 @@
+package curl
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Setenv("API_TOKEN", "t0k")
+	tests := []struct {
+		name     string
+		command  string
+		want     Request
+		warnings int
+	}{
+		{
+			name:    "plain get",
+			command: "curl https://example.com/",
+			want:    Request{URL: "https://example.com/", Method: "GET", NoFollow: true},
+		},
+		{
+			name:    "default scheme and clustered flags",
+			command: "curl -sSL example.com",
+			want:    Request{URL: "http://example.com", Method: "GET"},
+		},
+		{
+			name: "devtools post",
+			command: `curl 'https://api.example.com/v1/items' \
+  -H 'accept: application/json' \
+  -H "authorization: Bearer $API_TOKEN" \
+  --data-raw '{"name":"x"}' \
+  --compressed`,
+			want: Request{
+				URL: "https://api.example.com/v1/items", Method: "POST", NoFollow: true,
+				Headers: []Header{
+					{Name: "accept", Value: "application/json"},
+					{Name: "authorization", Value: "Bearer t0k"},
+					{Name: "Content-Type", Value: "application/x-www-form-urlencoded"},
+				},
+				Data: `{"name":"x"}`,
+			},
+		},
+		{
+			name:    "json",
+			command: `curl --json '{"a":1}' https://example.com`,
+			want: Request{
+				URL: "https://example.com", Method: "POST", NoFollow: true, Body: `{"a":1}`,
+				Headers: []Header{{Name: "Accept", Value: "application/json"}},
+			},
+		},
+		{
+			name:    "get with data",
+			command: "curl -G -d q=go --data-urlencode 'name=a b' https://example.com/search",
+			want:    Request{URL: "https://example.com/search?q=go&name=a%20b", Method: "GET", NoFollow: true},
+		},
+		{
+			name:    "attached method and ansi-c data",
+			command: `curl -XPUT https://example.com -d $'line\nbreak'`,
+			want: Request{
+				URL: "https://example.com", Method: "PUT", NoFollow: true, Data: "line\nbreak",
+				Headers: []Header{{Name: "Content-Type", Value: "application/x-www-form-urlencoded"}},
+			},
+		},
+		{
+			name:    "form and auth",
+			command: "curl -F name=x -F file=@a.txt -u user:pass https://example.com",
+			want:    Request{URL: "https://example.com", Method: "POST", NoFollow: true, Form: []string{"name=x", "file=@a.txt"}, User: "user:pass"},
+		},
+		{
+			name:    "head",
+			command: "curl -I https://example.com",
+			want:    Request{URL: "https://example.com", Method: "HEAD", NoFollow: true},
+		},
+		{
+			name: "connection and tls options",
+			command: "curl -k -m 2.5 --max-redirs 3 -L --http2 -x http://proxy:8080 --proxy-user u:p --noproxy localhost " +
+				"--resolve example.com:443:127.0.0.1 -4 --tlsv1.2 --tls-max 1.3 --ciphers ECDHE-RSA-AES128-GCM-SHA256 " +
+				"--retry 2 --max-filesize 1k -o out.bin -b jar.txt -c jar.txt https://example.com",
+			want: Request{
+				URL: "https://example.com", Method: "GET", Insecure: true, Timeout: "2.5s", MaxRedirects: 3,
+				HTTPVersion: "2", Proxy: "http://u:p@proxy:8080", NoProxy: "localhost",
+				Resolve: []string{"example.com:443:127.0.0.1"}, IPVersion: 4, TLSMin: "1.2", TLSMax: "1.3",
+				Ciphers: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}, Retries: 2, MaxBody: 1024,
+				Output: "out.bin", CookieJar: "jar.txt",
+			},
+		},
+		{
+			name:    "pkcs12 and sigv4",
+			command: "curl --cert-type P12 --cert id.p12:secret --aws-sigv4 aws:amz:eu-west-1:execute-api https://example.com",
+			want: Request{
+				URL: "https://example.com", Method: "GET", NoFollow: true, PKCS12: "id.p12", PKCS12Password: "secret",
+				AWSSigV4: true, AWSRegion: "eu-west-1", AWSService: "execute-api",
+			},
+		},
+		{
+			name:     "pipes and unsupported options",
+			command:  "curl --http3 --frobnicate https://a.example.com https://b.example.com | jq .",
+			want:     Request{URL: "https://a.example.com", Method: "GET", NoFollow: true},
+			warnings: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := Parse(tt.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse =\n  %+v\nwant\n  %+v", *got, tt.want)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("warnings = %q, want %d", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, command := range []string{
+		"",
+		"curl",
+		"curl -H",
+		"curl 'https://example.com",
+		`curl "https://example.com`,
+		"curl $(echo https://example.com)",
+		"curl -H nocolon https://example.com",
+		"curl --max-time soon https://example.com",
+		"curl --json '{}' -d a=b https://example.com",
+		"curl -F a=b -d c=d https://example.com",
+		"curl -d @- https://example.com",
+	} {
+		if _, _, err := Parse(command); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", command)
+		}
+	}
+}
+
+// TestRoundTrip checks that exporting a parsed command and parsing the export gives the same request.
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	upload := filepath.Join(dir, "upload.json")
+	if err := os.WriteFile(upload, []byte("{\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{
+		"curl https://example.com/",
+		"curl -L https://example.com/a?b=c",
+		"curl -X DELETE -H 'X-Trace: 1' https://example.com/items/1",
+		`curl -H 'Content-Type: application/json' -d '{"it'\''s":"quoted"}' https://example.com`,
+		"curl --data-binary @" + upload + " https://example.com/upload",
+		"curl -d @notes https://example.com",
+		"curl --data-urlencode 'q=a b' --data-urlencode n=1 https://example.com",
+		"curl -F 'name=x y' -F file=@a.txt https://example.com",
+		"curl -I https://example.com",
+		"curl -X HEAD -d a=b https://example.com",
+		"curl -u user:p@ss --oauth2-bearer tok https://example.com",
+		"curl --http2-prior-knowledge http://localhost:8080",
+		"curl --http1.1 -m 0.25 --max-redirs 2 -L https://example.com",
+		"curl -x socks5://proxy:1080 --noproxy '*' --resolve a:443:1.2.3.4 --connect-to a:443:b:8443 -6 --interface eth0 https://a",
+		"curl --unix-socket /var/run/docker.sock http://localhost/info",
+		"curl -k --cert c.pem --key k.pem --cacert ca.pem --tlsv1.3 --tls-max 1.3 https://example.com",
+		"curl --ciphers ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-CHACHA20-POLY1305 https://example.com",
+		"curl --cert-type P12 --cert id.p12 --pass pw https://example.com",
+		"curl --aws-sigv4 aws:amz:us-east-1:s3 https://bucket.s3.amazonaws.com",
+		"curl -b jar.txt -c jar.txt --retry 3 --max-filesize 2M -o out.bin https://example.com",
+		"curl --json '[1,2]' https://example.com",
+	} {
+		t.Run(command, func(t *testing.T) {
+			if strings.Contains(command, "@notes") {
+				t.Chdir(dir)
+				if err := os.WriteFile("notes", []byte("a=1\nb=2\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			first, _, err := Parse(command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exported := first.Command()
+			second, _, err := Parse(exported)
+			if err != nil {
+				t.Fatalf("parsing the export failed: %v\n%s", err, exported)
+			}
+			if first.AWSSigV4 {
+				// The export passes the credentials with -u.
+				second.User = first.User
+			}
+			if first.Body != "" {
+				// curl has no JSON-only body; the export sends it as data with a JSON content type.
+				first.Data, first.Body = first.Body, ""
+				first.Headers = append(first.Headers, Header{Name: "Content-Type", Value: "application/json"})
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("round trip changed the request\n  %+v\nexported as\n%s\nparsed back as\n  %+v", *first, exported, *second)
+			}
+		})
+	}
+}
+
+func TestCommandSecrets(t *testing.T) {
+	r := &Request{
+		URL:     "https://example.com",
+		Method:  "GET",
+		Headers: []Header{{Name: "X-Api-Key", Value: "env:API_KEY", Secret: true}},
+		User:    "admin:file:/run/secrets/pw",
+		Bearer:  "env:TOKEN",
+		Select:  []string{".items[0]", "$.count"},
+	}
+	command := r.Command()
+	for _, want := range []string{
+		`-H "X-Api-Key: ${API_KEY}"`,
+		`-u "admin:$(cat /run/secrets/pw)"`,
+		`--oauth2-bearer "${TOKEN}"`,
+		`| jq -r '.items[0]'`,
+	} {
+		if !strings.Contains(command, want) {
+			t.Errorf("command does not contain %s:\n%s", want, command)
+		}
+	}
+	if notes := r.Notes(); len(notes) != 1 || !strings.Contains(notes[0], "$.count") {
+		t.Errorf("Notes() = %q", notes)
+	}
+
+	// Parse refuses $(cat ...), so only environment secrets are read back.
+	r.User = "admin:env:PASSWORD"
+	t.Setenv("API_KEY", "k")
+	t.Setenv("TOKEN", "t")
+	t.Setenv("PASSWORD", "p")
+	parsed, _, err := Parse(r.Command())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := parsed.Header("X-Api-Key"); v != "k" || parsed.Bearer != "t" || parsed.User != "admin:p" {
+		t.Errorf("secrets expanded to %q, %q and %q, want k, t and admin:p", v, parsed.Bearer, parsed.User)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", "''"},
+		{"https://example.com/a?b=c", "'https://example.com/a?b=c'"},
+		{"plain-word_1.2", "plain-word_1.2"},
+		{"it's", `'it'\''s'`},
+		{"a b", "'a b'"},
+	}
+	for _, tt := range tests {
+		if got := quote(tt.in); got != tt.want {
+			t.Errorf("quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if words, err := split(quote(tt.in)); err != nil || len(words) != 1 || words[0] != tt.in {
+			t.Errorf("split(quote(%q)) = %q, %v", tt.in, words, err)
+		}
+	}
+}
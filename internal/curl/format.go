@@ -0,0 +1,216 @@
+package curl
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command renders the request as a curl command, one option per line. Secrets written as
+// env:NAME or file:path become shell expansions rather than being inlined.
+func (r *Request) Command() string {
+	args := [][]string{{"curl", quote(r.URL)}}
+	add := func(words ...string) { args = append(args, words) }
+
+	hasData := r.Data != "" || r.Body != "" || len(r.Form) > 0
+	if r.Method != "" && r.Method != "GET" && !(r.Method == "POST" && hasData) {
+		if r.Method == "HEAD" && !hasData {
+			add("--head")
+		} else {
+			add("-X", quote(r.Method))
+		}
+	}
+
+	for _, h := range r.Headers {
+		if h.Secret {
+			add("-H", secret(h.Name+": ", h.Value))
+		} else {
+			add("-H", quote(h.Name+": "+h.Value))
+		}
+	}
+
+	switch {
+	case r.Body != "":
+		if _, ok := r.Header("Content-Type"); !ok {
+			add("-H", quote("Content-Type: application/json"))
+		}
+		add(dataFlag(r.Body), quote(r.Body))
+	case r.Data != "":
+		if _, ok := r.Header("Content-Type"); !ok {
+			contentType := "application/octet-stream"
+			if strings.HasPrefix(r.Data, "@") {
+				if t := mime.TypeByExtension(filepath.Ext(r.Data)); t != "" {
+					contentType = t
+				}
+			}
+			add("-H", quote("Content-Type: "+contentType))
+		}
+		add(dataFlag(r.Data), quote(r.Data))
+	case r.URLEncoded:
+		for _, f := range r.Form {
+			// curl reads files for --data-urlencode as name@path.
+			if name, value, _ := strings.Cut(f, "="); strings.HasPrefix(value, "@") {
+				f = name + value
+			}
+			add("--data-urlencode", quote(f))
+		}
+	default:
+		for _, f := range r.Form {
+			add("-F", quote(f))
+		}
+	}
+
+	if r.User != "" {
+		user, pass, _ := strings.Cut(r.User, ":")
+		add("-u", secret(user+":", pass))
+	}
+	if r.Bearer != "" {
+		add("--oauth2-bearer", secret("", r.Bearer))
+	}
+	if r.AWSSigV4 {
+		provider := "aws:amz"
+		if r.AWSRegion != "" || r.AWSService != "" {
+			provider += ":" + r.AWSRegion + ":" + r.AWSService
+		}
+		add("--aws-sigv4", quote(provider))
+		add("-u", `"${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}"`)
+	}
+
+	if !r.NoFollow {
+		add("-L")
+		if r.MaxRedirects > 0 {
+			add("--max-redirs", strconv.Itoa(r.MaxRedirects))
+		}
+	}
+	if r.CookieJar != "" {
+		add("-b", quote(r.CookieJar), "-c", quote(r.CookieJar))
+	}
+	switch r.HTTPVersion {
+	case "1.1":
+		add("--http1.1")
+	case "2":
+		if strings.HasPrefix(r.URL, "http://") {
+			add("--http2-prior-knowledge")
+		} else {
+			add("--http2")
+		}
+	case "h2c":
+		add("--http2-prior-knowledge")
+	}
+	if d, err := time.ParseDuration(r.Timeout); err == nil && d > 0 {
+		add("-m", strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+	}
+
+	if r.Proxy != "" {
+		add("-x", quote(r.Proxy))
+	}
+	if r.NoProxy != "" {
+		add("--noproxy", quote(r.NoProxy))
+	}
+	for _, v := range r.Resolve {
+		add("--resolve", quote(v))
+	}
+	for _, v := range r.ConnectTo {
+		add("--connect-to", quote(v))
+	}
+	switch r.IPVersion {
+	case 4:
+		add("-4")
+	case 6:
+		add("-6")
+	}
+	if r.Interface != "" {
+		add("--interface", quote(r.Interface))
+	}
+	if r.UnixSocket != "" {
+		add("--unix-socket", quote(r.UnixSocket))
+	}
+
+	if r.Insecure {
+		add("-k")
+	}
+	if r.Cert != "" {
+		add("--cert", quote(r.Cert))
+	}
+	if r.Key != "" {
+		add("--key", quote(r.Key))
+	}
+	if r.PKCS12 != "" {
+		add("--cert-type", "P12", "--cert", quote(r.PKCS12))
+		if r.PKCS12Password != "" {
+			add("--pass", secret("", r.PKCS12Password))
+		}
+	}
+	if r.CACert != "" {
+		add("--cacert", quote(r.CACert))
+	}
+	if r.TLSMin != "" {
+		add("--tlsv" + r.TLSMin)
+	}
+	if r.TLSMax != "" {
+		add("--tls-max", quote(r.TLSMax))
+	}
+	if len(r.Ciphers) > 0 {
+		names := make([]string, len(r.Ciphers))
+		for i, name := range r.Ciphers {
+			names[i] = opensslCipher(name)
+		}
+		add("--ciphers", quote(strings.Join(names, ":")))
+	}
+
+	if r.Retries > 0 {
+		add("--retry", strconv.Itoa(r.Retries))
+	}
+	if r.MaxBody > 0 {
+		add("--max-filesize", strconv.FormatInt(r.MaxBody, 10))
+	}
+	if r.Output != "" {
+		add("-o", quote(r.Output))
+	}
+	add("--compressed")
+
+	lines := make([]string, len(args))
+	for i, words := range args {
+		lines[i] = strings.Join(words, " ")
+	}
+	command := strings.Join(lines, " \\\n  ")
+
+	if jq := r.jqSelect(); jq != "" {
+		command += " \\\n  | jq -r " + quote(jq)
+	}
+	return command
+}
+
+// jqSelect returns the first jq-style --select expression, which Command pipes into jq.
+func (r *Request) jqSelect() string {
+	for _, expr := range r.Select {
+		if strings.HasPrefix(expr, ".") {
+			return expr
+		}
+	}
+	return ""
+}
+
+// Notes lists selections that have no curl equivalent.
+func (r *Request) Notes() []string {
+	var notes []string
+	jq := r.jqSelect()
+	for _, expr := range r.Select {
+		if expr != jq {
+			notes = append(notes, fmt.Sprintf("--select %s has no curl equivalent", expr))
+		}
+	}
+	return notes
+}
+
+// dataFlag picks --data-binary for files, which curl would otherwise strip newlines from, and
+// --data-raw for inline data so a leading @ is not read as a file.
+func dataFlag(data string) string {
+	if strings.HasPrefix(data, "@") {
+		return "--data-binary"
+	}
+	return "--data-raw"
+}
@@ -0,0 +1,501 @@
+package curl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueFlags maps curl options that take an argument to their long names.
+var valueFlags = map[string]string{
+	"-X": "--request", "-H": "--header", "-d": "--data", "-F": "--form", "-u": "--user",
+	"-b": "--cookie", "-c": "--cookie-jar", "-o": "--output", "-m": "--max-time", "-x": "--proxy",
+	"-A": "--user-agent", "-e": "--referer", "-E": "--cert", "-r": "--range", "-w": "--write-out",
+	"-D": "--dump-header", "-T": "--upload-file", "-K": "--config", "-U": "--proxy-user",
+}
+
+// boolFlags maps curl switches without an argument to their long names.
+var boolFlags = map[string]string{
+	"-k": "--insecure", "-L": "--location", "-G": "--get", "-I": "--head", "-s": "--silent",
+	"-S": "--show-error", "-v": "--verbose", "-i": "--include", "-f": "--fail", "-N": "--no-buffer",
+	"-g": "--globoff", "-#": "--progress-bar", "-4": "--ipv4", "-6": "--ipv6", "-O": "--remote-name",
+	"-Z": "--parallel", "-j": "--junk-session-cookies", "-n": "--netrc", "-q": "--disable",
+}
+
+// longValueFlags are long options with an argument, handled or knowingly ignored.
+var longValueFlags = map[string]bool{
+	"--request": true, "--header": true, "--data": true, "--data-raw": true, "--data-ascii": true,
+	"--data-binary": true, "--data-urlencode": true, "--json": true, "--form": true, "--form-string": true,
+	"--user": true, "--cookie": true, "--cookie-jar": true, "--output": true, "--max-time": true,
+	"--proxy": true, "--noproxy": true, "--user-agent": true, "--referer": true, "--cert": true,
+	"--key": true, "--cacert": true, "--cert-type": true, "--pass": true, "--resolve": true,
+	"--connect-to": true, "--interface": true, "--unix-socket": true, "--max-redirs": true,
+	"--retry": true, "--oauth2-bearer": true, "--aws-sigv4": true, "--url": true, "--range": true,
+	"--tls-max": true, "--ciphers": true, "--max-filesize": true, "--proxy-user": true,
+	"--connect-timeout": true, "--write-out": true, "--dump-header": true, "--retry-delay": true,
+	"--retry-max-time": true, "--limit-rate": true, "--upload-file": true, "--config": true,
+	"--trace": true, "--trace-ascii": true, "--capath": true, "--pinnedpubkey": true, "--key-type": true,
+	"--tls13-ciphers": true, "--proto": true, "--proto-redir": true, "--expect100-timeout": true,
+	"--keepalive-time": true, "--speed-limit": true, "--speed-time": true, "--variable": true,
+}
+
+// ignored are options without a Pingify equivalent that don't change the request itself.
+var ignored = map[string]bool{
+	"--silent": true, "--show-error": true, "--verbose": true, "--include": true, "--fail": true,
+	"--fail-with-body": true, "--no-buffer": true, "--globoff": true, "--progress-bar": true,
+	"--compressed": true, "--path-as-is": true, "--no-progress-meter": true, "--write-out": true,
+	"--dump-header": true, "--trace": true, "--trace-ascii": true, "--connect-timeout": true,
+	"--retry-delay": true, "--retry-max-time": true, "--retry-connrefused": true, "--retry-all-errors": true,
+	"--limit-rate": true, "--keepalive-time": true, "--speed-limit": true, "--speed-time": true,
+	"--tcp-nodelay": true, "--tcp-fastopen": true, "--no-keepalive": true, "--styled-output": true,
+	"--disable": true, "--http1.0": true, "--location-trusted": true, "--post301": true,
+	"--post302": true, "--post303": true, "--expect100-timeout": true, "--remote-name": true,
+	"--junk-session-cookies": true, "--parallel": true,
+}
+
+// Parse reads a curl command line, as copied from browser devtools or docs. Options Pingify
+// cannot honour are returned as warnings rather than errors.
+func Parse(command string) (*Request, []string, error) {
+	words, err := split(strings.TrimSpace(command))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(words) > 0 && (words[0] == "curl" || strings.HasSuffix(words[0], "/curl") || words[0] == "curl.exe") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil, nil, errors.New("empty curl command")
+	}
+
+	p := &parse{req: &Request{}}
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if w == "--" {
+			for _, rest := range words[i+1:] {
+				p.url(rest)
+			}
+			break
+		}
+		if !strings.HasPrefix(w, "-") || w == "-" {
+			p.url(w)
+			continue
+		}
+
+		var names []string
+		var value *string
+		if strings.HasPrefix(w, "--") {
+			names = []string{w}
+		} else {
+			// Short options can be clustered (-sSL) and take their value attached (-XPOST).
+			for j := 1; j < len(w); j++ {
+				short := "-" + string(w[j])
+				if long, ok := valueFlags[short]; ok {
+					names = append(names, long)
+					if j+1 < len(w) {
+						v := w[j+1:]
+						value = &v
+					}
+					break
+				}
+				if long, ok := boolFlags[short]; ok {
+					names = append(names, long)
+					continue
+				}
+				p.warn("unknown option %s ignored", short)
+			}
+		}
+
+		for _, name := range names {
+			takesValue := longValueFlags[name]
+			if takesValue && value == nil {
+				if i+1 >= len(words) {
+					return nil, nil, fmt.Errorf("%s needs a value", name)
+				}
+				i++
+				value = &words[i]
+			}
+			v := ""
+			if takesValue {
+				v = *value
+			}
+			if err := p.option(name, v); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	if err := p.finish(); err != nil {
+		return nil, nil, err
+	}
+	return p.req, p.warnings, nil
+}
+
+type parse struct {
+	req       *Request
+	warnings  []string
+	data      []string
+	get       bool
+	head      bool
+	location  bool
+	cookieIn  string
+	cookieOut string
+	certType  string
+	keyPass   string
+}
+
+func (p *parse) warn(format string, args ...any) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *parse) url(u string) {
+	if p.req.URL != "" {
+		p.warn("extra URL %s ignored; only one request is imported", u)
+		return
+	}
+	if !strings.Contains(u, "://") {
+		u = "http://" + u // curl's default scheme
+	}
+	p.req.URL = u
+}
+
+func (p *parse) header(name, value string) {
+	for i, h := range p.req.Headers {
+		if strings.EqualFold(h.Name, name) && strings.EqualFold(name, "Cookie") {
+			p.req.Headers[i].Value += "; " + value
+			return
+		}
+	}
+	p.req.Headers = append(p.req.Headers, Header{Name: name, Value: value})
+}
+
+func (p *parse) option(name, v string) error {
+	r := p.req
+	switch name {
+	case "--url":
+		p.url(v)
+	case "--request":
+		r.Method = strings.ToUpper(v)
+	case "--header":
+		hname, hvalue, ok := strings.Cut(v, ":")
+		if !ok {
+			if strings.HasSuffix(v, ";") {
+				p.header(strings.TrimSuffix(v, ";"), "") // curl's syntax for an empty header
+				return nil
+			}
+			return fmt.Errorf("header %q has no colon", v)
+		}
+		if hvalue = strings.TrimSpace(hvalue); hvalue == "" {
+			p.warn("header %s: curl removes headers set to nothing; ignored", hname)
+			return nil
+		}
+		p.header(strings.TrimSpace(hname), hvalue)
+	case "--data", "--data-ascii":
+		if strings.HasPrefix(v, "@") {
+			data, err := readFile(v[1:])
+			if err != nil {
+				return err
+			}
+			// -d strips newlines from files, unlike --data-binary.
+			v = strings.NewReplacer("\r", "", "\n", "").Replace(data)
+		}
+		p.data = append(p.data, v)
+	case "--data-binary":
+		if strings.HasPrefix(v, "@") && len(p.data) == 0 && r.Data == "" {
+			// Keep a single file reference so large uploads are not inlined.
+			r.Data = v
+			return nil
+		}
+		if strings.HasPrefix(v, "@") {
+			data, err := readFile(v[1:])
+			if err != nil {
+				return err
+			}
+			v = data
+		}
+		p.data = append(p.data, v)
+	case "--data-raw":
+		p.data = append(p.data, v)
+	case "--data-urlencode":
+		encoded, err := urlencode(v)
+		if err != nil {
+			return err
+		}
+		p.data = append(p.data, encoded)
+	case "--json":
+		r.Body += v
+		if _, ok := r.Header("Accept"); !ok {
+			p.header("Accept", "application/json")
+		}
+	case "--form":
+		if name, file, ok := strings.Cut(v, "=<"); ok {
+			data, err := readFile(file)
+			if err != nil {
+				return err
+			}
+			v = name + "=" + data
+		}
+		r.Form = append(r.Form, v)
+	case "--form-string":
+		if _, value, _ := strings.Cut(v, "="); strings.HasPrefix(value, "@") {
+			p.warn("--form-string %q starts with @ and would be read as a file by Pingify", v)
+		}
+		r.Form = append(r.Form, v)
+	case "--upload-file":
+		r.Data = "@" + v
+		if r.Method == "" {
+			r.Method = "PUT"
+		}
+	case "--get":
+		p.get = true
+	case "--head":
+		p.head = true
+	case "--user":
+		r.User = v
+	case "--oauth2-bearer":
+		r.Bearer = v
+	case "--cookie":
+		if strings.Contains(v, "=") {
+			p.header("Cookie", v)
+		} else {
+			p.cookieIn = v
+		}
+	case "--cookie-jar":
+		p.cookieOut = v
+	case "--user-agent":
+		p.header("User-Agent", v)
+	case "--referer":
+		p.header("Referer", strings.TrimSuffix(v, ";auto"))
+	case "--range":
+		p.header("Range", "bytes="+v)
+	case "--insecure":
+		r.Insecure = true
+	case "--location":
+		p.location = true
+	case "--max-redirs":
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		r.MaxRedirects = n
+	case "--max-time":
+		secs, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		r.Timeout = (time.Duration(secs * float64(time.Second))).String()
+	case "--http1.1":
+		r.HTTPVersion = "1.1"
+	case "--http2":
+		r.HTTPVersion = "2"
+	case "--http2-prior-knowledge":
+		r.HTTPVersion = "h2c"
+		if strings.HasPrefix(r.URL, "https://") {
+			r.HTTPVersion = "2"
+		}
+	case "--http3", "--http3-only":
+		p.warn("%s is not supported; Pingify will negotiate HTTP/1.1 or HTTP/2", name)
+	case "--proxy":
+		r.Proxy = v
+	case "--proxy-user":
+		if r.Proxy == "" {
+			p.warn("--proxy-user without --proxy ignored")
+			return nil
+		}
+		if u, err := url.Parse(r.Proxy); err == nil && u.Host != "" {
+			user, pass, _ := strings.Cut(v, ":")
+			u.User = url.UserPassword(user, pass)
+			r.Proxy = u.String()
+		}
+	case "--noproxy":
+		r.NoProxy = v
+	case "--resolve":
+		r.Resolve = append(r.Resolve, v)
+	case "--connect-to":
+		r.ConnectTo = append(r.ConnectTo, v)
+	case "--ipv4":
+		r.IPVersion = 4
+	case "--ipv6":
+		r.IPVersion = 6
+	case "--interface":
+		r.Interface = v
+	case "--unix-socket":
+		r.UnixSocket = v
+	case "--cert":
+		r.Cert = v
+	case "--key":
+		r.Key = v
+	case "--cert-type":
+		p.certType = strings.ToUpper(v)
+	case "--pass":
+		p.keyPass = v
+	case "--cacert":
+		r.CACert = v
+	case "--tlsv1", "--tlsv1.0":
+		r.TLSMin = "1.0"
+	case "--tlsv1.1":
+		r.TLSMin = "1.1"
+	case "--tlsv1.2":
+		r.TLSMin = "1.2"
+	case "--tlsv1.3":
+		r.TLSMin = "1.3"
+	case "--tls-max":
+		r.TLSMax = v
+	case "--ciphers":
+		for _, name := range strings.Split(v, ":") {
+			r.Ciphers = append(r.Ciphers, goCipher(name))
+		}
+	case "--aws-sigv4":
+		// aws:amz[:region[:service]]
+		parts := strings.Split(v, ":")
+		r.AWSSigV4 = true
+		if len(parts) > 2 {
+			r.AWSRegion = parts[2]
+		}
+		if len(parts) > 3 {
+			r.AWSService = parts[3]
+		}
+	case "--output":
+		r.Output = v
+	case "--max-filesize":
+		n, err := parseSize(v)
+		if err != nil {
+			return err
+		}
+		r.MaxBody = n
+	case "--retry":
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		r.Retries = n
+	case "--config", "--netrc":
+		p.warn("%s is not supported; pass the options directly", name)
+	default:
+		if !ignored[name] {
+			p.warn("unsupported option %s ignored", name)
+		}
+	}
+	return nil
+}
+
+// finish resolves options that depend on each other, mirroring curl's defaults.
+func (p *parse) finish() error {
+	r := p.req
+	if r.URL == "" {
+		return errors.New("no URL in curl command")
+	}
+
+	if len(p.data) > 0 {
+		joined := strings.Join(p.data, "&")
+		if r.Data != "" {
+			return errors.New("cannot combine a --data-binary file with other data options")
+		}
+		if p.get {
+			sep := "?"
+			if strings.Contains(r.URL, "?") {
+				sep = "&"
+			}
+			r.URL += sep + joined
+		} else {
+			r.Data = joined
+		}
+	}
+	if r.Data != "" && r.Body != "" {
+		return errors.New("cannot combine --json with other data options")
+	}
+	if r.Data != "" {
+		if _, ok := r.Header("Content-Type"); !ok {
+			p.header("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if (r.Data != "" || r.Body != "") && len(r.Form) > 0 {
+		return errors.New("cannot combine --form with data options")
+	}
+
+	switch {
+	case r.Method != "":
+	case p.head:
+		r.Method = "HEAD"
+	case r.Data != "" || r.Body != "" || len(r.Form) > 0:
+		r.Method = "POST"
+	default:
+		r.Method = "GET"
+	}
+
+	// curl does not follow redirects unless told to; Pingify does by default.
+	r.NoFollow = !p.location
+
+	switch {
+	case p.cookieOut != "":
+		r.CookieJar = p.cookieOut
+		if p.cookieIn != "" && p.cookieIn != p.cookieOut {
+			p.warn("cookies are read from and saved to %s; %s is not loaded", p.cookieOut, p.cookieIn)
+		}
+	case p.cookieIn != "":
+		r.CookieJar = p.cookieIn
+	}
+
+	if p.certType == "P12" {
+		cert, pass, _ := strings.Cut(r.Cert, ":")
+		r.PKCS12, r.Cert = cert, ""
+		r.PKCS12Password = pass
+		if p.keyPass != "" {
+			r.PKCS12Password = p.keyPass
+		}
+	} else if p.keyPass != "" {
+		p.warn("encrypted client keys are not supported; decrypt the key or use a PKCS#12 bundle")
+	}
+	return nil
+}
+
+// urlencode applies --data-urlencode's content forms: content, =content, name=content, @file and name@file.
+func urlencode(v string) (string, error) {
+	if eq := strings.IndexByte(v, '='); eq >= 0 && !strings.Contains(v[:eq], "@") {
+		name := v[:eq]
+		if name == "" {
+			return escape(v[eq+1:]), nil
+		}
+		return name + "=" + escape(v[eq+1:]), nil
+	}
+	if name, file, ok := strings.Cut(v, "@"); ok {
+		data, err := readFile(file)
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			return escape(data), nil
+		}
+		return name + "=" + escape(data), nil
+	}
+	return escape(v), nil
+}
+
+// parseSize reads curl's sizes: a byte count with an optional k, M or G (binary) suffix.
+func parseSize(v string) (int64, error) {
+	shift := 0
+	if i := strings.IndexAny(v, "kKmMgG"); i >= 0 && i == len(v)-1 {
+		shift = map[byte]int{'k': 10, 'm': 20, 'g': 30}[v[i]|0x20]
+		v = v[:i]
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", v)
+	}
+	return n << shift, nil
+}
+
+func readFile(path string) (string, error) {
+	if path == "-" {
+		return "", errors.New("reading data from stdin (@-) is not supported when importing")
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+// escape percent-encodes like curl, which writes spaces as %20 rather than +.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
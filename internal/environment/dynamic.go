@@ -0,0 +1,106 @@
+package environment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dynamic lists the built-in {{$name}} values, each computed afresh wherever it is used.
+var Dynamic = []string{
+	"$uuid", "$timestamp", "$timestampMs", "$isoTimestamp", "$randomInt [min max]",
+	"$hmac <sha1|sha256|sha512> <key> <message> [hex|base64]",
+}
+
+// dynamic computes a built-in value. $hmac takes variable names or literals as its key and
+// message, so signatures can cover values captured or defined elsewhere.
+func (v Vars) dynamic(name string, args []string) (string, error) {
+	switch name {
+	case "$uuid", "$guid":
+		return uuid()
+	case "$timestamp":
+		return strconv.FormatInt(time.Now().Unix(), 10), nil
+	case "$timestampMs":
+		return strconv.FormatInt(time.Now().UnixMilli(), 10), nil
+	case "$isoTimestamp":
+		return time.Now().UTC().Format(time.RFC3339), nil
+	case "$randomInt":
+		low, high := int64(0), int64(1000)
+		if len(args) == 2 {
+			var err1, err2 error
+			low, err1 = strconv.ParseInt(args[0], 10, 64)
+			high, err2 = strconv.ParseInt(args[1], 10, 64)
+			if err1 != nil || err2 != nil || high < low {
+				return "", errors.New("use {{$randomInt min max}} with whole numbers")
+			}
+		} else if len(args) != 0 {
+			return "", errors.New("use {{$randomInt}} or {{$randomInt min max}}")
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(high-low+1))
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(low+n.Int64(), 10), nil
+	case "$hmac":
+		return v.hmac(args)
+	}
+	return "", fmt.Errorf("unknown dynamic value (supported: %s)", strings.Join(Dynamic, ", "))
+}
+
+func (v Vars) hmac(args []string) (string, error) {
+	if len(args) != 3 && len(args) != 4 {
+		return "", errors.New("use {{$hmac sha256 key message}}, optionally followed by hex or base64")
+	}
+	var newHash func() hash.Hash
+	switch strings.ToLower(args[0]) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return "", fmt.Errorf("unknown hash %q (supported: sha1, sha256, sha512)", args[0])
+	}
+	arg := func(s string) string {
+		if value, ok := v[s]; ok {
+			return value
+		}
+		return s
+	}
+	mac := hmac.New(newHash, []byte(arg(args[1])))
+	mac.Write([]byte(arg(args[2])))
+	sum := mac.Sum(nil)
+	if len(args) == 4 {
+		switch args[3] {
+		case "base64":
+			return base64.StdEncoding.EncodeToString(sum), nil
+		case "hex":
+		default:
+			return "", fmt.Errorf("unknown encoding %q (supported: hex, base64)", args[3])
+		}
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// uuid returns a random (version 4) UUID.
+func uuid() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
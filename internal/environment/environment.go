@@ -0,0 +1,119 @@
+// Package environment loads environment files, which define the variables of one target such as
+// local, staging or prod, and expands {{name}}, {{$dynamic}} and ${ENV} references.
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is where environments are looked up by name, e.g. environments/staging.yaml.
+const Dir = "environments"
+
+// Environment is an environment file. Secrets are env:NAME or file:path references, so the file
+// can be committed; their values are masked in output, logs, reports and AI payloads.
+type Environment struct {
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Secrets   map[string]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	// Path is the file the environment was loaded from.
+	Path string `json:"-" yaml:"-"`
+}
+
+// Find returns the file of the named environment: the name itself when it is a file, otherwise
+// environments/<name>.yaml, .yml or .json.
+func Find(name string) (string, error) {
+	if info, err := os.Stat(name); err == nil && !info.IsDir() {
+		return name, nil
+	}
+	var tried []string
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		path := filepath.Join(Dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		tried = append(tried, path)
+	}
+	return "", fmt.Errorf("environment %q not found (looked for %s)", name, strings.Join(tried, ", "))
+}
+
+// Load reads the named environment; see Find.
+func Load(name string) (*Environment, error) {
+	path, err := Find(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment: %w", err)
+	}
+	var env Environment
+	if err := yaml.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	env.Path = path
+	if err := env.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &env, nil
+}
+
+// Validate reports the first problem with the environment's names or secret references.
+func (e *Environment) Validate() error {
+	for name := range e.Variables {
+		if !ValidName(name) {
+			return fmt.Errorf("%q is not a valid variable name", name)
+		}
+	}
+	for name, ref := range e.Secrets {
+		if !ValidName(name) {
+			return fmt.Errorf("%q is not a valid variable name", name)
+		}
+		if _, dup := e.Variables[name]; dup {
+			return fmt.Errorf("%s is both a variable and a secret", name)
+		}
+		if !strings.HasPrefix(ref, "env:") && !strings.HasPrefix(ref, "file:") {
+			return fmt.Errorf("secret %s must be env:NAME or file:path, so its value stays out of the file", name)
+		}
+	}
+	return nil
+}
+
+// Vars resolves the environment's variables and secrets, registering the secret values for
+// masking. Variables may use ${ENV} and dynamic values, but not each other.
+func (e *Environment) Vars() (Vars, error) {
+	vars := Vars{}
+	for name, value := range e.Variables {
+		expanded, err := Vars{}.Expand(value)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", name, err)
+		}
+		vars[name] = expanded
+	}
+	for name, ref := range e.Secrets {
+		if strings.HasPrefix(ref, "file:") {
+			ref = "file:" + resolve(filepath.Dir(e.Path), strings.TrimPrefix(ref, "file:"))
+		}
+		value, err := requester.ResolveSecret(ref)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", name, err)
+		}
+		secret.Register(value)
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// resolve makes a path written in the environment file relative to it.
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,111 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("PINGIFY_TEST_TOKEN", "env-t0ken")
+	writeFile(t, "environments/staging.yaml", `
+variables:
+  base: https://${PINGIFY_TEST_HOST:-staging.example.com}
+  id: "42"
+secrets:
+  token: env:PINGIFY_TEST_TOKEN
+  key: file:keys/api.key
+`)
+	// file: secrets are relative to the environment file, not the working directory.
+	writeFile(t, "environments/keys/api.key", "f1le-key\n")
+	writeFile(t, "keys/api.key", "wrong")
+
+	env, err := Load("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Path != filepath.Join("environments", "staging.yaml") {
+		t.Errorf("Path = %q", env.Path)
+	}
+	vars, err := env.Vars()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Vars{"base": "https://staging.example.com", "id": "42", "token": "env-t0ken", "key": "f1le-key"}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+	if got := secret.Mask("env-t0ken f1le-key 42"); got != "**** **** 42" {
+		t.Errorf("secrets are not masked: %q", got)
+	}
+
+	// A path to the file works as well as a name.
+	if _, err := Load(filepath.Join("environments", "staging.yaml")); err != nil {
+		t.Errorf("Load by path: %v", err)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{"literal secret", "secrets:\n  token: abc123\n", "must be env:NAME or file:path"},
+		{"invalid variable name", "variables:\n  \"bad name\": x\n", "not a valid variable name"},
+		{"invalid secret name", "secrets:\n  \"1st\": env:X\n", "not a valid variable name"},
+		{"variable and secret", "variables:\n  token: x\nsecrets:\n  token: env:X\n", "both a variable and a secret"},
+		{"not yaml", "variables: [", "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			writeFile(t, "environments/bad.yaml", tt.content)
+			_, err := Load("bad")
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Load = %v, want an error mentioning %q", err, tt.errText)
+			}
+		})
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		if _, err := Load("prod"); err == nil || !strings.Contains(err.Error(), "environments/prod.yaml") {
+			t.Errorf("Load = %v, want the paths tried", err)
+		}
+	})
+}
+
+func TestVarsReportsUnresolvedSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		env  Environment
+	}{
+		{"unset env", Environment{Secrets: map[string]string{"token": "env:PINGIFY_TEST_UNSET"}}},
+		{"missing file", Environment{Secrets: map[string]string{"token": "file:nope.key"}, Path: filepath.Join(t.TempDir(), "env.yaml")}},
+		{"undefined env in variable", Environment{Variables: map[string]string{"base": "${PINGIFY_TEST_UNSET}"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.env.Vars(); err == nil {
+				t.Error("Vars succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reference matches {{name}}, {{$dynamic args...}} (spaces inside the braces are ignored) and
+	// ${NAME} or ${NAME:-default} for environment variables, after the {{{{ and $${ escapes.
+	reference = regexp.MustCompile(`(\{\{\{\{|\$\$\{)|\{\{\s*(\$?[A-Za-z_][A-Za-z0-9_.-]*)((?:\s+[^\s{}]+)*)\s*\}\}|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+// ErrUndefined is returned for references to variables that are not set.
+var ErrUndefined = errors.New("undefined variable")
+
+// ValidName reports whether name can be used as a variable.
+func ValidName(s string) bool {
+	return validName.MatchString(s)
+}
+
+// Vars holds the values {{name}} placeholders are replaced with: environment variables and
+// secrets, collection variables and values captured from earlier responses.
+type Vars map[string]string
+
+// HasReferences reports whether s contains anything Expand would replace.
+func HasReferences(s string) bool {
+	return strings.Contains(s, "{{") || strings.Contains(s, "${")
+}
+
+// Names returns the {{name}} variables s references, leaving out dynamic values, environment
+// variables and escaped braces.
+func Names(s string) []string {
+	var names []string
+	for _, sub := range reference.FindAllStringSubmatch(s, -1) {
+		if name := sub[2]; name != "" && !strings.HasPrefix(name, "$") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Expand replaces {{name}} with the variable, {{$uuid}} and the other dynamic values with a fresh
+// value, and ${NAME} with the environment variable. {{{{ and $${ stand for a literal {{ and ${.
+// Referencing a variable that is not set is an error, so a failed capture is reported where its
+// value is used rather than sent as "".
+func (v Vars) Expand(s string) (string, error) {
+	if !HasReferences(s) {
+		return s, nil
+	}
+	var missing []string
+	var failed error
+	out := reference.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reference.FindStringSubmatch(m)
+		if escape := sub[1]; escape != "" {
+			return escape[len(escape)/2:]
+		}
+		if env := sub[4]; env != "" {
+			if value, ok := os.LookupEnv(env); ok {
+				return value
+			}
+			if sub[5] != "" {
+				return strings.TrimPrefix(sub[5], ":-")
+			}
+			missing = append(missing, "${"+env+"}")
+			return ""
+		}
+		ref, args := sub[2], strings.Fields(sub[3])
+		if strings.HasPrefix(ref, "$") {
+			value, err := v.dynamic(ref, args)
+			if err != nil && failed == nil {
+				failed = fmt.Errorf("{{%s}}: %w", ref, err)
+			}
+			return value
+		}
+		value, ok := v[ref]
+		if !ok || len(args) > 0 {
+			missing = append(missing, "{{"+strings.TrimSpace(strings.Trim(m, "{}"))+"}}")
+		}
+		return value
+	})
+	if failed != nil {
+		return "", failed
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w %s", ErrUndefined, strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// ExpandValue replaces references in every string of a decoded JSON or YAML value, returning a
+// copy.
+func (v Vars) ExpandValue(value any) (any, error) {
+	switch t := value.(type) {
+	case string:
+		return v.Expand(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, x := range t {
+			key, err := v.Expand(k)
+			if err != nil {
+				return nil, err
+			}
+			if out[key], err = v.ExpandValue(x); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, x := range t {
+			var err error
+			if out[i], err = v.ExpandValue(x); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// Merge returns a copy of v with the values of others added, later ones taking precedence.
+func (v Vars) Merge(others ...Vars) Vars {
+	out := Vars{}
+	for _, vars := range append([]Vars{v}, others...) {
+		for k, x := range vars {
+			out[k] = x
+		}
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package environment
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	t.Setenv("PINGIFY_TEST_HOST", "api.example.com")
+	t.Setenv("PINGIFY_TEST_EMPTY", "")
+	vars := Vars{"base": "https://example.com", "id": "42", "secret": "s3cret", "msg": "hello"}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error // nil for success, ErrUndefined, or errAny for any other error
+	}{
+		{"no references", "plain text", "plain text", nil},
+		{"variable", "{{base}}/items/{{id}}", "https://example.com/items/42", nil},
+		{"spaces inside braces", "{{ id }}", "42", nil},
+		{"escaped braces", "{{{{id}}", "{{id}}", nil},
+		{"escaped env", "$${PINGIFY_TEST_HOST}", "${PINGIFY_TEST_HOST}", nil},
+		{"env", "https://${PINGIFY_TEST_HOST}/", "https://api.example.com/", nil},
+		{"env set but empty", "[${PINGIFY_TEST_EMPTY:-fallback}]", "[]", nil},
+		{"env default", "${PINGIFY_TEST_UNSET:-http://localhost:8080}", "http://localhost:8080", nil},
+		{"env empty default", "[${PINGIFY_TEST_UNSET:-}]", "[]", nil},
+		{"undefined env", "${PINGIFY_TEST_UNSET}", "", ErrUndefined},
+		{"undefined variable", "{{tokn}}", "", ErrUndefined},
+		{"arguments on a variable", "{{id extra}}", "", ErrUndefined},
+		{"unknown dynamic", "{{$nope}}", "", errAny},
+		{"hmac hex", "{{$hmac sha256 secret msg}}", "e5a01537481fa0b2c697f787c7aff885412cf0760d08e08502259b39d2d6ae68", nil},
+		{"hmac literal arguments", "{{$hmac sha1 key message hex}}", "2088df74d5f2146b48146caf4965377e9d0be3a4", nil},
+		{"hmac unknown hash", "{{$hmac md5 key msg}}", "", errAny},
+		{"hmac unknown encoding", "{{$hmac sha256 key msg base32}}", "", errAny},
+		{"hmac missing message", "{{$hmac sha256 key}}", "", errAny},
+		{"randomInt bad bounds", "{{$randomInt 10 1}}", "", errAny},
+		{"randomInt one bound", "{{$randomInt 10}}", "", errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vars.Expand(tt.in)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("Expand(%q) failed: %v", tt.in, err)
+			case tt.wantErr == errAny && err == nil, tt.wantErr == ErrUndefined && !errors.Is(err, ErrUndefined):
+				t.Fatalf("Expand(%q) = %q, %v; want %v", tt.in, got, err, tt.wantErr)
+			}
+			if err == nil && tt.want != "" && got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// errAny stands for any error other than ErrUndefined in test tables.
+var errAny = errors.New("any error")
+
+func TestExpandHMACEncodings(t *testing.T) {
+	vars := Vars{"k": "key"}
+	tests := map[string]string{
+		"{{$hmac sha1 k message base64}}":   "IIjfdNXyFGtIFGyvSWU3fp0L46Q=",
+		"{{$hmac sha256 k message hex}}":    "6e9ef29b75fffc5b7abae527d58fdadb2fe42e7219011976917343065f58ed4a",
+		"{{$hmac sha256 k message base64}}": "bp7ym3X//Ft6uuUn1Y/a2y/kLnIZARl2kXNDBl9Y7Uo=",
+		"{{$hmac sha512 k message base64}}": "5Hc4TXyiKd0UJuZLY+vy0269bX5mmmc1Qk5y6mwB0/i1brOcNtgjL1QnmZuNGj+c0RKPxp9NdbQ0IWgQ+jZ+mA==",
+	}
+	for in, want := range tests {
+		if got, err := vars.Expand(in); err != nil || got != want {
+			t.Errorf("Expand(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestExpandUndefinedListsEveryReference(t *testing.T) {
+	_, err := Vars{}.Expand("{{a}}/{{ b }}/${PINGIFY_TEST_UNSET}")
+	if !errors.Is(err, ErrUndefined) {
+		t.Fatalf("err = %v, want ErrUndefined", err)
+	}
+	for _, ref := range []string{"{{a}}", "{{b}}", "${PINGIFY_TEST_UNSET}"} {
+		if !strings.Contains(err.Error(), ref) {
+			t.Errorf("error %q does not mention %s", err, ref)
+		}
+	}
+}
+
+func TestExpandDynamicValues(t *testing.T) {
+	tests := []struct {
+		in      string
+		pattern string
+	}{
+		{"{{$uuid}}", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"{{$guid}}", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"{{$timestamp}}", `^\d{10}$`},
+		{"{{$timestampMs}}", `^\d{13}$`},
+		{"{{$isoTimestamp}}", `^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ$`},
+	}
+	for _, tt := range tests {
+		got, err := Vars{}.Expand(tt.in)
+		if err != nil || !regexp.MustCompile(tt.pattern).MatchString(got) {
+			t.Errorf("Expand(%q) = %q, %v; want a match for %s", tt.in, got, err, tt.pattern)
+		}
+	}
+
+	a, _ := Vars{}.Expand("{{$uuid}}")
+	b, _ := Vars{}.Expand("{{$uuid}}")
+	if a == b {
+		t.Errorf("two {{$uuid}} expansions returned %s", a)
+	}
+}
+
+func TestExpandRandomIntBounds(t *testing.T) {
+	tests := []struct {
+		in        string
+		low, high int64
+	}{
+		{"{{$randomInt}}", 0, 1000},
+		{"{{$randomInt 5 7}}", 5, 7},
+		{"{{$randomInt -3 -3}}", -3, -3},
+	}
+	for _, tt := range tests {
+		seen := map[int64]bool{}
+		for range 200 {
+			got, err := Vars{}.Expand(tt.in)
+			if err != nil {
+				t.Fatalf("Expand(%q): %v", tt.in, err)
+			}
+			n, err := strconv.ParseInt(got, 10, 64)
+			if err != nil || n < tt.low || n > tt.high {
+				t.Fatalf("Expand(%q) = %q, want a number in [%d, %d]", tt.in, got, tt.low, tt.high)
+			}
+			seen[n] = true
+		}
+		if want := min(tt.high-tt.low+1, 3); int64(len(seen)) < want {
+			t.Errorf("Expand(%q) returned only %v in 200 tries", tt.in, seen)
+		}
+	}
+}
+
+func TestExpandValue(t *testing.T) {
+	vars := Vars{"id": "42", "field": "name"}
+	in := map[string]any{
+		"id":        "{{id}}",
+		"{{field}}": "x",
+		"list":      []any{"{{id}}", 1.5, true, map[string]any{"nested": "{{id}}"}},
+	}
+	got, err := vars.ExpandValue(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"id":   "42",
+		"name": "x",
+		"list": []any{"42", 1.5, true, map[string]any{"nested": "42"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandValue = %#v, want %#v", got, want)
+	}
+	if in["id"] != "{{id}}" || in["list"].([]any)[0] != "{{id}}" {
+		t.Errorf("ExpandValue modified its input: %#v", in)
+	}
+
+	if _, err := vars.ExpandValue(map[string]any{"a": []any{"{{missing}}"}}); !errors.Is(err, ErrUndefined) {
+		t.Errorf("err = %v, want ErrUndefined", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := Vars{"a": "1", "b": "1"}
+	got := base.Merge(Vars{"b": "2", "c": "2"}, Vars{"c": "3"})
+	if want := (Vars{"a": "1", "b": "2", "c": "3"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge = %v, want %v", got, want)
+	}
+	if base["b"] != "1" || len(base) != 2 {
+		t.Errorf("Merge modified the receiver: %v", base)
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := map[string][]string{
+		"plain":                              nil,
+		"Bearer {{token}}":                   {"token"},
+		"{{ base }}/{{id}}":                  {"base", "id"},
+		"{{$uuid}} {{$hmac sha256 key msg}}": nil,
+		"${HOME} {{{{escaped}}":              nil,
+	}
+	for in, want := range tests {
+		if got := Names(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("Names(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package graphql sends GraphQL operations over HTTP and interprets the response envelope.
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+)
+
+// Request is a single GraphQL operation POSTed to URL as JSON.
+type Request struct {
+	URL           string
+	Query         string
+	Variables     string // JSON object, may be empty
+	OperationName string
+	Headers       string
+	Timeout       string
+
+	// Header holds extra headers set on the outgoing request (e.g. traceparent).
+	Header http.Header
+
+	// Retry, Auth, Signer, TLS, Net and HTTP are passed through to requester.Do.
+	Retry  requester.RetryPolicy
+	Auth   requester.Authenticator
+	Signer requester.Signer
+	TLS    *requester.TLSOptions
+	Net    *requester.NetOptions
+	HTTP   *requester.HTTPOptions
+
+	// MaxBody caps the response size read; zero reads it all.
+	MaxBody int64
+}
+
+// Error is one entry of the response's errors array.
+type Error struct {
+	Message   string     `json:"message"`
+	Path      []any      `json:"path,omitempty"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+// Location points into the query document.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (e Error) String() string {
+	var where []string
+	if len(e.Path) > 0 {
+		parts := make([]string, len(e.Path))
+		for i, p := range e.Path {
+			parts[i] = fmt.Sprint(p)
+		}
+		where = append(where, "at "+strings.Join(parts, "."))
+	}
+	for _, loc := range e.Locations {
+		where = append(where, fmt.Sprintf("line %d:%d", loc.Line, loc.Column))
+	}
+	if len(where) == 0 {
+		return e.Message
+	}
+	return e.Message + " (" + strings.Join(where, ", ") + ")"
+}
+
+// Result is the HTTP response together with the decoded GraphQL envelope.
+type Result struct {
+	Response *requester.Response
+	Data     json.RawMessage
+	Errors   []Error
+
+	// Envelope is false when the body was not a GraphQL JSON response.
+	Envelope bool
+}
+
+// Problem describes why the result counts as a failure, or returns "" for a clean response.
+// A 200 response with a non-empty errors array is a failure.
+func (r *Result) Problem() string {
+	switch {
+	case r.Response.StatusCode >= 400 && len(r.Errors) == 0:
+		return fmt.Sprintf("HTTP %d", r.Response.StatusCode)
+	case len(r.Errors) == 1:
+		return "GraphQL error: " + r.Errors[0].String()
+	case len(r.Errors) > 1:
+		return fmt.Sprintf("%d GraphQL errors, first: %s", len(r.Errors), r.Errors[0])
+	case !r.Envelope:
+		return "response is not a GraphQL JSON object"
+	}
+	return ""
+}
+
+// PrettyData returns the data member indented, or "null" when it is absent.
+func (r *Result) PrettyData() string {
+	if len(r.Data) == 0 {
+		return "null"
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, r.Data, "", "  "); err != nil {
+		return string(r.Data)
+	}
+	return out.String()
+}
+
+// LoadQuery reads a query document from path.
+func LoadQuery(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read query file: %w", err)
+	}
+	return string(data), nil
+}
+
+// Do sends the operation and decodes the response. Transport failures return a partial Result
+// alongside the error, like requester.Do.
+func Do(r Request) (*Result, error) {
+	if strings.TrimSpace(r.Query) == "" {
+		return nil, errors.New("GraphQL query is required")
+	}
+
+	payload := map[string]any{"query": r.Query}
+	if strings.TrimSpace(r.Variables) != "" {
+		var vars map[string]any
+		if err := json.Unmarshal([]byte(r.Variables), &vars); err != nil {
+			return nil, fmt.Errorf("variables must be a JSON object: %w", err)
+		}
+		payload["variables"] = vars
+	}
+	if r.OperationName != "" {
+		payload["operationName"] = r.OperationName
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Accept", "application/graphql-response+json, application/json")
+
+	resp, err := requester.Do(requester.Request{
+		URL:     r.URL,
+		Method:  http.MethodPost,
+		Headers: r.Headers,
+		Body:    string(body),
+		Timeout: r.Timeout,
+		Header:  header,
+		Retry:   r.Retry,
+		Auth:    r.Auth,
+		Signer:  r.Signer,
+		TLS:     r.TLS,
+		Net:     r.Net,
+		HTTP:    r.HTTP,
+		MaxBody: r.MaxBody,
+	})
+	if resp == nil {
+		return nil, err
+	}
+	result := &Result{Response: resp}
+	if err != nil {
+		return result, err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []Error         `json:"errors"`
+	}
+	if json.Unmarshal([]byte(resp.Body), &envelope) == nil && (envelope.Data != nil || envelope.Errors != nil) {
+		result.Envelope = true
+		result.Errors = envelope.Errors
+		if string(envelope.Data) != "null" {
+			result.Data = envelope.Data
+		}
+	}
+	return result, nil
+}
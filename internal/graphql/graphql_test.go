@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantData    string
+		wantErrors  int
+		wantProblem string
+	}{
+		{"data", 200, `{"data":{"user":{"id":"1"}}}`, `{"user":{"id":"1"}}`, 0, ""},
+		{"errors with 200", 200, `{"data":null,"errors":[{"message":"not found","path":["user",0],"locations":[{"line":1,"column":3}]}]}`, "", 1, "GraphQL error: not found (at user.0, line 1:3)"},
+		{"several errors", 200, `{"errors":[{"message":"a"},{"message":"b"}]}`, "", 2, "2 GraphQL errors, first: a"},
+		{"partial data", 200, `{"data":{"user":null},"errors":[{"message":"denied"}]}`, `{"user":null}`, 1, "GraphQL error: denied"},
+		{"http error with errors", 400, `{"errors":[{"message":"syntax"}]}`, "", 1, "GraphQL error: syntax"},
+		{"http error without errors", 502, `bad gateway`, "", 0, "HTTP 502"},
+		{"not an envelope", 200, `{"user":"1"}`, "", 0, "response is not a GraphQL JSON object"},
+		{"not json", 200, `<html></html>`, "", 0, "response is not a GraphQL JSON object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			result, err := Do(Request{URL: srv.URL, Query: "{ user { id } }", Timeout: "5s"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result.Data) != tt.wantData {
+				t.Errorf("data = %s, want %s", result.Data, tt.wantData)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("errors = %v, want %d", result.Errors, tt.wantErrors)
+			}
+			if got := result.Problem(); got != tt.wantProblem {
+				t.Errorf("Problem() = %q, want %q", got, tt.wantProblem)
+			}
+		})
+	}
+}
+
+func TestDoSendsOperation(t *testing.T) {
+	var got map[string]any
+	var accept, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		accept, contentType = r.Header.Get("Accept"), r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"data":{}}`)
+	}))
+	defer srv.Close()
+
+	_, err := Do(Request{URL: srv.URL, Query: "query Q($id: ID!) { user(id: $id) { id } }", Variables: `{"id": "7"}`,
+		OperationName: "Q", Headers: `{"Content-Type": "application/json"}`, Timeout: "5s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"query":         "query Q($id: ID!) { user(id: $id) { id } }",
+		"variables":     map[string]any{"id": "7"},
+		"operationName": "Q",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("payload = %v, want %v", got, want)
+	}
+	if !strings.Contains(accept, "application/graphql-response+json") || contentType != "application/json" {
+		t.Errorf("Accept = %q, Content-Type = %q", accept, contentType)
+	}
+}
+
+func TestDoRejects(t *testing.T) {
+	tests := map[string]Request{
+		"empty query":          {URL: "http://localhost", Query: "  "},
+		"variables not object": {URL: "http://localhost", Query: "{ a }", Variables: "[1]"},
+	}
+	for name, r := range tests {
+		if _, err := Do(r); err == nil {
+			t.Errorf("%s: Do succeeded", name)
+		}
+	}
+}
+
+func TestPrettyData(t *testing.T) {
+	if got := (&Result{}).PrettyData(); got != "null" {
+		t.Errorf("PrettyData() = %q, want null", got)
+	}
+	if got := (&Result{Data: json.RawMessage(`{"a":1}`)}).PrettyData(); got != "{\n  \"a\": 1\n}" {
+		t.Errorf("PrettyData() = %q", got)
+	}
+}
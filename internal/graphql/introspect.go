@@ -0,0 +1,152 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IntrospectionQuery fetches the root operation types and every named type with its fields.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) {
+        name
+        args { name type { ...TypeRef } }
+        type { ...TypeRef }
+      }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}`
+
+// Schema is the subset of the introspection result Pingify reports on.
+type Schema struct {
+	QueryType        *named       `json:"queryType"`
+	MutationType     *named       `json:"mutationType"`
+	SubscriptionType *named       `json:"subscriptionType"`
+	Types            []SchemaType `json:"types"`
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+// SchemaType is a named type in the schema.
+type SchemaType struct {
+	Kind   string  `json:"kind"`
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is a field of an object or interface type.
+type Field struct {
+	Name string  `json:"name"`
+	Args []Arg   `json:"args"`
+	Type TypeRef `json:"type"`
+}
+
+// Arg is a field argument.
+type Arg struct {
+	Name string  `json:"name"`
+	Type TypeRef `json:"type"`
+}
+
+// TypeRef is a possibly wrapped (list / non-null) type reference.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// String renders the reference in SDL form, e.g. [User!]!.
+func (t TypeRef) String() string {
+	switch {
+	case t.Kind == "NON_NULL" && t.OfType != nil:
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST" && t.OfType != nil:
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// Introspect runs IntrospectionQuery against the endpoint in r, ignoring r's query and variables.
+func Introspect(r Request) (*Schema, *Result, error) {
+	r.Query = IntrospectionQuery
+	r.Variables = ""
+	r.OperationName = "IntrospectionQuery"
+
+	result, err := Do(r)
+	if err != nil {
+		return nil, result, err
+	}
+	if problem := result.Problem(); problem != "" {
+		return nil, result, fmt.Errorf("introspection failed: %s", problem)
+	}
+
+	var data struct {
+		Schema *Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(result.Data, &data); err != nil || data.Schema == nil {
+		return nil, result, fmt.Errorf("introspection returned no __schema")
+	}
+	return data.Schema, result, nil
+}
+
+// Summary lists the root operations and the fields they expose, plus a count of user-defined types.
+func (s *Schema) Summary() string {
+	byName := map[string]SchemaType{}
+	kinds := map[string]int{}
+	for _, t := range s.Types {
+		byName[t.Name] = t
+		if !strings.HasPrefix(t.Name, "__") {
+			kinds[t.Kind]++
+		}
+	}
+
+	var b strings.Builder
+	for _, root := range []struct {
+		label string
+		ref   *named
+	}{{"Query", s.QueryType}, {"Mutation", s.MutationType}, {"Subscription", s.SubscriptionType}} {
+		if root.ref == nil {
+			continue
+		}
+		t := byName[root.ref.Name]
+		fmt.Fprintf(&b, "%s (%s): %d fields\n", root.label, root.ref.Name, len(t.Fields))
+		for _, f := range t.Fields {
+			args := make([]string, len(f.Args))
+			for i, a := range f.Args {
+				args[i] = a.Name + ": " + a.Type.String()
+			}
+			sig := f.Name
+			if len(args) > 0 {
+				sig += "(" + strings.Join(args, ", ") + ")"
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", sig, f.Type)
+		}
+	}
+
+	names := make([]string, 0, len(kinds))
+	for k := range kinds {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	counts := make([]string, len(names))
+	for i, k := range names {
+		counts[i] = fmt.Sprintf("%d %s", kinds[k], strings.ToLower(k))
+	}
+	fmt.Fprintf(&b, "Types: %s", strings.Join(counts, ", "))
+	return b.String()
+}
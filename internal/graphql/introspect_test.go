@@ -0,0 +1,93 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTypeRefString(t *testing.T) {
+	user := &TypeRef{Kind: "OBJECT", Name: "User"}
+	tests := []struct {
+		ref  TypeRef
+		want string
+	}{
+		{*user, "User"},
+		{TypeRef{Kind: "NON_NULL", OfType: user}, "User!"},
+		{TypeRef{Kind: "LIST", OfType: user}, "[User]"},
+		{TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "LIST", OfType: &TypeRef{Kind: "NON_NULL", OfType: user}}}, "[User!]!"},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+const schemaResponse = `{"data": {"__schema": {
+  "queryType": {"name": "Query"},
+  "mutationType": null,
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "user", "args": [{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}],
+       "type": {"kind": "OBJECT", "name": "User"}},
+      {"name": "users", "args": [], "type": {"kind": "LIST", "ofType": {"kind": "OBJECT", "name": "User"}}}
+    ]},
+    {"kind": "OBJECT", "name": "User", "fields": [{"name": "id", "args": [], "type": {"kind": "SCALAR", "name": "ID"}}]},
+    {"kind": "SCALAR", "name": "ID"},
+    {"kind": "OBJECT", "name": "__Type"}
+  ]
+}}}`
+
+func TestIntrospect(t *testing.T) {
+	var operation string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			OperationName string `json:"operationName"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		operation = payload.OperationName
+		io.WriteString(w, schemaResponse)
+	}))
+	defer srv.Close()
+
+	schema, _, err := Introspect(Request{URL: srv.URL, Query: "{ ignored }", Variables: "not json", Timeout: "5s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operation != "IntrospectionQuery" {
+		t.Errorf("operationName = %q", operation)
+	}
+	want := "Query (Query): 2 fields\n  user(id: ID!): User\n  users: [User]\nTypes: 2 object, 1 scalar"
+	if got := schema.Summary(); got != want {
+		t.Errorf("Summary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIntrospectFailures(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"errors", `{"errors": [{"message": "introspection disabled"}]}`, "introspection failed: GraphQL error: introspection disabled"},
+		{"no schema", `{"data": {}}`, "introspection returned no __schema"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+			_, result, err := Introspect(Request{URL: srv.URL, Timeout: "5s"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+			if result == nil {
+				t.Error("the result is not returned with the error")
+			}
+		})
+	}
+}
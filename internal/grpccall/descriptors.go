@@ -0,0 +1,188 @@
+package grpccall
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// resolveMethod finds the method descriptor in the protoset file, or through server reflection.
+func resolveMethod(ctx context.Context, conn *grpc.ClientConn, service, method, protoset string) (protoreflect.MethodDescriptor, error) {
+	var files *protoregistry.Files
+	var err error
+	if protoset != "" {
+		files, err = loadProtoset(protoset)
+	} else {
+		files, err = reflectFiles(ctx, conn, service)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found: %w", service, err)
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, fmt.Errorf("method %s not found in %s", method, service)
+	}
+	return md, nil
+}
+
+func loadProtoset(path string) (*protoregistry.Files, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse protoset %s: %w", path, err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protoset %s (was it built with --include_imports?): %w", path, err)
+	}
+	return files, nil
+}
+
+// reflectFiles downloads the file defining service, and everything it imports, with grpc.reflection.v1.
+func reflectFiles(ctx context.Context, conn *grpc.ClientConn, service string) (*protoregistry.Files, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection unavailable (pass a protoset instead): %w", err)
+	}
+	defer stream.CloseSend()
+
+	fdps := map[string]*descriptorpb.FileDescriptorProto{}
+	fetch := func(req *rpb.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return fmt.Errorf("reflection stream closed")
+		}
+		if err != nil {
+			return err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return fmt.Errorf("reflection error: %s", e.GetErrorMessage())
+		}
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fdp := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, fdp); err != nil {
+				return fmt.Errorf("invalid descriptor from reflection: %w", err)
+			}
+			fdps[fdp.GetName()] = fdp
+		}
+		return nil
+	}
+
+	err = fetch(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s via reflection: %w", service, err)
+	}
+
+	// Servers usually send the whole import closure at once; ask for anything still missing.
+	for {
+		missing := ""
+		for _, fdp := range fdps {
+			for _, dep := range fdp.GetDependency() {
+				if _, ok := fdps[dep]; ok {
+					continue
+				}
+				if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+					continue
+				}
+				missing = dep
+			}
+		}
+		if missing == "" {
+			break
+		}
+		err := fetch(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: missing},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s via reflection: %w", missing, err)
+		}
+		if _, ok := fdps[missing]; !ok {
+			return nil, fmt.Errorf("server did not return %s", missing)
+		}
+	}
+
+	return buildFiles(fdps)
+}
+
+// buildFiles registers the descriptors in dependency order, falling back to the linked-in
+// well-known types for imports the server didn't send.
+func buildFiles(fdps map[string]*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	files := &protoregistry.Files{}
+	r := resolver{local: files}
+
+	var add func(name string) error
+	add = func(name string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+		fdp, ok := fdps[name]
+		if !ok {
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+				return nil
+			}
+			return fmt.Errorf("missing descriptor for %s", name)
+		}
+		for _, dep := range fdp.GetDependency() {
+			if err := add(dep); err != nil {
+				return err
+			}
+		}
+		fd, err := protodesc.NewFile(fdp, r)
+		if err != nil {
+			return fmt.Errorf("invalid descriptor %s: %w", name, err)
+		}
+		return files.RegisterFile(fd)
+	}
+
+	for name := range fdps {
+		if err := add(name); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// resolver looks descriptors up in the reflected files first and the global registry second.
+type resolver struct {
+	local *protoregistry.Files
+}
+
+func (r resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
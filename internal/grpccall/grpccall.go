@@ -0,0 +1,211 @@
+package grpccall
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Request describes a gRPC call. Without a Method it performs a grpc.health.v1 health check of Service.
+type Request struct {
+	// Target is host:port; a grpc:// prefix implies Plaintext and grpcs:// forces TLS.
+	Target    string
+	Plaintext bool
+	Service   string
+	Method    string
+	Body      string
+	Metadata  map[string]string
+	Timeout   time.Duration
+	// Protoset is a FileDescriptorSet (protoc --include_imports -o) used instead of server reflection.
+	Protoset string
+	// TLS replaces the default client TLS settings when the call is not Plaintext.
+	TLS *tls.Config
+	// Dialer replaces the TCP dialer, e.g. to pin addresses or go through SOCKS.
+	Dialer func(ctx context.Context, addr string) (net.Conn, error)
+}
+
+// Response is the outcome of a call. A non-OK status is reported in Code rather than as an error.
+type Response struct {
+	Code     codes.Code
+	Message  string
+	Body     string
+	Header   metadata.MD
+	Duration time.Duration
+	// HealthStatus is set for health checks (e.g. SERVING).
+	HealthStatus string
+}
+
+// Do dials the target and performs the call. Errors are returned only when the call could not be
+// attempted, e.g. an invalid target or an unresolvable method.
+func Do(r Request) (*Response, error) {
+	target, plaintext := parseTarget(r.Target, r.Plaintext)
+	if target == "" {
+		return nil, errors.New("gRPC target is required")
+	}
+
+	creds := insecure.NewCredentials()
+	if !plaintext {
+		tlsConfig := r.TLS
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if r.Dialer != nil {
+		dialOpts = append(dialOpts, grpc.WithContextDialer(r.Dialer))
+	}
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	defer conn.Close()
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if len(r.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(r.Metadata))
+	}
+
+	if r.Method == "" {
+		return health(ctx, conn, r.Service)
+	}
+	return invoke(ctx, conn, r)
+}
+
+func parseTarget(target string, plaintext bool) (string, bool) {
+	switch {
+	case strings.HasPrefix(target, "grpc://"):
+		return strings.TrimPrefix(target, "grpc://"), true
+	case strings.HasPrefix(target, "grpcs://"):
+		return strings.TrimPrefix(target, "grpcs://"), false
+	}
+	return target, plaintext
+}
+
+func health(ctx context.Context, conn *grpc.ClientConn, service string) (*Response, error) {
+	var header metadata.MD
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service}, grpc.Header(&header))
+	out := &Response{Duration: time.Since(start), Header: header}
+	setStatus(out, err)
+	if err == nil {
+		out.HealthStatus = resp.GetStatus().String()
+		out.Body = marshal(resp)
+	}
+	return out, nil
+}
+
+func invoke(ctx context.Context, conn *grpc.ClientConn, r Request) (*Response, error) {
+	service, method, err := splitMethod(r.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := resolveMethod(ctx, conn, service, method, r.Protoset)
+	if err != nil {
+		return nil, err
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("%s is a streaming method; only unary calls are supported", r.Method)
+	}
+
+	in := dynamicpb.NewMessage(md.Input())
+	body := r.Body
+	if strings.TrimSpace(body) == "" {
+		body = "{}"
+	}
+	if err := protojson.Unmarshal([]byte(body), in); err != nil {
+		return nil, fmt.Errorf("request body does not match %s: %w", md.Input().FullName(), err)
+	}
+	out := dynamicpb.NewMessage(md.Output())
+
+	var header metadata.MD
+	start := time.Now()
+	err = conn.Invoke(ctx, "/"+service+"/"+method, in, out, grpc.Header(&header))
+	resp := &Response{Duration: time.Since(start), Header: header}
+	setStatus(resp, err)
+	if err == nil {
+		resp.Body = marshal(out)
+	}
+	return resp, nil
+}
+
+func setStatus(resp *Response, err error) {
+	st := status.Convert(err)
+	resp.Code = st.Code()
+	resp.Message = st.Message()
+}
+
+func marshal(m proto.Message) string {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m)
+	if err != nil {
+		return fmt.Sprintf("<failed to encode response: %v>", err)
+	}
+	return string(data)
+}
+
+// splitMethod accepts "pkg.Service/Method" or "pkg.Service.Method".
+func splitMethod(full string) (string, string, error) {
+	full = strings.TrimPrefix(full, "/")
+	i := strings.LastIndexAny(full, "/.")
+	if i <= 0 || i == len(full)-1 {
+		return "", "", fmt.Errorf("invalid gRPC method %q, expected package.Service/Method", full)
+	}
+	return full[:i], full[i+1:], nil
+}
+
+// ParseCode turns a status code name ("OK", "NOT_FOUND", "NotFound") or number into a code.
+func ParseCode(s string) (codes.Code, error) {
+	if s == "" {
+		return codes.OK, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return codes.Code(n), nil
+	}
+
+	name := strings.ToUpper(s)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.EqualFold(c.String(), s) {
+			return c, nil
+		}
+	}
+	var c codes.Code
+	if err := c.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+		return 0, fmt.Errorf("unknown gRPC status code %q", s)
+	}
+	return c, nil
+}
+
+// ParseMetadata reads metadata from the same JSON object format used for HTTP headers.
+func ParseMetadata(raw string) (map[string]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var md map[string]string
+	if err := json.Unmarshal([]byte(raw), &md); err != nil {
+		return nil, fmt.Errorf("metadata must be a JSON object of strings: %w", err)
+	}
+	return md, nil
+}
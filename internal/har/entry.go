@@ -0,0 +1,276 @@
+package har
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+)
+
+// redacted lists headers whose values are replaced, so HAR files can be shared without leaking
+// credentials. Authorization keeps its scheme.
+var redacted = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"X-Api-Key":            true,
+	"Api-Key":              true,
+	"X-Amz-Security-Token": true,
+}
+
+// FromResponse records a request made with requester.Do: one entry per redirect hop followed,
+// then the final response. A request that got no response is recorded with status 0 and the
+// error as its comment.
+func FromResponse(resp *requester.Response, err error) []Entry {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	sent := resp.Request
+
+	var entries []Entry
+	started := resp.Started
+	for _, h := range resp.Redirects {
+		e := Entry{
+			StartedDateTime: started,
+			Request:         newRequest(h.Method, h.URL, resp.Proto, nil),
+			Response: Response{
+				Status:      h.StatusCode,
+				StatusText:  http.StatusText(h.StatusCode),
+				HTTPVersion: proto(resp.Proto),
+				Cookies:     []Cookie{},
+				Headers:     []NameValue{{Name: "Location", Value: h.Location}},
+				Content:     Content{MimeType: "x-unknown"},
+				RedirectURL: h.Location,
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings: Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: ms(h.Duration)},
+		}
+		e.Time = e.Timings.total()
+		entries = append(entries, e)
+		started = started.Add(h.Duration)
+	}
+
+	e := Entry{StartedDateTime: started, Request: newRequest(sent.Method, sent.URL.String(), resp.Proto, sent.Header)}
+	if body := sentBody(sent); body != "" {
+		e.Request.PostData = postData(sent.Header.Get("Content-Type"), body)
+		e.Request.BodySize = len(body)
+	}
+
+	// The trace phases describe the final hop, which started once the redirects were followed.
+	e.Timings = timings(started, resp.Phases)
+	e.Time = e.Timings.total()
+
+	if err != nil && resp.StatusCode == 0 {
+		e.Response = Response{Cookies: []Cookie{}, Headers: []NameValue{}, Content: Content{MimeType: "x-unknown"}, HeadersSize: -1, BodySize: -1, Comment: err.Error()}
+		e.Comment = err.Error()
+		return append(entries, e)
+	}
+	e.Response = newResponse(resp)
+	if err != nil {
+		e.Comment = err.Error()
+	}
+	return append(entries, e)
+}
+
+func newRequest(method, rawURL, protocol string, header http.Header) Request {
+	r := Request{
+		Method:      method,
+		URL:         rawURL,
+		HTTPVersion: proto(protocol),
+		Cookies:     []Cookie{},
+		Headers:     headers(header),
+		QueryString: []NameValue{},
+		HeadersSize: -1,
+	}
+	for _, c := range (&http.Request{Header: header}).Cookies() {
+		r.Cookies = append(r.Cookies, Cookie{Name: c.Name, Value: c.Value})
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		for _, kv := range strings.Split(u.RawQuery, "&") {
+			if kv == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(kv, "=")
+			name, _ = url.QueryUnescape(name)
+			value, _ = url.QueryUnescape(value)
+			r.QueryString = append(r.QueryString, NameValue{Name: name, Value: value})
+		}
+	}
+	return r
+}
+
+func newResponse(resp *requester.Response) Response {
+	r := Response{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: proto(resp.Proto),
+		Cookies:     []Cookie{},
+		Headers:     headers(resp.Header),
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    resp.WireSize,
+	}
+	for _, c := range (&http.Response{Header: resp.Header}).Cookies() {
+		cookie := Cookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.UTC().Format(time.RFC3339)
+		}
+		r.Cookies = append(r.Cookies, cookie)
+	}
+
+	r.Content = Content{Size: resp.Size, MimeType: resp.Header.Get("Content-Type")}
+	if r.Content.MimeType == "" {
+		r.Content.MimeType = "x-unknown"
+	}
+	if resp.Size > resp.WireSize {
+		r.Content.Compression = resp.Size - resp.WireSize
+	}
+	switch {
+	case resp.Body == "" && resp.Size > 0:
+		r.Content.Comment = "body written to a file, not recorded"
+	case resp.Binary:
+		r.Content.Text = base64.StdEncoding.EncodeToString([]byte(resp.Body))
+		r.Content.Encoding = "base64"
+	default:
+		r.Content.Text = resp.Body
+	}
+	if resp.Truncated {
+		r.Content.Comment = "body truncated at the max body size"
+	}
+	return r
+}
+
+// sentBody returns the body of a sent request, which requester keeps replayable.
+func sentBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	return string(data)
+}
+
+// postData records a request body, listing the fields of URL-encoded forms.
+func postData(contentType, body string) *PostData {
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	p := &PostData{MimeType: contentType, Text: body}
+	if !utf8.ValidString(body) {
+		p.Text = ""
+		return p
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(body); err == nil {
+			for name, vs := range values {
+				for _, v := range vs {
+					p.Params = append(p.Params, Param{Name: name, Value: v})
+				}
+			}
+			sort.Slice(p.Params, func(i, j int) bool { return p.Params[i].Name < p.Params[j].Name })
+		}
+	}
+	return p
+}
+
+// headers lists a header map sorted by name, redacting credentials.
+func headers(h http.Header) []NameValue {
+	list := []NameValue{}
+	for name, values := range h {
+		for _, v := range values {
+			if redacted[http.CanonicalHeaderKey(name)] {
+				v = redact(v)
+			}
+			list = append(list, NameValue{Name: name, Value: v})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// redact hides a credential, keeping an auth scheme such as "Bearer".
+func redact(v string) string {
+	if scheme, _, ok := strings.Cut(v, " "); ok {
+		return scheme + " REDACTED"
+	}
+	return "REDACTED"
+}
+
+// timings converts the trace phases of a request that began at start.
+func timings(start time.Time, phases []requester.Phase) Timings {
+	t := Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+	byName := map[string]requester.Phase{}
+	for _, p := range phases {
+		byName[p.Name] = p
+	}
+	dns, hasDNS := byName["dns"]
+	connect, hasConnect := byName["connect"]
+	tlsPhase, hasTLS := byName["tls"]
+
+	// Sending starts once the connection is ready; a reused connection is ready at once.
+	ready := start
+	if hasDNS {
+		t.DNS = ms(dns.Duration())
+		t.Blocked = ms(dns.Start.Sub(start))
+		ready = dns.End
+	}
+	if hasConnect {
+		end := connect.End
+		if hasTLS {
+			t.SSL = ms(tlsPhase.Duration())
+			end = tlsPhase.End
+		}
+		t.Connect = ms(end.Sub(connect.Start))
+		if !hasDNS {
+			t.Blocked = ms(connect.Start.Sub(start))
+		}
+		ready = end
+	}
+	if send, ok := byName["send"]; ok && send.End.After(ready) {
+		t.Send = ms(send.End.Sub(ready))
+	}
+	t.Wait = ms(phase(phases, "wait"))
+	t.Receive = ms(phase(phases, "receive"))
+	return t
+}
+
+// total is the entry time: every phase that happened, with SSL already counted in Connect.
+func (t Timings) total() float64 {
+	sum := 0.0
+	for _, v := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if v > 0 {
+			sum += v
+		}
+	}
+	return sum
+}
+
+func phase(phases []requester.Phase, name string) time.Duration {
+	for _, p := range phases {
+		if p.Name == name {
+			return p.Duration()
+		}
+	}
+	return 0
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func proto(p string) string {
+	if p == "" {
+		return "HTTP/1.1"
+	}
+	return p
+}
@@ -0,0 +1,138 @@
+package har
+
+import (
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+)
+
+// header returns the first value of name in a recorded header list.
+func header(list []NameValue, name string) string {
+	for _, h := range list {
+		if http.CanonicalHeaderKey(h.Name) == http.CanonicalHeaderKey(name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+func TestFromResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, "/items?tag=a%20b&page=2", http.StatusFound)
+		case "/logo":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G', 0})
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer srv.Close()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + lis.Addr().String()
+	lis.Close()
+
+	tests := []struct {
+		name  string
+		req   requester.Request
+		check func(t *testing.T, entries []Entry)
+	}{
+		{"redirect, query and credentials", requester.Request{URL: srv.URL + "/start", Method: http.MethodGet,
+			Headers: `{"Authorization": "Bearer s3cret", "X-Api-Key": "k3y", "Cookie": "theme=dark"}`},
+			func(t *testing.T, entries []Entry) {
+				if len(entries) != 2 {
+					t.Fatalf("entries = %d, want the hop and the final response", len(entries))
+				}
+				hop, final := entries[0], entries[1]
+				if hop.Request.URL != srv.URL+"/start" || hop.Response.Status != http.StatusFound || hop.Response.RedirectURL != "/items?tag=a%20b&page=2" {
+					t.Errorf("hop = %+v", hop)
+				}
+				if final.StartedDateTime.Before(hop.StartedDateTime) {
+					t.Errorf("final entry starts before the hop")
+				}
+				if want := []NameValue{{"tag", "a b"}, {"page", "2"}}; !reflect.DeepEqual(final.Request.QueryString, want) {
+					t.Errorf("query = %v, want %v", final.Request.QueryString, want)
+				}
+				if got := header(final.Request.Headers, "Authorization"); got != "Bearer REDACTED" {
+					t.Errorf("Authorization = %q", got)
+				}
+				if got := header(final.Request.Headers, "X-Api-Key"); got != "REDACTED" {
+					t.Errorf("X-Api-Key = %q", got)
+				}
+				if want := []Cookie{{Name: "theme", Value: "dark"}}; !reflect.DeepEqual(final.Request.Cookies, want) {
+					t.Errorf("request cookies = %v", final.Request.Cookies)
+				}
+				resp := final.Response
+				if resp.Status != 200 || resp.Content.Text != `{"ok":true}` || resp.Content.MimeType != "application/json" {
+					t.Errorf("response = %+v", resp)
+				}
+				if len(resp.Cookies) != 1 || resp.Cookies[0].Name != "session" || !resp.Cookies[0].HTTPOnly {
+					t.Errorf("response cookies = %+v", resp.Cookies)
+				}
+			}},
+		{"url-encoded form", requester.Request{URL: srv.URL + "/items", Method: http.MethodPost,
+			Body: "b=2&a=1&a=0", ContentType: "application/x-www-form-urlencoded"},
+			func(t *testing.T, entries []Entry) {
+				p := entries[0].Request.PostData
+				if p == nil || p.Text != "b=2&a=1&a=0" || entries[0].Request.BodySize != 11 {
+					t.Fatalf("postData = %+v", p)
+				}
+				if want := []Param{{Name: "a", Value: "1"}, {Name: "a", Value: "0"}, {Name: "b", Value: "2"}}; !reflect.DeepEqual(p.Params, want) {
+					t.Errorf("params = %v, want %v", p.Params, want)
+				}
+			}},
+		{"json body", requester.Request{URL: srv.URL + "/items", Method: http.MethodPost, Body: `{"a":1}`},
+			func(t *testing.T, entries []Entry) {
+				if p := entries[0].Request.PostData; p == nil || p.MimeType != "application/json" || p.Text != `{"a":1}` || p.Params != nil {
+					t.Errorf("postData = %+v", p)
+				}
+			}},
+		{"binary response", requester.Request{URL: srv.URL + "/logo", Method: http.MethodGet},
+			func(t *testing.T, entries []Entry) {
+				c := entries[0].Response.Content
+				if c.Encoding != "base64" || c.Text != base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G', 0}) || c.Size != 5 {
+					t.Errorf("content = %+v", c)
+				}
+			}},
+		{"no response", requester.Request{URL: closed, Method: http.MethodGet, Timeout: "1s"},
+			func(t *testing.T, entries []Entry) {
+				e := entries[0]
+				if len(entries) != 1 || e.Response.Status != 0 || e.Comment == "" || e.Response.Comment != e.Comment {
+					t.Errorf("entries = %+v", entries)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req.Timeout == "" {
+				tt.req.Timeout = "5s"
+			}
+			resp, err := requester.Do(tt.req)
+			entries := FromResponse(resp, err)
+			if len(entries) == 0 {
+				t.Fatalf("no entries for %v", err)
+			}
+			for _, e := range entries {
+				if e.Time != e.Timings.total() || e.Time < 0 {
+					t.Errorf("time = %v, timings = %+v", e.Time, e.Timings)
+				}
+			}
+			tt.check(t, entries)
+		})
+	}
+
+	if entries := FromResponse(nil, nil); entries != nil {
+		t.Errorf("FromResponse(nil) = %v", entries)
+	}
+}
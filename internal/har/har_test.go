@@ -0,0 +1,78 @@
+package har
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	secret.Register("har-s3cret-value")
+	path := filepath.Join(t.TempDir(), "out", "calls.har")
+	f := New(Entry{Request: Request{Method: "GET", URL: "https://api.example.com/?key=har-s3cret-value"}})
+	if err := f.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "har-s3cret-value") {
+		t.Error("the secret was written unmasked")
+	}
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Log.Version != "1.2" || got.Log.Creator.Name != "pingify" || len(got.Log.Entries) != 1 {
+		t.Errorf("log = %+v", got.Log)
+	}
+	if u := got.Log.Entries[0].Request.URL; u != "https://api.example.com/?key="+secret.Masked {
+		t.Errorf("url = %q", u)
+	}
+}
+
+func TestReadRejects(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"not json":   "<html>",
+		"no entries": `{"other": {}}`,
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name+".har")
+		os.WriteFile(path, []byte(content), 0600)
+		if _, err := Read(path); err == nil || !strings.Contains(err.Error(), "is not a HAR file") {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+	if _, err := Read(filepath.Join(dir, "missing.har")); !os.IsNotExist(err) {
+		t.Errorf("missing file: err = %v", err)
+	}
+}
+
+func TestAppendConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor.har")
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Append(path, Entry{Request: Request{Method: "GET"}}, Entry{Request: Request{Method: "POST"}}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	f, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Log.Entries) != 20 {
+		t.Errorf("entries = %d, want 20", len(f.Log.Entries))
+	}
+}
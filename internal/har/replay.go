@@ -0,0 +1,76 @@
+package har
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// transportHeaders are set by the HTTP client itself and are not resent on replay.
+var transportHeaders = map[string]bool{
+	"Host": true, "Content-Length": true, "Connection": true, "Keep-Alive": true,
+	"Transfer-Encoding": true, "Upgrade": true, "Te": true, "Proxy-Connection": true,
+}
+
+// Header returns the headers to resend, dropping HTTP/2 pseudo-headers (":authority"), those
+// the transport sets and redacted credentials. Repeated headers are joined; cookies with "; ",
+// others with ", ".
+func (r Request) Header() map[string]string {
+	header := map[string]string{}
+	for _, h := range r.Headers {
+		name := http.CanonicalHeaderKey(h.Name)
+		if strings.HasPrefix(h.Name, ":") || transportHeaders[name] || isRedacted(h) {
+			continue
+		}
+		if prev, ok := header[name]; ok {
+			sep := ", "
+			if name == "Cookie" {
+				sep = "; "
+			}
+			header[name] = prev + sep + h.Value
+			continue
+		}
+		header[name] = h.Value
+	}
+	return header
+}
+
+// Redacted reports whether the request carries credentials that were redacted when it was
+// recorded, so replaying it needs them supplied again.
+func (r Request) Redacted() bool {
+	for _, h := range r.Headers {
+		if isRedacted(h) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRedacted(h NameValue) bool {
+	return redacted[http.CanonicalHeaderKey(h.Name)] && (h.Value == "REDACTED" || strings.HasSuffix(h.Value, " REDACTED"))
+}
+
+// Body returns the body to resend and its content type: the recorded text, or the form
+// parameters URL-encoded when only those were kept. Uploaded files cannot be rebuilt.
+func (r Request) Body() (string, string, error) {
+	p := r.PostData
+	if p == nil {
+		return "", "", nil
+	}
+	if p.Text != "" || len(p.Params) == 0 {
+		return p.Text, p.MimeType, nil
+	}
+	values := url.Values{}
+	for _, param := range p.Params {
+		if param.FileName != "" {
+			return "", "", fmt.Errorf("the recorded body uploads %s, which the HAR file does not contain", param.FileName)
+		}
+		values.Add(param.Name, param.Value)
+	}
+	contentType := p.MimeType
+	if contentType == "" || strings.HasPrefix(contentType, "multipart/") {
+		contentType = "application/x-www-form-urlencoded"
+	}
+	return values.Encode(), contentType, nil
+}
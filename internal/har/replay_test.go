@@ -0,0 +1,70 @@
+package har
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRequestHeader(t *testing.T) {
+	r := Request{Headers: []NameValue{
+		{":authority", "api.example.com"},
+		{"host", "api.example.com"},
+		{"Content-Length", "12"},
+		{"Accept", "application/json"},
+		{"accept", "text/plain"},
+		{"Cookie", "a=1"},
+		{"cookie", "b=2"},
+		{"Authorization", "Bearer REDACTED"},
+		{"X-Api-Key", "REDACTED"},
+		{"X-Request-Id", "REDACTED"},
+	}}
+	want := map[string]string{
+		"Accept":       "application/json, text/plain",
+		"Cookie":       "a=1; b=2",
+		"X-Request-Id": "REDACTED", // not a credential header, so sent as recorded
+	}
+	if got := r.Header(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Header() = %v, want %v", got, want)
+	}
+	if !r.Redacted() {
+		t.Error("Redacted() = false with redacted credentials")
+	}
+	if (Request{Headers: []NameValue{{"Authorization", "Bearer t0k"}}}).Redacted() {
+		t.Error("Redacted() = true for a recorded credential")
+	}
+}
+
+func TestRequestBody(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             *PostData
+		wantBody, wantCT string
+		errText          string
+	}{
+		{"none", nil, "", "", ""},
+		{"text", &PostData{MimeType: "application/json", Text: `{"a":1}`}, `{"a":1}`, "application/json", ""},
+		{"text over params", &PostData{MimeType: "application/x-www-form-urlencoded", Text: "a=1", Params: []Param{{Name: "a", Value: "2"}}}, "a=1", "application/x-www-form-urlencoded", ""},
+		{"params only", &PostData{MimeType: "application/x-www-form-urlencoded; charset=utf-8", Params: []Param{{Name: "q", Value: "a b"}, {Name: "n", Value: "1"}}},
+			"n=1&q=a+b", "application/x-www-form-urlencoded; charset=utf-8", ""},
+		{"multipart fields", &PostData{MimeType: "multipart/form-data; boundary=x", Params: []Param{{Name: "title", Value: "Report"}}}, "title=Report", "application/x-www-form-urlencoded", ""},
+		{"uploaded file", &PostData{MimeType: "multipart/form-data", Params: []Param{{Name: "logo", FileName: "logo.png"}}}, "", "", "uploads logo.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType, err := Request{PostData: tt.data}.Body()
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Errorf("err = %v, want it to mention %q", err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body != tt.wantBody || contentType != tt.wantCT {
+				t.Errorf("Body() = %q, %q; want %q, %q", body, contentType, tt.wantBody, tt.wantCT)
+			}
+		})
+	}
+}
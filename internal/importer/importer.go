@@ -0,0 +1,234 @@
+// Package importer converts Postman collections and environments and Insomnia exports into
+// Pingify collections and environment files.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/environment"
+	"gopkg.in/yaml.v3"
+)
+
+// Result is a converted export. Collection is nil when only environments were imported.
+type Result struct {
+	Collection   *collection.Collection
+	Environments map[string]*environment.Environment
+
+	// Unsupported lists what could not be converted, such as scripts, so it can be redone by hand.
+	Unsupported []string
+}
+
+var (
+	// template matches {{name}}, {{ _.name }} and {{$dynamic}} references.
+	template = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+	// tag matches Insomnia template tags such as {% uuid 'v4' %}.
+	tag = regexp.MustCompile(`\{%\s*(\w+)\s*([^%]*?)\s*%\}`)
+
+	notAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// dynamicNames maps the dynamic values of Postman and Insomnia to Pingify's.
+var dynamicNames = map[string]string{
+	"$guid":         "$uuid",
+	"$uuid":         "$uuid",
+	"$randomUUID":   "$uuid",
+	"$timestamp":    "$timestamp",
+	"$isoTimestamp": "$isoTimestamp",
+	"$randomInt":    "$randomInt",
+}
+
+// converter collects what could not be converted while a file is converted.
+type converter struct {
+	result *Result
+	noted  map[string]bool
+}
+
+func newConverter() *converter {
+	return &converter{
+		result: &Result{Environments: map[string]*environment.Environment{}},
+		noted:  map[string]bool{},
+	}
+}
+
+func (c *converter) note(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if !c.noted[msg] {
+		c.noted[msg] = true
+		c.result.Unsupported = append(c.result.Unsupported, msg)
+	}
+}
+
+// text rewrites the variable references and template tags of s in Pingify's syntax. where names
+// the request or environment s belongs to, for the notes on what is left as is.
+func (c *converter) text(s, where string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	s = template.ReplaceAllStringFunc(s, func(m string) string {
+		name := strings.TrimPrefix(template.FindStringSubmatch(m)[1], "_.")
+		if strings.HasPrefix(name, "$") {
+			if dynamic, ok := dynamicNames[name]; ok {
+				return "{{" + dynamic + "}}"
+			}
+			c.note("%s: dynamic value %s has no equivalent", where, m)
+			return m
+		}
+		if !environment.ValidName(name) {
+			c.note("%s: %s is not a valid variable name; rename the variable", where, m)
+			return m
+		}
+		return "{{" + name + "}}"
+	})
+	return tag.ReplaceAllStringFunc(s, func(m string) string {
+		sub := tag.FindStringSubmatch(m)
+		first, _, _ := strings.Cut(sub[2], ",")
+		args := strings.Trim(first, `'" `)
+		switch {
+		case sub[1] == "uuid":
+			return "{{$uuid}}"
+		case sub[1] == "now" && args == "unix":
+			return "{{$timestamp}}"
+		case sub[1] == "now" && args == "millis":
+			return "{{$timestampMs}}"
+		case sub[1] == "now" && (args == "" || args == "iso-8601"):
+			return "{{$isoTimestamp}}"
+		}
+		if sub[1] == "response" {
+			c.note("%s: template tag %s not converted; capture the value in the earlier request instead", where, m)
+			return m
+		}
+		c.note("%s: template tag %s has no equivalent", where, m)
+		return m
+	})
+}
+
+// environment converts the variables of an environment; secret ones are read from environment
+// variables, as environment files only hold references to secrets.
+func (c *converter) environment(name string, values map[string]string, secrets map[string]bool) {
+	where := "environment " + name
+	env := &environment.Environment{}
+	for _, key := range sortedKeys(values) {
+		if !environment.ValidName(key) {
+			c.note("%s: %q is not a valid variable name and was left out", where, key)
+			continue
+		}
+		if secrets[key] {
+			if env.Secrets == nil {
+				env.Secrets = map[string]string{}
+			}
+			envName := strings.ToUpper(strings.Trim(notAlphanumeric.ReplaceAllString(key, "_"), "_"))
+			env.Secrets[key] = "env:" + envName
+			c.note("%s: secret %s is read from $%s; export it before running", where, key, envName)
+			continue
+		}
+		value := c.text(values[key], where)
+		if environment.HasReferences(value) {
+			c.note("%s: variable %s refers to other variables, which environments cannot; write its value out", where, key)
+		}
+		if env.Variables == nil {
+			env.Variables = map[string]string{}
+		}
+		env.Variables[key] = value
+	}
+	c.result.Environments[name] = env
+}
+
+// plainSecret notes credentials written into the collection rather than referenced as variables.
+func (c *converter) plainSecret(where, field, value string) {
+	if value != "" && !environment.HasReferences(value) {
+		c.note("%s: the %s is written in the collection; move it to an environment secret", where, field)
+	}
+}
+
+// checkAuth notes the plain credentials of a converted auth.
+func (c *converter) checkAuth(where string, a *config.Auth) {
+	if a == nil {
+		return
+	}
+	c.plainSecret(where, "password", a.Password)
+	c.plainSecret(where, "token", a.Token)
+	c.plainSecret(where, "API key", a.APIKeyValue)
+	c.plainSecret(where, "client secret", a.ClientSecret)
+}
+
+// contentTypes maps Postman raw body languages to the Content-Type Postman sends for them.
+var contentTypes = map[string]string{
+	"text":       "text/plain",
+	"javascript": "application/javascript",
+	"html":       "text/html",
+	"xml":        "application/xml",
+}
+
+// setHeader adds a header unless the request already has it, in any case.
+func setHeader(r *collection.Request, name, value string) {
+	for k := range r.Headers {
+		if strings.EqualFold(k, name) {
+			return
+		}
+	}
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+	r.Headers[name] = value
+}
+
+// addHeader adds a header, joining repeated ones with a comma.
+func addHeader(r *collection.Request, name, value string) {
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+	if v, ok := r.Headers[name]; ok {
+		value = v + ", " + value
+	}
+	r.Headers[name] = value
+}
+
+// Slug turns a collection or environment name into a file name.
+func Slug(name string) string {
+	slug := strings.Trim(notAlphanumeric.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "collection"
+	}
+	return slug
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinName names a request after the folders it is in.
+func joinName(folders []string, name string) string {
+	return strings.Join(append(append([]string(nil), folders...), name), " / ")
+}
+
+// WriteYAML writes a converted collection or environment to path, creating its directory. An
+// existing file is only replaced when overwrite is set.
+func WriteYAML(path string, v any, overwrite bool) error {
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists; use --force to replace it", path)
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
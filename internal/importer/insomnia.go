@@ -0,0 +1,310 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/environment"
+)
+
+type insomniaExport struct {
+	Type      string             `json:"_type"`
+	Format    int                `json:"__export_format"`
+	Resources []insomniaResource `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID          string  `json:"_id"`
+	Type        string  `json:"_type"`
+	ParentID    string  `json:"parentId"`
+	Name        string  `json:"name"`
+	MetaSortKey float64 `json:"metaSortKey"`
+
+	// Requests.
+	Method         string         `json:"method"`
+	URL            string         `json:"url"`
+	Body           insomniaBody   `json:"body"`
+	Headers        []insomniaPair `json:"headers"`
+	Parameters     []insomniaPair `json:"parameters"`
+	Authentication map[string]any `json:"authentication"`
+
+	// Folder variables and environments.
+	Environment map[string]any `json:"environment"`
+	Data        map[string]any `json:"data"`
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	FileName string         `json:"fileName"`
+	Params   []insomniaPair `json:"params"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	FileName string `json:"fileName"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Insomnia converts an Insomnia export (format 4, "Insomnia v4 (JSON)" in the export dialog). The
+// base environment becomes the collection's variables and each sub environment an environment
+// file; folder environments are merged into the collection's variables.
+func Insomnia(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var export insomniaExport
+	if err := json.Unmarshal(data, &export); err != nil || export.Type != "export" {
+		return nil, fmt.Errorf("%s is not an Insomnia export; export the data as Insomnia v4 (JSON)", path)
+	}
+	if export.Format != 4 {
+		return nil, fmt.Errorf("%s uses export format %d; export the data as Insomnia v4 (JSON)", path, export.Format)
+	}
+
+	c := newConverter()
+	children := map[string][]insomniaResource{}
+	var workspaces []insomniaResource
+	for _, r := range export.Resources {
+		if r.Type == "workspace" {
+			workspaces = append(workspaces, r)
+		}
+		children[r.ParentID] = append(children[r.ParentID], r)
+	}
+	for _, list := range children {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].MetaSortKey < list[j].MetaSortKey })
+	}
+	if len(workspaces) == 0 {
+		return nil, errors.New("the export has no workspace")
+	}
+
+	col := &collection.Collection{Name: workspaces[0].Name}
+	c.result.Collection = col
+	for _, w := range workspaces {
+		var folders []string
+		if len(workspaces) > 1 {
+			folders = []string{w.Name}
+		}
+		for _, env := range children[w.ID] {
+			if env.Type != "environment" {
+				continue
+			}
+			c.variables(col, env.Data, "base environment")
+			for _, sub := range children[env.ID] {
+				if sub.Type == "environment" {
+					c.environment(sub.Name, flatten(sub.Data), nil)
+				}
+			}
+		}
+		c.insomniaItems(children, w.ID, folders, nil)
+	}
+	return c.result, nil
+}
+
+// variables adds the values of an environment or folder to the collection's variables.
+func (c *converter) variables(col *collection.Collection, data map[string]any, where string) {
+	for key, value := range flatten(data) {
+		if !environment.ValidName(key) {
+			c.note("%s: %q is not a valid variable name and was left out", where, key)
+			continue
+		}
+		if col.Variables == nil {
+			col.Variables = map[string]string{}
+		}
+		value = c.text(value, where)
+		if old, ok := col.Variables[key]; ok && old != value {
+			c.note("%s: variable %s differs from another folder's; only one value is kept", where, key)
+		}
+		col.Variables[key] = value
+	}
+}
+
+func (c *converter) insomniaItems(children map[string][]insomniaResource, parent string, folders []string, auth *config.Auth) {
+	for _, item := range children[parent] {
+		name := joinName(folders, item.Name)
+		switch item.Type {
+		case "request_group":
+			c.variables(c.result.Collection, item.Environment, name)
+			folderAuth := auth
+			if a := c.insomniaAuth(name, item.Authentication); a != nil {
+				folderAuth = a
+			}
+			c.insomniaItems(children, item.ID, append(folders, item.Name), folderAuth)
+		case "request":
+			r := c.insomniaRequest(name, item)
+			if r.Auth == nil {
+				r.Auth = auth
+			}
+			c.result.Collection.Requests = append(c.result.Collection.Requests, r)
+		case "grpc_request":
+			c.note("%s: gRPC request not converted; use pingify call --grpc", name)
+		case "websocket_request":
+			c.note("%s: WebSocket request not converted; use pingify call --ws", name)
+		case "unit_test_suite":
+			c.note("%s: unit tests not converted; write their checks as assertions", name)
+		}
+	}
+}
+
+func (c *converter) insomniaRequest(name string, item insomniaResource) collection.Request {
+	r := collection.Request{Name: name, Method: strings.ToUpper(item.Method)}
+	if r.Method == "GET" {
+		r.Method = ""
+	}
+
+	r.URL = item.URL
+	var query []string
+	for _, p := range item.Parameters {
+		if !p.Disabled && p.Name != "" {
+			query = append(query, queryEscape(p.Name)+"="+queryEscape(p.Value))
+		}
+	}
+	if len(query) > 0 {
+		sep := "?"
+		if strings.Contains(r.URL, "?") {
+			sep = "&"
+		}
+		r.URL += sep + strings.Join(query, "&")
+	}
+	r.URL = c.text(r.URL, name)
+
+	for _, h := range item.Headers {
+		if !h.Disabled && h.Name != "" {
+			addHeader(&r, h.Name, c.text(h.Value, name))
+		}
+	}
+
+	b := item.Body
+	switch b.MimeType {
+	case "":
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		for _, p := range b.Params {
+			switch {
+			case p.Disabled:
+			case p.Type == "file" && p.FileName == "":
+				c.note("%s: form file %s has no file selected", name, p.Name)
+			case p.Type == "file":
+				r.Form = append(r.Form, p.Name+"=@"+p.FileName)
+			default:
+				r.Form = append(r.Form, c.text(p.Name+"="+p.Value, name))
+			}
+		}
+		r.URLEncoded = b.MimeType == "application/x-www-form-urlencoded" && len(r.Form) > 0
+	case "application/octet-stream":
+		if b.FileName != "" {
+			r.Body = "@" + b.FileName
+			setHeader(&r, "Content-Type", b.MimeType)
+		}
+	case "application/graphql":
+		if b.Text != "" {
+			r.Body = c.text(b.Text, name)
+			setHeader(&r, "Content-Type", "application/json")
+		}
+	default:
+		if b.Text != "" {
+			r.Body = c.text(b.Text, name)
+			setHeader(&r, "Content-Type", b.MimeType)
+		}
+	}
+
+	r.Auth = c.insomniaAuth(name, item.Authentication)
+	return r
+}
+
+// insomniaAuth converts an authentication block; nil means the request inherits its folder's, and
+// auth that cannot be converted sends none.
+func (c *converter) insomniaAuth(where string, a map[string]any) *config.Auth {
+	if len(a) == 0 {
+		return nil
+	}
+	p := func(key string) string { return c.text(scalar(a[key]), where) }
+	if disabled, _ := a["disabled"].(bool); disabled {
+		return &config.Auth{Type: collection.AuthNone}
+	}
+	var auth *config.Auth
+	switch p("type") {
+	case "", "inherit":
+		return nil
+	case "none":
+		return &config.Auth{Type: collection.AuthNone}
+	case "basic":
+		auth = &config.Auth{Type: "basic", Username: p("username"), Password: p("password")}
+	case "bearer":
+		if prefix := p("prefix"); prefix != "" && !strings.EqualFold(prefix, "Bearer") {
+			auth = &config.Auth{Type: "apikey", APIKeyName: "Authorization", APIKeyValue: prefix + " " + p("token"), APIKeyIn: "header"}
+			break
+		}
+		auth = &config.Auth{Type: "bearer", Token: p("token")}
+	case "apikey":
+		in := "header"
+		if p("addTo") == "queryParams" {
+			in = "query"
+		}
+		auth = &config.Auth{Type: "apikey", APIKeyName: p("key"), APIKeyValue: p("value"), APIKeyIn: in}
+	case "oauth2":
+		switch {
+		case p("grantType") == "client_credentials" && p("accessTokenUrl") != "":
+			auth = &config.Auth{
+				Type:         "oauth2",
+				TokenURL:     p("accessTokenUrl"),
+				ClientID:     p("clientId"),
+				ClientSecret: p("clientSecret"),
+				Scopes:       strings.Fields(p("scope")),
+			}
+		default:
+			c.note("%s: OAuth2 %s flow not converted", where, p("grantType"))
+			return &config.Auth{Type: collection.AuthNone}
+		}
+	default:
+		c.note("%s: %s auth not converted", where, p("type"))
+		return &config.Auth{Type: collection.AuthNone}
+	}
+	c.checkAuth(where, auth)
+	return auth
+}
+
+// flatten turns nested environment data into dotted names, as Insomnia references them with
+// {{ _.parent.child }}.
+func flatten(data map[string]any) map[string]string {
+	out := map[string]string{}
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		name := strings.TrimSuffix(prefix, ".")
+		switch t := v.(type) {
+		case map[string]any:
+			if len(t) == 0 {
+				out[name] = "{}"
+			}
+			for k, x := range t {
+				walk(prefix+k+".", x)
+			}
+		case []any:
+			data, _ := json.Marshal(t)
+			out[name] = string(data)
+		default:
+			out[name] = scalar(v)
+		}
+	}
+	for k, v := range data {
+		walk(k+".", v)
+	}
+	return out
+}
+
+// queryEscape escapes a query parameter, leaving variable references to be replaced when the
+// request is sent.
+func queryEscape(s string) string {
+	if environment.HasReferences(s) || strings.Contains(s, "{%") {
+		return s
+	}
+	return url.QueryEscape(s)
+}
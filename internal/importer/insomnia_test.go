@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+const insomniaExportFile = `{
+  "_type": "export",
+  "__export_format": 4,
+  "resources": [
+    {"_id": "wrk_1", "_type": "workspace", "parentId": null, "name": "Shop"},
+    {"_id": "env_base", "_type": "environment", "parentId": "wrk_1", "name": "Base", "data": {"base": "https://shop.example.com", "api": {"version": "v2"}}},
+    {"_id": "env_dev", "_type": "environment", "parentId": "env_base", "name": "Dev", "data": {"base": "http://localhost:8080", "user": "{{ _.name }}"}},
+    {"_id": "fld_1", "_type": "request_group", "parentId": "wrk_1", "name": "Items", "metaSortKey": 2,
+     "environment": {"page_size": 20},
+     "authentication": {"type": "bearer", "token": "{{ _.token }}", "prefix": ""}},
+    {"_id": "req_2", "_type": "request", "parentId": "fld_1", "name": "Create", "metaSortKey": 2, "method": "post",
+     "url": "{{ _.base }}/{{ _.api.version }}/items",
+     "headers": [{"name": "X-Request-Id", "value": "{% uuid 'v4' %}"}, {"name": "X-Off", "value": "1", "disabled": true}],
+     "body": {"mimeType": "application/json", "text": "{\"name\": \"w\", \"at\": \"{% now 'unix' %}\"}"}},
+    {"_id": "req_1", "_type": "request", "parentId": "fld_1", "name": "List", "metaSortKey": 1, "method": "GET",
+     "url": "{{ _.base }}/items", "parameters": [{"name": "q", "value": "a b"}, {"name": "size", "value": "{{ _.page_size }}"}]},
+    {"_id": "req_3", "_type": "request", "parentId": "wrk_1", "name": "Login", "metaSortKey": 1, "method": "POST",
+     "url": "{{ _.base }}/login",
+     "body": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "{{ _.user }}"}, {"name": "x", "value": "1", "disabled": true}]},
+     "authentication": {"type": "apikey", "key": "X-Api-Key", "value": "secret-key", "addTo": "header"}},
+    {"_id": "req_4", "_type": "request", "parentId": "wrk_1", "name": "Token", "metaSortKey": 3, "method": "GET",
+     "url": "{{ _.base }}/me?token={% response 'body', 'req_3', '$.token' %}",
+     "authentication": {"type": "oauth2", "grantType": "authorization_code"}},
+    {"_id": "grpc_1", "_type": "grpc_request", "parentId": "wrk_1", "name": "Stream", "metaSortKey": 4}
+  ]
+}`
+
+func TestInsomnia(t *testing.T) {
+	result, err := Insomnia(writeExport(t, "insomnia.json", insomniaExportFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := result.Collection
+	wantVars := map[string]string{"base": "https://shop.example.com", "api.version": "v2", "page_size": "20"}
+	if col.Name != "Shop" || !reflect.DeepEqual(col.Variables, wantVars) {
+		t.Errorf("collection = %q with variables %v, want Shop with %v", col.Name, col.Variables, wantVars)
+	}
+
+	bearer := &config.Auth{Type: "bearer", Token: "{{token}}"}
+	want := []collection.Request{
+		{
+			Name: "Login", Method: "POST", URL: "{{base}}/login", Form: []string{"user={{user}}"}, URLEncoded: true,
+			Auth: &config.Auth{Type: "apikey", APIKeyName: "X-Api-Key", APIKeyValue: "secret-key", APIKeyIn: "header"},
+		},
+		{Name: "Items / List", URL: "{{base}}/items?q=a+b&size={{page_size}}", Auth: bearer},
+		{
+			Name: "Items / Create", Method: "POST", URL: "{{base}}/{{api.version}}/items",
+			Headers: map[string]string{"X-Request-Id": "{{$uuid}}", "Content-Type": "application/json"},
+			Body:    `{"name": "w", "at": "{{$timestamp}}"}`,
+			Auth:    bearer,
+		},
+		{
+			Name: "Token", URL: "{{base}}/me?token={% response 'body', 'req_3', '$.token' %}",
+			Auth: &config.Auth{Type: collection.AuthNone},
+		},
+	}
+	if len(col.Requests) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(col.Requests), len(want), col.Requests)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(col.Requests[i], want[i]) {
+			t.Errorf("request %d =\n  %+v\nwant\n  %+v", i, col.Requests[i], want[i])
+		}
+	}
+
+	dev := result.Environments["Dev"]
+	if dev == nil || !reflect.DeepEqual(dev.Variables, map[string]string{"base": "http://localhost:8080", "user": "{{name}}"}) {
+		t.Errorf("Dev environment = %+v", dev)
+	}
+
+	assertNotes(t, result.Unsupported, []string{
+		"Login: the API key is written in the collection",
+		"Token: template tag {% response",
+		"Token: OAuth2 authorization_code flow not converted",
+		"Stream: gRPC request not converted",
+		"environment Dev: variable user refers to other variables",
+	})
+}
+
+func TestInsomniaErrors(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"not json", "nope", "not an Insomnia export"},
+		{"postman file", postmanCollection, "not an Insomnia export"},
+		{"old format", `{"_type": "export", "__export_format": 3, "resources": []}`, "export format 3"},
+		{"no workspace", `{"_type": "export", "__export_format": 4, "resources": []}`, "no workspace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Insomnia(writeExport(t, "insomnia.json", tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		in, want string
+		notes    int
+	}{
+		{"plain", "plain", 0},
+		{"{{ host }}/{{_.path}}", "{{host}}/{{path}}", 0},
+		{"{{$timestamp}} {{$randomUUID}}", "{{$timestamp}} {{$uuid}}", 0},
+		{"{% now 'millis' %} {% now %}", "{{$timestampMs}} {{$isoTimestamp}}", 0},
+		{"{{bad name}}", "{{bad name}}", 1},
+		{"{% base64 'encode', 'x' %}", "{% base64 'encode', 'x' %}", 1},
+	}
+	for _, tt := range tests {
+		c := newConverter()
+		if got := c.text(tt.in, "test"); got != tt.want || len(c.result.Unsupported) != tt.notes {
+			t.Errorf("text(%q) = %q with notes %q, want %q with %d notes", tt.in, got, c.result.Unsupported, tt.want, tt.notes)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+type postmanFile struct {
+	Info *struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth"`
+	Variable []postmanVariable `json:"variable"`
+	Event    []postmanEvent    `json:"event"`
+
+	// Environment and globals exports.
+	Name   string            `json:"name"`
+	Values []postmanVariable `json:"values"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request json.RawMessage `json:"request"`
+	Auth    *postmanAuth    `json:"auth"`
+	Event   []postmanEvent  `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header json.RawMessage `json:"header"`
+	Body   *postmanBody    `json:"body"`
+	URL    json.RawMessage `json:"url"`
+	Auth   *postmanAuth    `json:"auth"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanVariable `json:"urlencoded"`
+	FormData   []postmanVariable `json:"formdata"`
+	File       *struct {
+		Src string `json:"src"`
+	} `json:"file"`
+	GraphQL *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+// postmanVariable is the key/value pair Postman uses for variables, headers, query parameters and
+// form fields.
+type postmanVariable struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Type     string `json:"type"`
+	Src      any    `json:"src"`
+	Disabled bool   `json:"disabled"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+func (v postmanVariable) off() bool {
+	return v.Disabled || (v.Enabled != nil && !*v.Enabled)
+}
+
+func (v postmanVariable) value() string {
+	return scalar(v.Value)
+}
+
+type postmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec any `json:"exec"`
+	} `json:"script"`
+}
+
+// postmanAuth holds the auth type and, under the type's name, its parameters as a list of
+// key/value pairs (v2.1) or an object (v2.0).
+type postmanAuth struct {
+	Type   string
+	Params map[string]string
+}
+
+func (a *postmanAuth) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw["type"], &a.Type); err != nil {
+		return fmt.Errorf("auth type: %w", err)
+	}
+	a.Params = map[string]string{}
+	params := raw[a.Type]
+	var list []postmanVariable
+	if json.Unmarshal(params, &list) == nil {
+		for _, p := range list {
+			a.Params[p.Key] = p.value()
+		}
+		return nil
+	}
+	var object map[string]any
+	if json.Unmarshal(params, &object) == nil {
+		for k, v := range object {
+			a.Params[k] = scalar(v)
+		}
+	}
+	return nil
+}
+
+// Postman converts Postman exports: at most one collection (v2.0 or v2.1) and any number of
+// environments or globals, which are told apart by their contents. Folders become request name
+// prefixes, and folder auth is copied to the requests that inherit it.
+func Postman(paths []string) (*Result, error) {
+	c := newConverter()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var file postmanFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("%s is not a Postman export: %w", path, err)
+		}
+		switch {
+		case file.Info != nil:
+			if c.result.Collection != nil {
+				return nil, errors.New("import one collection at a time; the other files may be environments")
+			}
+			if strings.Contains(file.Info.Schema, "v1.") {
+				return nil, fmt.Errorf("%s is a Postman v1 collection; export it as Collection v2.1", path)
+			}
+			c.postmanCollection(file)
+		case file.Values != nil:
+			name := file.Name
+			if name == "" {
+				name = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".json"), ".postman_environment")
+			}
+			values, secrets := map[string]string{}, map[string]bool{}
+			for _, v := range file.Values {
+				if v.off() {
+					continue
+				}
+				values[v.Key] = v.value()
+				secrets[v.Key] = v.Type == "secret"
+			}
+			c.environment(name, values, secrets)
+		default:
+			return nil, fmt.Errorf("%s is neither a Postman collection nor an environment", path)
+		}
+	}
+	return c.result, nil
+}
+
+func (c *converter) postmanCollection(file postmanFile) {
+	col := &collection.Collection{Name: file.Info.Name}
+	c.result.Collection = col
+	for _, v := range file.Variable {
+		if v.off() {
+			continue
+		}
+		if col.Variables == nil {
+			col.Variables = map[string]string{}
+		}
+		col.Variables[v.Key] = c.text(v.value(), "collection variables")
+	}
+	c.events("collection", file.Event)
+	col.Defaults.Auth = c.postmanAuth("collection", file.Auth)
+	c.postmanItems(file.Item, nil, nil)
+}
+
+// postmanItems converts the requests of a folder; auth is the folder's, nil when it inherits the
+// collection's.
+func (c *converter) postmanItems(items []postmanItem, folders []string, auth *config.Auth) {
+	for _, item := range items {
+		name := joinName(folders, item.Name)
+		c.events(name, item.Event)
+		if len(item.Request) == 0 || string(item.Request) == "null" {
+			folderAuth := auth
+			if item.Auth != nil && item.Auth.Type != "inherit" {
+				folderAuth = c.postmanAuth(name, item.Auth)
+			}
+			c.postmanItems(item.Item, append(folders, item.Name), folderAuth)
+			continue
+		}
+		r, err := c.postmanRequest(name, item.Request)
+		if err != nil {
+			c.note("%s: left out: %v", name, err)
+			continue
+		}
+		if r.Auth == nil {
+			r.Auth = auth
+		}
+		c.result.Collection.Requests = append(c.result.Collection.Requests, *r)
+	}
+}
+
+// events notes the scripts of an item, which are not converted.
+func (c *converter) events(where string, events []postmanEvent) {
+	for _, e := range events {
+		if scalar(e.Script.Exec) == "" {
+			continue
+		}
+		switch e.Listen {
+		case "prerequest":
+			c.note("%s: pre-request script not converted", where)
+		case "test":
+			c.note("%s: test script not converted; write its checks as assertions", where)
+		default:
+			c.note("%s: %s script not converted", where, e.Listen)
+		}
+	}
+}
+
+func (c *converter) postmanRequest(name string, raw json.RawMessage) (*collection.Request, error) {
+	var req postmanRequest
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), `"`) {
+		req.URL = raw // a request can be just its URL
+	} else if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, err
+	}
+	r := &collection.Request{Name: name, Method: strings.ToUpper(req.Method)}
+	if r.Method == "GET" {
+		r.Method = ""
+	}
+
+	var u postmanURL
+	if len(req.URL) == 0 {
+		return nil, errors.New("the request has no URL")
+	}
+	if err := json.Unmarshal(req.URL, &u.Raw); err != nil {
+		if err := json.Unmarshal(req.URL, &u); err != nil {
+			return nil, fmt.Errorf("url: %w", err)
+		}
+	}
+	if u.Raw == "" {
+		return nil, errors.New("the request has no URL")
+	}
+	r.URL = c.text(pathVariables(u.Raw, u.Variable, func(key string) {
+		c.note("%s: path variable :%s has no value", name, key)
+	}), name)
+	if !strings.Contains(r.URL, "://") && !strings.HasPrefix(r.URL, "{{") {
+		r.URL = "http://" + r.URL // as Postman does
+	}
+
+	var headers []postmanVariable
+	if len(req.Header) > 0 && json.Unmarshal(req.Header, &headers) != nil {
+		var text string
+		if json.Unmarshal(req.Header, &text) == nil {
+			for _, line := range strings.Split(text, "\n") {
+				if k, v, ok := strings.Cut(line, ":"); ok {
+					headers = append(headers, postmanVariable{Key: strings.TrimSpace(k), Value: strings.TrimSpace(v)})
+				}
+			}
+		}
+	}
+	for _, h := range headers {
+		if !h.off() && h.Key != "" {
+			addHeader(r, h.Key, c.text(h.value(), name))
+		}
+	}
+
+	c.postmanBody(r, req.Body)
+	if req.Auth != nil {
+		r.Auth = c.postmanAuth(name, req.Auth)
+	}
+	return r, nil
+}
+
+var pathVariable = regexp.MustCompile(`/:([A-Za-z0-9_]+)`)
+
+// pathVariables replaces the :name segments of a Postman URL with their values.
+func pathVariables(rawURL string, vars []postmanVariable, missing func(string)) string {
+	values := map[string]string{}
+	for _, v := range vars {
+		values[v.Key] = v.value()
+	}
+	path, query, hasQuery := strings.Cut(rawURL, "?")
+	path = pathVariable.ReplaceAllStringFunc(path, func(m string) string {
+		key := m[2:]
+		value, ok := values[key]
+		if !ok || value == "" {
+			missing(key)
+			return m
+		}
+		return "/" + value
+	})
+	if hasQuery {
+		return path + "?" + query
+	}
+	return path
+}
+
+func (c *converter) postmanBody(r *collection.Request, b *postmanBody) {
+	if b == nil || b.Disabled {
+		return
+	}
+	switch b.Mode {
+	case "raw":
+		if b.Raw == "" {
+			return
+		}
+		r.Body = c.text(b.Raw, r.Name)
+		if t, ok := contentTypes[b.Options.Raw.Language]; ok {
+			setHeader(r, "Content-Type", t)
+		}
+	case "urlencoded":
+		for _, f := range b.URLEncoded {
+			if !f.off() {
+				r.Form = append(r.Form, c.text(f.Key+"="+f.value(), r.Name))
+			}
+		}
+		r.URLEncoded = len(r.Form) > 0
+	case "formdata":
+		for _, f := range b.FormData {
+			if f.off() {
+				continue
+			}
+			if f.Type == "file" {
+				src := scalar(f.Src)
+				if list, ok := f.Src.([]any); ok && len(list) > 0 {
+					src = scalar(list[0])
+				}
+				if src == "" {
+					c.note("%s: form file %s has no file selected", r.Name, f.Key)
+					continue
+				}
+				r.Form = append(r.Form, f.Key+"=@"+src)
+				continue
+			}
+			r.Form = append(r.Form, c.text(f.Key+"="+f.value(), r.Name))
+		}
+	case "file":
+		if b.File != nil && b.File.Src != "" {
+			r.Body = "@" + b.File.Src
+			setHeader(r, "Content-Type", "application/octet-stream")
+		}
+	case "graphql":
+		if b.GraphQL == nil {
+			return
+		}
+		query, _ := json.Marshal(b.GraphQL.Query)
+		body := `{"query": ` + string(query)
+		if vars := strings.TrimSpace(b.GraphQL.Variables); vars != "" {
+			body += `, "variables": ` + vars
+		}
+		r.Body = c.text(body+"}", r.Name)
+	case "":
+	default:
+		c.note("%s: %s body not converted", r.Name, b.Mode)
+	}
+}
+
+// postmanAuth converts an auth block. OAuth2 flows other than client credentials keep the access
+// token Postman had fetched, as a bearer token; auth that cannot be converted sends none.
+func (c *converter) postmanAuth(where string, a *postmanAuth) *config.Auth {
+	if a == nil {
+		return nil
+	}
+	p := func(key string) string { return c.text(a.Params[key], where) }
+	var auth *config.Auth
+	switch a.Type {
+	case "noauth":
+		return &config.Auth{Type: collection.AuthNone}
+	case "inherit":
+		return nil
+	case "basic":
+		auth = &config.Auth{Type: "basic", Username: p("username"), Password: p("password")}
+	case "bearer":
+		auth = &config.Auth{Type: "bearer", Token: p("token")}
+	case "apikey":
+		in := p("in")
+		if in == "" {
+			in = "header"
+		}
+		auth = &config.Auth{Type: "apikey", APIKeyName: p("key"), APIKeyValue: p("value"), APIKeyIn: in}
+	case "oauth2":
+		switch {
+		case p("grant_type") == "client_credentials" && p("accessTokenUrl") != "":
+			auth = &config.Auth{
+				Type:         "oauth2",
+				TokenURL:     p("accessTokenUrl"),
+				ClientID:     p("clientId"),
+				ClientSecret: p("clientSecret"),
+				Scopes:       strings.Fields(p("scope")),
+			}
+		case p("accessToken") != "":
+			c.note("%s: OAuth2 %s flow replaced by its saved access token, which expires", where, a.Params["grant_type"])
+			auth = &config.Auth{Type: "bearer", Token: p("accessToken")}
+		default:
+			c.note("%s: OAuth2 %s flow not converted", where, a.Params["grant_type"])
+			return &config.Auth{Type: collection.AuthNone}
+		}
+	default:
+		c.note("%s: %s auth not converted", where, a.Type)
+		return &config.Auth{Type: collection.AuthNone}
+	}
+	c.checkAuth(where, auth)
+	return auth
+}
+
+// scalar returns a JSON value as text; lists of strings, such as script lines, are joined.
+func scalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []any:
+		var lines []string
+		for _, x := range t {
+			lines = append(lines, scalar(x))
+		}
+		return strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,232 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+const postmanCollection = `{
+  "info": {"name": "Shop API", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "variable": [{"key": "base", "value": "https://shop.example.com"}, {"key": "off", "value": "x", "disabled": true}],
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]},
+  "item": [
+    {
+      "name": "Items",
+      "auth": {"type": "basic", "basic": [{"key": "username", "value": "admin"}, {"key": "password", "value": "hunter2"}]},
+      "item": [
+        {
+          "name": "Create",
+          "event": [{"listen": "test", "script": {"exec": ["pm.test('ok', () => {})"]}}],
+          "request": {
+            "method": "POST",
+            "header": [{"key": "X-Id", "value": "{{$guid}}"}, {"key": "X-Off", "value": "1", "disabled": true}],
+            "body": {"mode": "raw", "raw": "<item/>", "options": {"raw": {"language": "xml"}}},
+            "url": {"raw": "{{base}}/items/:kind?draft=true", "variable": [{"key": "kind", "value": "books"}]}
+          }
+        },
+        {
+          "name": "Public",
+          "request": {"method": "GET", "url": "{{base}}/items", "auth": {"type": "noauth"}}
+        }
+      ]
+    },
+    {
+      "name": "Login",
+      "request": {
+        "method": "POST",
+        "header": "Accept: application/json\nX-Trace: {{ trace }}",
+        "body": {"mode": "urlencoded", "urlencoded": [{"key": "user", "value": "{{user}}"}, {"key": "skip", "value": "1", "disabled": true}]},
+        "url": "shop.example.com/login"
+      }
+    },
+    {
+      "name": "Upload",
+      "request": {
+        "method": "PUT",
+        "body": {"mode": "formdata", "formdata": [{"key": "note", "value": "hi"}, {"key": "file", "type": "file", "src": ["/tmp/a.png"]}, {"key": "none", "type": "file"}]},
+        "url": {"raw": "{{base}}/upload"},
+        "auth": {"type": "oauth2", "oauth2": {"grant_type": "client_credentials", "accessTokenUrl": "{{base}}/token", "clientId": "id", "clientSecret": "{{secret}}", "scope": "read write"}}
+      }
+    },
+    {
+      "name": "Search",
+      "request": {
+        "method": "POST",
+        "body": {"mode": "graphql", "graphql": {"query": "{ items { id } }", "variables": "{\"n\": 1}"}},
+        "url": "{{base}}/graphql?t={{$randomColor}}",
+        "auth": {"type": "digest", "digest": []}
+      }
+    },
+    {"name": "Broken", "request": {"method": "GET"}}
+  ]
+}`
+
+const postmanEnvironment = `{
+  "name": "Staging",
+  "values": [
+    {"key": "base", "value": "https://staging.example.com", "enabled": true},
+    {"key": "api-key", "value": "k", "type": "secret", "enabled": true},
+    {"key": "old", "value": "x", "enabled": false},
+    {"key": "bad name", "value": "x", "enabled": true}
+  ]
+}`
+
+func writeExport(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPostman(t *testing.T) {
+	result, err := Postman([]string{
+		writeExport(t, "shop.postman_collection.json", postmanCollection),
+		writeExport(t, "staging.postman_environment.json", postmanEnvironment),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := result.Collection
+	if col.Name != "Shop API" || !reflect.DeepEqual(col.Variables, map[string]string{"base": "https://shop.example.com"}) {
+		t.Errorf("collection = %q with variables %v", col.Name, col.Variables)
+	}
+	if want := (&config.Auth{Type: "bearer", Token: "{{token}}"}); !reflect.DeepEqual(col.Defaults.Auth, want) {
+		t.Errorf("default auth = %+v, want %+v", col.Defaults.Auth, want)
+	}
+
+	want := []collection.Request{
+		{
+			Name: "Items / Create", Method: "POST", URL: "{{base}}/items/books?draft=true",
+			Headers: map[string]string{"X-Id": "{{$uuid}}", "Content-Type": "application/xml"},
+			Body:    "<item/>",
+			Auth:    &config.Auth{Type: "basic", Username: "admin", Password: "hunter2"},
+		},
+		{Name: "Items / Public", URL: "{{base}}/items", Auth: &config.Auth{Type: collection.AuthNone}},
+		{
+			Name: "Login", Method: "POST", URL: "http://shop.example.com/login",
+			Headers: map[string]string{"Accept": "application/json", "X-Trace": "{{trace}}"},
+			Form:    []string{"user={{user}}"}, URLEncoded: true,
+		},
+		{
+			Name: "Upload", Method: "PUT", URL: "{{base}}/upload", Form: []string{"note=hi", "file=@/tmp/a.png"},
+			Auth: &config.Auth{Type: "oauth2", TokenURL: "{{base}}/token", ClientID: "id", ClientSecret: "{{secret}}", Scopes: []string{"read", "write"}},
+		},
+		{
+			Name: "Search", Method: "POST", URL: "{{base}}/graphql?t={{$randomColor}}",
+			Body: `{"query": "{ items { id } }", "variables": {"n": 1}}`,
+			Auth: &config.Auth{Type: collection.AuthNone},
+		},
+	}
+	if len(col.Requests) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(col.Requests), len(want), col.Requests)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(col.Requests[i], want[i]) {
+			t.Errorf("request %d =\n  %+v\nwant\n  %+v", i, col.Requests[i], want[i])
+		}
+	}
+
+	env := result.Environments["Staging"]
+	if env == nil {
+		t.Fatalf("environments = %v", result.Environments)
+	}
+	if !reflect.DeepEqual(env.Variables, map[string]string{"base": "https://staging.example.com"}) ||
+		!reflect.DeepEqual(env.Secrets, map[string]string{"api-key": "env:API_KEY"}) {
+		t.Errorf("environment = %+v", env)
+	}
+
+	assertNotes(t, result.Unsupported, []string{
+		"Items / Create: test script not converted",
+		"Items: the password is written in the collection",
+		"Upload: form file none has no file selected",
+		"Search: dynamic value {{$randomColor}} has no equivalent",
+		"Search: digest auth not converted",
+		"Broken: left out: the request has no URL",
+		"environment Staging: secret api-key is read from $API_KEY",
+		`environment Staging: "bad name" is not a valid variable name`,
+	})
+
+	// What was converted must load as a collection.
+	if err := col.Validate(); err != nil {
+		t.Errorf("converted collection is invalid: %v", err)
+	}
+}
+
+// assertNotes checks that every wanted note is among the notes, by prefix.
+func assertNotes(t *testing.T, notes, want []string) {
+	t.Helper()
+	for _, w := range want {
+		found := false
+		for _, n := range notes {
+			found = found || strings.HasPrefix(n, w)
+		}
+		if !found {
+			t.Errorf("no note starting with %q in\n  %s", w, strings.Join(notes, "\n  "))
+		}
+	}
+}
+
+func TestPostmanErrors(t *testing.T) {
+	collectionFile := writeExport(t, "a.json", postmanCollection)
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{"not json", []string{writeExport(t, "x.json", "nope")}, "is not a Postman export"},
+		{"unknown file", []string{writeExport(t, "x.json", `{"foo": 1}`)}, "neither a Postman collection nor an environment"},
+		{"v1", []string{writeExport(t, "x.json", `{"info": {"name": "old", "schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}}`)}, "Postman v1 collection"},
+		{"two collections", []string{collectionFile, collectionFile}, "one collection at a time"},
+		{"missing file", []string{filepath.Join(t.TempDir(), "missing.json")}, "no such file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Postman(tt.paths)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Shop API", "shop-api"},
+		{"  Staging (EU) ", "staging-eu"},
+		{"***", "collection"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.name); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "shop.yaml")
+	col := &collection.Collection{Name: "shop", Requests: []collection.Request{{Name: "home", URL: "https://example.com"}}}
+	if err := WriteYAML(path, col, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteYAML(path, col, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second write error = %v, want it to refuse", err)
+	}
+	if err := WriteYAML(path, col, true); err != nil {
+		t.Errorf("overwrite: %v", err)
+	}
+	loaded, err := collection.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Name != "shop" || len(loaded.Requests) != 1 || loaded.Requests[0].URL != "https://example.com" {
+		t.Errorf("loaded %+v", loaded)
+	}
+}
@@ -0,0 +1,516 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (st stage) apply(v any) ([]any, error) {
+	switch st.fn {
+	case "":
+		return evalSteps(v, v, st.path), nil
+	case "length":
+		switch t := v.(type) {
+		case []any:
+			return []any{float64(len(t))}, nil
+		case map[string]any:
+			return []any{float64(len(t))}, nil
+		case string:
+			return []any{float64(len([]rune(t)))}, nil
+		case nil:
+			return []any{float64(0)}, nil
+		}
+		return nil, fmt.Errorf("length: %s has no length", typeName(v))
+	case "keys":
+		switch t := v.(type) {
+		case map[string]any:
+			var keys []any
+			for _, k := range sortedKeys(t) {
+				keys = append(keys, k)
+			}
+			return []any{keys}, nil
+		case []any:
+			keys := make([]any, len(t))
+			for i := range t {
+				keys[i] = float64(i)
+			}
+			return []any{keys}, nil
+		}
+		return nil, fmt.Errorf("keys: %s has no keys", typeName(v))
+	case "first", "last":
+		arr, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is not an array", st.fn, typeName(v))
+		}
+		if len(arr) == 0 {
+			return nil, nil
+		}
+		if st.fn == "first" {
+			return []any{arr[0]}, nil
+		}
+		return []any{arr[len(arr)-1]}, nil
+	case "select":
+		if st.cond.eval(v, v) {
+			return []any{v}, nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown function %q", st.fn)
+}
+
+// evalSteps applies steps to v; root is what $ refers to inside filters.
+func evalSteps(root, v any, steps []step) []any {
+	values := []any{v}
+	for i := 0; i < len(steps); i++ {
+		st := steps[i]
+		var next []any
+		if st.kind == stepRecursive {
+			for _, x := range values {
+				next = appendDescendants(next, x)
+			}
+			values = next
+			continue
+		}
+		for _, x := range values {
+			next = append(next, st.apply(root, x)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (st step) apply(root, v any) []any {
+	switch st.kind {
+	case stepChild:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		var out []any
+		for _, name := range st.names {
+			if x, ok := obj[name]; ok {
+				out = append(out, x)
+			}
+		}
+		return out
+	case stepWildcard:
+		return children(v)
+	case stepIndex:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		var out []any
+		for _, i := range st.indexes {
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				out = append(out, arr[i])
+			}
+		}
+		return out
+	case stepSlice:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		return slice(arr, st.slice)
+	case stepFilter:
+		var out []any
+		for _, x := range children(v) {
+			if st.cond.eval(root, x) {
+				out = append(out, x)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// children returns array elements or object values in key order.
+func children(v any) []any {
+	switch t := v.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		out := make([]any, 0, len(t))
+		for _, k := range sortedKeys(t) {
+			out = append(out, t[k])
+		}
+		return out
+	}
+	return nil
+}
+
+// appendDescendants appends v and everything below it, depth first.
+func appendDescendants(out []any, v any) []any {
+	out = append(out, v)
+	for _, c := range children(v) {
+		out = appendDescendants(out, c)
+	}
+	return out
+}
+
+// slice follows Python semantics, including negative bounds and steps.
+func slice(arr []any, bounds [3]*int) []any {
+	n := len(arr)
+	stepSize := 1
+	if bounds[2] != nil {
+		stepSize = *bounds[2]
+	}
+	clamp := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		i := *p
+		if i < 0 {
+			i += n
+		}
+		return min(max(i, -1), n)
+	}
+	var out []any
+	if stepSize > 0 {
+		start, end := max(clamp(bounds[0], 0), 0), clamp(bounds[1], n)
+		for i := start; i < end; i += stepSize {
+			out = append(out, arr[i])
+		}
+	} else {
+		start, end := min(clamp(bounds[0], n-1), n-1), clamp(bounds[1], -1)
+		for i := start; i > end; i += stepSize {
+			out = append(out, arr[i])
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// cond is a filter expression, e.g. @.price > 10 && @.tags[0] == 'sale'.
+type cond struct {
+	op          string // "or", "and", "not", or a comparison operator; "" tests existence/truthiness
+	left, right *cond
+	a, b        operand
+	re          *regexp.Regexp
+}
+
+// operand is a path relative to the current node (@ or .), the root ($), or a literal.
+type operand struct {
+	path   []step
+	isPath bool
+	rooted bool
+	value  any
+}
+
+func parseCond(s string) (*cond, error) {
+	p := &parser{s: strings.TrimSpace(s)}
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in filter", p.rest())
+	}
+	return c, nil
+}
+
+func (p *parser) or() (*cond, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.eat("||") && !p.eat("or ") {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &cond{op: "or", left: left, right: right}
+	}
+}
+
+func (p *parser) and() (*cond, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.eat("&&") && !p.eat("and ") {
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &cond{op: "and", left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (*cond, error) {
+	p.skipSpace()
+	if p.done() {
+		return nil, errors.New("incomplete filter")
+	}
+	if p.peek() == '!' && !strings.HasPrefix(p.rest(), "!=") {
+		p.pos++
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &cond{op: "not", left: c}, nil
+	}
+	if p.eat("(") {
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.eat(")") {
+			return nil, errors.New("expected ) in filter")
+		}
+		return c, nil
+	}
+
+	a, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "=~", "<", ">"} {
+		if !p.eat(op) {
+			continue
+		}
+		p.skipSpace()
+		if op == "=~" {
+			pattern, err := p.pattern()
+			if err != nil {
+				return nil, err
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex: %w", err)
+			}
+			return &cond{op: op, a: a, re: re}, nil
+		}
+		b, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &cond{op: op, a: a, b: b}, nil
+	}
+	return &cond{a: a}, nil
+}
+
+// pattern reads a regex written as /.../ or a quoted string.
+func (p *parser) pattern() (string, error) {
+	if p.done() {
+		return "", errors.New("missing regex")
+	}
+	if p.peek() != '/' {
+		return p.quoted()
+	}
+	p.pos++
+	end := strings.IndexByte(p.rest(), '/')
+	for end > 0 && p.rest()[end-1] == '\\' {
+		next := strings.IndexByte(p.rest()[end+1:], '/')
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += next + 1
+	}
+	if end < 0 {
+		return "", errors.New("unterminated regex")
+	}
+	pattern := p.rest()[:end]
+	p.pos += end + 1
+	return pattern, nil
+}
+
+func (p *parser) operand() (operand, error) {
+	p.skipSpace()
+	if p.done() {
+		return operand{}, errors.New("missing operand")
+	}
+	switch c := p.peek(); {
+	case c == '@' || c == '$' || c == '.':
+		rooted := c == '$'
+		if c != '.' {
+			p.pos++
+		}
+		steps, err := p.steps()
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{path: steps, isPath: true, rooted: rooted}, nil
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		return operand{value: s}, err
+	}
+	start := p.pos
+	for !p.done() && strings.IndexByte(" )=!<>&|", p.peek()) < 0 {
+		p.pos++
+	}
+	word := p.s[start:p.pos]
+	switch word {
+	case "true":
+		return operand{value: true}, nil
+	case "false":
+		return operand{value: false}, nil
+	case "null":
+		return operand{value: nil}, nil
+	}
+	f, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return operand{}, fmt.Errorf("unexpected %q in filter", word)
+	}
+	return operand{value: f}, nil
+}
+
+// resolve returns the operand's value and whether it exists.
+func (o operand) resolve(root, v any) (any, bool) {
+	if !o.isPath {
+		return o.value, true
+	}
+	base := v
+	if o.rooted {
+		base = root
+	}
+	values := evalSteps(root, base, o.path)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+func (c *cond) eval(root, v any) bool {
+	switch c.op {
+	case "or":
+		return c.left.eval(root, v) || c.right.eval(root, v)
+	case "and":
+		return c.left.eval(root, v) && c.right.eval(root, v)
+	case "not":
+		return !c.left.eval(root, v)
+	case "":
+		x, ok := c.a.resolve(root, v)
+		if !c.a.isPath {
+			return Truthy(x)
+		}
+		return ok && x != false && x != nil
+	case "=~":
+		x, ok := c.a.resolve(root, v)
+		s, isString := x.(string)
+		return ok && isString && c.re.MatchString(s)
+	}
+
+	a, okA := c.a.resolve(root, v)
+	b, okB := c.b.resolve(root, v)
+	if !okA || !okB {
+		return c.op == "!=" && okA != okB
+	}
+	switch c.op {
+	case "==":
+		return Equal(a, b)
+	case "!=":
+		return !Equal(a, b)
+	}
+	cmp, ok := Compare(a, b)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// Truthy follows jq: everything except null and false is true.
+func Truthy(v any) bool {
+	return v != nil && v != false
+}
+
+// Equal compares JSON values, treating numbers by value.
+func Equal(a, b any) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Compare orders two numbers or two strings.
+func Compare(a, b any) (int, bool) {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	sa, okA := a.(string)
+	sb, okB := b.(string)
+	if !okA || !okB {
+		return 0, false
+	}
+	return strings.Compare(sa, sb), true
+}
+
+func number(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
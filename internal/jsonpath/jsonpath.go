@@ -0,0 +1,148 @@
+// Package jsonpath evaluates JSONPath ($.items[0].name) and jq-style (.items[].name | length)
+// expressions against decoded JSON, without shelling out to jq.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Expr is a compiled expression: a path followed by optional jq-style pipe stages.
+type Expr struct {
+	raw    string
+	header string // for header:Name expressions
+	stages []stage
+}
+
+// stage is one step of a pipeline: either a path or a function such as length or select(...).
+type stage struct {
+	path []step
+	fn   string
+	cond *cond // for select(...)
+}
+
+// Compile parses an expression. Supported forms:
+//
+//	$.a.b[0]  $..name  $.items[*]  $.items[1:3]  $.items[?(@.price > 10)]  $['a','b']
+//	.a.b[0]  .items[].name  .items | length  .[] | select(.ok == true) | .id
+//	header:Content-Type
+//
+// Pipe stages may also be keys, first, last or another path.
+func Compile(expr string) (*Expr, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, errors.New("empty expression")
+	}
+	if name, ok := strings.CutPrefix(expr, "header:"); ok {
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, errors.New("header: needs a header name")
+		}
+		return &Expr{raw: expr, header: name}, nil
+	}
+
+	e := &Expr{raw: expr}
+	for i, part := range splitPipes(expr) {
+		st, err := parseStage(strings.TrimSpace(part), i == 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+		e.stages = append(e.stages, st)
+	}
+	return e, nil
+}
+
+func (e *Expr) String() string { return e.raw }
+
+// Eval applies the expression to a decoded JSON document (as produced by encoding/json).
+func (e *Expr) Eval(doc any) ([]any, error) {
+	values := []any{doc}
+	for _, st := range e.stages {
+		var next []any
+		for _, v := range values {
+			out, err := st.apply(v)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// Select evaluates the expression against a response: header: expressions read header, others
+// decode body as JSON.
+func (e *Expr) Select(body string, header http.Header) ([]any, error) {
+	if e.header != "" {
+		var values []any
+		for _, v := range header.Values(e.header) {
+			values = append(values, v)
+		}
+		return values, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+	return e.Eval(normalize(doc))
+}
+
+// Select compiles expr and evaluates it against a response.
+func Select(expr, body string, header http.Header) ([]any, error) {
+	e, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Select(body, header)
+}
+
+// normalize turns numbers into float64 where that is lossless, keeping others (e.g. 64-bit IDs)
+// as json.Number so they print exactly.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, x := range t {
+			t[k] = normalize(x)
+		}
+	case []any:
+		for i, x := range t {
+			t[i] = normalize(x)
+		}
+	case json.Number:
+		if f, err := t.Float64(); err == nil && fmt.Sprint(f) == t.String() {
+			return f
+		}
+		return t
+	}
+	return v
+}
+
+// Format renders selected values one per line: strings as-is, everything else as indented JSON.
+func Format(values []any) string {
+	lines := make([]string, 0, len(values))
+	for _, v := range values {
+		lines = append(lines, FormatValue(v))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatValue renders a single value: strings as-is, everything else as indented JSON.
+func FormatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,167 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const store = `{
+  "store": {
+    "name": "corner shop",
+    "books": [
+      {"title": "Go", "price": 30, "tags": ["code"], "stock": true},
+      {"title": "Rust", "price": 45.5, "tags": ["code", "sale"], "stock": false},
+      {"title": "Poems", "price": 8, "tags": [], "isbn": "0-553"},
+      {"title": "Atlas", "price": 120}
+    ],
+    "owner": {"name": "Ana", "id": 9007199254740993}
+  },
+  "limit": 40
+}`
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string // the selected values as a JSON array
+	}{
+		// JSONPath
+		{"$.store.name", `["corner shop"]`},
+		{"$.store.books[0].title", `["Go"]`},
+		{"$.store.books[-1].title", `["Atlas"]`},
+		{"$.store.books[0,2].title", `["Go","Poems"]`},
+		{"$.store.books[*].price", `[30,45.5,8,120]`},
+		{"$.store.books[1:3].title", `["Rust","Poems"]`},
+		{"$.store.books[::2].title", `["Go","Poems"]`},
+		{"$.store.books[?(@.price > 40)].title", `["Rust","Atlas"]`},
+		{"$.store.books[?(@.price < $.limit && @.stock)].title", `["Go"]`},
+		{"$.store.books[?(@.isbn)].title", `["Poems"]`},
+		{"$.store.books[?(!@.price)].title", `null`},
+		{"$.store.books[?(@.title =~ /^R/ || @.tags[0] == 'code')].title", `["Go","Rust"]`},
+		{"$.store.books[?(@.stock != true)].title", `["Rust","Poems","Atlas"]`},
+		{"$['store']['owner']['name','id']", `["Ana",9007199254740993]`},
+		{"$..name", `["corner shop","Ana"]`},
+		{"$.store.missing", `null`},
+		{"$.limit", `[40]`},
+
+		// jq style
+		{".store.books[].title", `["Go","Rust","Poems","Atlas"]`},
+		{".store.books | length", `[4]`},
+		{".store.name | length", `[11]`},
+		{".store.owner | keys", `[["id","name"]]`},
+		{".store.books | first | .title", `["Go"]`},
+		{".store.books | last | .price", `[120]`},
+		{".store.books[] | select(.price >= 30) | .title", `["Go","Rust","Atlas"]`},
+		{".store.books[] | select(.tags[0]) | .title", `["Go","Rust"]`},
+		{".store.books[1].tags[] | select(. == \"sale\")", `["sale"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Select(tt.expr, store, nil)
+			assertSelected(t, got, err, tt.want)
+		})
+	}
+}
+
+func TestSelectIdentity(t *testing.T) {
+	for _, expr := range []string{".", "$", ". | select(.ok == true)", ". | select(. != null)"} {
+		got, err := Select(expr, `{"ok": true}`, nil)
+		assertSelected(t, got, err, `[{"ok":true}]`)
+	}
+}
+
+func assertSelected(t *testing.T, got []any, err error, want string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestSelectHeader(t *testing.T) {
+	header := http.Header{"Content-Type": {"application/json"}, "Set-Cookie": {"a=1", "b=2"}}
+	tests := []struct {
+		expr, want string
+	}{
+		{"header:Content-Type", `["application/json"]`},
+		{"header: set-cookie", `["a=1","b=2"]`},
+		{"header:X-Missing", `null`},
+	}
+	for _, tt := range tests {
+		got, err := Select(tt.expr, "not json", header)
+		assertSelected(t, got, err, tt.want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"header:",
+		"$.items[",
+		"$.items[1:2:0]",
+		"$.items[a]",
+		"$.items[?(@.price >)]",
+		"$.items[?(@.price > 1]",
+		"$.items[?(@.name =~ /[/)]",
+		"$['a',0]",
+		".items | frobnicate",
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		expr, body string
+	}{
+		{".a | length", `{"a": true}`},
+		{".a | keys", `{"a": "x"}`},
+		{".a | first", `{"a": {}}`},
+		{".a", `not json`},
+	}
+	for _, tt := range tests {
+		if got, err := Select(tt.expr, tt.body, nil); err == nil {
+			t.Errorf("Select(%q, %s) = %v, want an error", tt.expr, tt.body, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		values []any
+		want   string
+	}{
+		{[]any{"plain", 1.5, true, nil}, "plain\n1.5\ntrue\nnull"},
+		{[]any{map[string]any{"a": "<b>"}}, "{\n  \"a\": \"<b>\"\n}"},
+		{[]any{json.Number("9007199254740993")}, "9007199254740993"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.values); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestEqualAndCompare(t *testing.T) {
+	if !Equal(1.0, json.Number("1")) {
+		t.Error("Equal(1.0, 1) = false")
+	}
+	if Equal("1", 1.0) {
+		t.Error(`Equal("1", 1) = true`)
+	}
+	if c, ok := Compare("a", "b"); !ok || c >= 0 {
+		t.Errorf(`Compare("a", "b") = %d, %v`, c, ok)
+	}
+	if _, ok := Compare("a", 1.0); ok {
+		t.Error(`Compare("a", 1) is ok`)
+	}
+}
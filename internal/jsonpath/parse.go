@@ -0,0 +1,326 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepChild     stepKind = iota // .name or ['a','b']
+	stepIndex                     // [0] or [0,2]
+	stepWildcard                  // .* [*] []
+	stepSlice                     // [start:end:step]
+	stepFilter                    // [?(...)]
+	stepRecursive                 // .. ; the following step applies at every depth
+)
+
+type step struct {
+	kind    stepKind
+	names   []string
+	indexes []int
+	slice   [3]*int
+	cond    *cond
+}
+
+// splitPipes splits on | outside brackets, parentheses and quotes, leaving || alone.
+func splitPipes(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == '|' && depth == 0:
+			if i+1 < len(s) && s[i+1] == '|' {
+				i++
+				continue
+			}
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseStage(s string, first bool) (stage, error) {
+	switch s {
+	case "length", "keys", "first", "last":
+		return stage{fn: s}, nil
+	case "":
+		return stage{}, errors.New("empty pipe stage")
+	}
+	if inner, ok := strings.CutPrefix(s, "select("); ok && strings.HasSuffix(inner, ")") {
+		c, err := parseCond(inner[:len(inner)-1])
+		if err != nil {
+			return stage{}, err
+		}
+		return stage{fn: "select", cond: c}, nil
+	}
+	p := &parser{s: s}
+	switch {
+	case p.eat("$"), p.eat("@"):
+	case strings.HasPrefix(s, ".") || strings.HasPrefix(s, "["):
+	case first:
+		return stage{}, errors.New("expressions start with $, . or header:")
+	default:
+		return stage{}, fmt.Errorf("unknown function %q", s)
+	}
+	steps, err := p.steps()
+	if err != nil {
+		return stage{}, err
+	}
+	if !p.done() {
+		return stage{}, fmt.Errorf("unexpected %q", p.rest())
+	}
+	return stage{path: steps}, nil
+}
+
+// parser reads paths: a sequence of .name, ..name, [...] and jq's .[...] steps.
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) done() bool   { return p.pos >= len(p.s) }
+func (p *parser) rest() string { return p.s[p.pos:] }
+func (p *parser) peek() byte   { return p.s[p.pos] }
+func (p *parser) eat(t string) bool {
+	if strings.HasPrefix(p.s[p.pos:], t) {
+		p.pos += len(t)
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+// steps parses steps until the input ends or something that is not a step (e.g. an operator).
+func (p *parser) steps() ([]step, error) {
+	var steps []step
+	for !p.done() {
+		switch {
+		case p.eat(".."):
+			steps = append(steps, step{kind: stepRecursive})
+			if !p.done() && p.peek() == '[' {
+				continue
+			}
+			st, err := p.member()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, st)
+		case p.eat("."):
+			if p.done() || strings.IndexByte(" )=!<>&|,", p.peek()) >= 0 {
+				// A lone . is the identity, e.g. select(. == "x").
+				return steps, nil
+			}
+			if p.peek() == '[' {
+				continue
+			}
+			st, err := p.member()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, st)
+		case p.peek() == '[':
+			st, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, st)
+		default:
+			return steps, nil
+		}
+	}
+	return steps, nil
+}
+
+// member reads the name after a dot: *, an identifier or a quoted string.
+func (p *parser) member() (step, error) {
+	if p.eat("*") {
+		return step{kind: stepWildcard}, nil
+	}
+	if !p.done() && (p.peek() == '"' || p.peek() == '\'') {
+		name, err := p.quoted()
+		if err != nil {
+			return step{}, err
+		}
+		return step{kind: stepChild, names: []string{name}}, nil
+	}
+	start := p.pos
+	for !p.done() {
+		c := p.peek()
+		if c == '.' || c == '[' || c == ' ' || c == '|' || c == ')' || c == '=' || c == '!' || c == '<' || c == '>' || c == '&' || c == ',' {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return step{}, fmt.Errorf("expected a member name at %q", p.rest())
+	}
+	return step{kind: stepChild, names: []string{p.s[start:p.pos]}}, nil
+}
+
+func (p *parser) quoted() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for !p.done() {
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == '\\' && !p.done():
+			b.WriteByte(p.peek())
+			p.pos++
+		case c == quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+// bracket reads [*], [], [0], [-1], [0,2], ['a','b'], [1:3] or [?(...)].
+func (p *parser) bracket() (step, error) {
+	p.pos++ // [
+	p.skipSpace()
+	switch {
+	case p.eat("]"):
+		return step{kind: stepWildcard}, nil
+	case p.eat("*"):
+		p.skipSpace()
+		if !p.eat("]") {
+			return step{}, errors.New("expected ] after *")
+		}
+		return step{kind: stepWildcard}, nil
+	case p.eat("?"):
+		p.skipSpace()
+		if !p.eat("(") {
+			return step{}, errors.New("expected ( after ?")
+		}
+		end := matchingParen(p.s, p.pos)
+		if end < 0 {
+			return step{}, errors.New("unbalanced parentheses in filter")
+		}
+		c, err := parseCond(p.s[p.pos:end])
+		if err != nil {
+			return step{}, err
+		}
+		p.pos = end + 1
+		p.skipSpace()
+		if !p.eat("]") {
+			return step{}, errors.New("expected ] after filter")
+		}
+		return step{kind: stepFilter, cond: c}, nil
+	}
+
+	end := strings.IndexByte(p.s[p.pos:], ']')
+	if !p.done() && (p.peek() == '\'' || p.peek() == '"') {
+		var names []string
+		for {
+			p.skipSpace()
+			name, err := p.quoted()
+			if err != nil {
+				return step{}, err
+			}
+			names = append(names, name)
+			p.skipSpace()
+			if p.eat("]") {
+				return step{kind: stepChild, names: names}, nil
+			}
+			if !p.eat(",") {
+				return step{}, errors.New("expected , or ] in bracket")
+			}
+			p.skipSpace()
+			if p.done() || (p.peek() != '\'' && p.peek() != '"') {
+				return step{}, errors.New("cannot mix names and indexes in a bracket")
+			}
+		}
+	}
+	if end < 0 {
+		return step{}, errors.New("missing ]")
+	}
+	inner := strings.TrimSpace(p.s[p.pos : p.pos+end])
+	p.pos += end + 1
+
+	if strings.Contains(inner, ":") {
+		parts := strings.Split(inner, ":")
+		if len(parts) > 3 {
+			return step{}, fmt.Errorf("invalid slice [%s]", inner)
+		}
+		var st step
+		st.kind = stepSlice
+		for i, part := range parts {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return step{}, fmt.Errorf("invalid slice [%s]", inner)
+			}
+			st.slice[i] = &n
+		}
+		if st.slice[2] != nil && *st.slice[2] == 0 {
+			return step{}, errors.New("slice step cannot be zero")
+		}
+		return st, nil
+	}
+
+	var indexes []int
+	for _, part := range strings.Split(inner, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return step{}, fmt.Errorf("invalid index [%s]", inner)
+		}
+		indexes = append(indexes, n)
+	}
+	return step{kind: stepIndex, indexes: indexes}, nil
+}
+
+// matchingParen returns the index of the ) closing the group that starts at from, honouring quotes.
+func matchingParen(s string, from int) int {
+	depth := 1
+	var quote byte
+	for i := from; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
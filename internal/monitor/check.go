@@ -0,0 +1,240 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/har"
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+// LogDir is where monitor logs are written, one JSON Lines file per log key (see config.Check.LogKey).
+const LogDir = "logs"
+
+// LogRetention is how far back a log keeps entries once it is compacted; status pages show the
+// last 90 days.
+const LogRetention = 90 * 24 * time.Hour
+
+// MaxLogSize is the size at which a log is compacted: entries older than LogRetention are dropped,
+// then the oldest ones until the file is at most half this size.
+var MaxLogSize int64 = 32 << 20
+
+// logMu serialises appends so concurrent checks against the same key don't drop entries.
+var logMu sync.Mutex
+
+// LogPath returns the log file used for the given key.
+func LogPath(key string) string {
+	return filepath.Join(LogDir, fmt.Sprintf("monitor_%s.jsonl", HashURL(key)))
+}
+
+// AppendLog adds an entry to the end of the log file of the given key, compacting the file once it
+// grows past MaxLogSize.
+func AppendLog(key string, log MonitorLog) error {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	if err := os.MkdirAll(LogDir, os.ModePerm); err != nil {
+		return err
+	}
+	if err := migrateLog(key); err != nil {
+		return err
+	}
+	line, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+
+	path := LogPath(key)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(secret.MaskBytes(line), '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	info, err := f.Stat()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() > MaxLogSize {
+		return compactLog(path, time.Now())
+	}
+	return nil
+}
+
+// RunCheck probes the check once and appends the outcome to its log file. It returns the entry and
+// the probe output to show (the response body for HTTP checks). Probes that could not complete are
+// logged as unsuccessful entries and their error is returned alongside the entry.
+func RunCheck(check config.Check) (MonitorLog, string, error) {
+	check = check.WithDefaults()
+
+	var log MonitorLog
+	var output string
+	var err error
+	if probe, ok := probeFor(check.Type); ok {
+		log, output, err = probe(check)
+	} else {
+		err = fmt.Errorf("unknown check type %q", check.Type)
+	}
+
+	log.Timestamp = time.Now()
+	log.URL = check.URL
+	log.Threshold = check.Threshold.Std()
+	log.Exceeded = log.Threshold > 0 && log.ExecutionTime > log.Threshold
+	log.Success = err == nil && log.Error == "" && !log.Exceeded
+	log.Insecure = check.TLS != nil && check.TLS.Insecure && check.Type != config.TypeTLS
+	if err != nil {
+		log.Error = err.Error()
+	}
+
+	if appendErr := AppendLog(check.LogKey(), log); appendErr != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Failed to write monitor log:", appendErr)
+	}
+	if !log.Success && check.HAROnFailure != "" && len(log.har) > 0 {
+		if harErr := har.Append(check.HAROnFailure, log.har...); harErr != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to write HAR:", harErr)
+		}
+	}
+	return log, output, err
+}
+
+// ReadLogs returns every logged check for the given key, oldest first.
+func ReadLogs(key string) ([]MonitorLog, error) {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	if err := migrateLog(key); err != nil {
+		return nil, err
+	}
+	return readLogFile(LogPath(key))
+}
+
+// readLogFile parses a JSON Lines log. A last line without a newline is an append still in
+// progress and is skipped; any other line that doesn't parse is an error.
+func readLogFile(path string) ([]MonitorLog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var logs []MonitorLog
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return logs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var log MonitorLog
+		if err := json.Unmarshal(line, &log); err != nil {
+			return nil, fmt.Errorf("corrupt log %s at line %d: %w", path, n, err)
+		}
+		logs = append(logs, log)
+	}
+}
+
+// compactLog drops entries older than LogRetention, then the oldest ones until the log fits in
+// half of MaxLogSize. The file is replaced through a temp file and a rename, so readers that don't
+// hold logMu, like the report package, never see a partly written file.
+func compactLog(path string, now time.Time) error {
+	logs, err := readLogFile(path)
+	if err != nil {
+		return err
+	}
+	var lines [][]byte
+	var size int64
+	for _, log := range logs {
+		if now.Sub(log.Timestamp) > LogRetention {
+			continue
+		}
+		line, err := json.Marshal(log)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, append(line, '\n'))
+		size += int64(len(line) + 1)
+	}
+	for len(lines) > 1 && size > MaxLogSize/2 {
+		size -= int64(len(lines[0]))
+		lines = lines[1:]
+	}
+	return replaceFile(path, bytes.Join(lines, nil))
+}
+
+// migrateLog converts a log written as a single JSON array by earlier versions to JSON Lines,
+// keeping any entries already appended to the new file after it.
+func migrateLog(key string) error {
+	legacy := strings.TrimSuffix(LogPath(key), ".jsonl") + ".json"
+	data, err := os.ReadFile(legacy)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var logs []MonitorLog
+	if err := json.Unmarshal(data, &logs); err != nil {
+		return fmt.Errorf("corrupt log %s: %w", legacy, err)
+	}
+
+	var buf bytes.Buffer
+	for _, log := range logs {
+		line, err := json.Marshal(log)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	current, err := os.ReadFile(LogPath(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	buf.Write(current)
+	if err := replaceFile(LogPath(key), buf.Bytes()); err != nil {
+		return err
+	}
+	return os.Remove(legacy)
+}
+
+// replaceFile atomically replaces path with data.
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,180 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+func TestRunCheckLogsByName(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	checks := []struct {
+		check config.Check
+		runs  int
+		key   string
+	}{
+		{config.Check{Name: "a", URL: srv.URL}, 1, "check://a"},
+		{config.Check{Name: "b", URL: srv.URL}, 2, "check://b"},
+		{config.Check{Name: srv.URL, URL: srv.URL}, 3, srv.URL},
+	}
+	for _, c := range checks {
+		for i := 0; i < c.runs; i++ {
+			if _, _, err := RunCheck(c.check); err != nil {
+				t.Fatalf("RunCheck(%q): %v", c.check.Name, err)
+			}
+		}
+	}
+	for _, c := range checks {
+		logs, err := ReadLogs(c.key)
+		if err != nil {
+			t.Fatalf("ReadLogs(%q): %v", c.key, err)
+		}
+		if len(logs) != c.runs {
+			t.Errorf("%q has %d entries, want %d", c.key, len(logs), c.runs)
+		}
+	}
+}
+
+func TestAppendLogIsAtomicForReaders(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const key, writes = "check://busy", 50
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < writes; i++ {
+			if err := AppendLog(key, MonitorLog{Timestamp: time.Now(), URL: "http://example.com"}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	// Read without logMu, as the report package does.
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			logs, err := ReadLogs(key)
+			if err != nil || len(logs) != writes {
+				t.Fatalf("got %d entries (%v), want %d", len(logs), err, writes)
+			}
+			return
+		default:
+		}
+		if _, err := readLogFile(LogPath(key)); err != nil && !os.IsNotExist(err) {
+			t.Fatalf("reader saw a partly written log: %v", err)
+		}
+	}
+}
+
+func TestAppendLogKeepsCorruptLog(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const key = "check://corrupt"
+	if err := os.MkdirAll(LogDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(LogPath(key), []byte("{\"success\":true}\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadLogs(key); err == nil {
+		t.Fatal("ReadLogs accepted a corrupt line")
+	}
+	if err := AppendLog(key, MonitorLog{Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(LogPath(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "{\"success\":true}\nnot json\n{") {
+		t.Errorf("history was rewritten: %q", data)
+	}
+}
+
+func TestAppendLogCompacts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer func(size int64) { MaxLogSize = size }(MaxLogSize)
+	MaxLogSize = 4096
+	const key = "check://compact"
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		ts := now.Add(time.Duration(i-100) * time.Minute)
+		if i < 5 {
+			ts = now.Add(-LogRetention - time.Hour)
+		}
+		if err := AppendLog(key, MonitorLog{Timestamp: ts, URL: "http://example.com", Success: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := os.Stat(LogPath(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > MaxLogSize {
+		t.Errorf("log is %d bytes, want at most %d", info.Size(), MaxLogSize)
+	}
+	logs, err := ReadLogs(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) == 0 || len(logs) == 100 {
+		t.Fatalf("kept %d of 100 entries", len(logs))
+	}
+	if last := logs[len(logs)-1]; !last.Timestamp.Equal(now.Add(-time.Minute)) {
+		t.Errorf("newest entry is %v, want the last one appended", last.Timestamp)
+	}
+	for _, log := range logs {
+		if now.Sub(log.Timestamp) > LogRetention {
+			t.Errorf("kept an entry from %v, older than the retention window", log.Timestamp)
+		}
+	}
+}
+
+func TestReadLogsMigratesJSONArray(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const key = "check://legacy"
+	if err := os.MkdirAll(LogDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := strings.TrimSuffix(LogPath(key), "l")
+	if err := os.WriteFile(legacy, []byte(`[{"status_code":200},{"status_code":500}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendLog(key, MonitorLog{StatusCode: 204}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := ReadLogs(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []int
+	for _, log := range logs {
+		codes = append(codes, log.StatusCode)
+	}
+	if fmt.Sprint(codes) != "[200 500 204]" {
+		t.Errorf("status codes = %v, want [200 500 204]", codes)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("old log still exists: %v", err)
+	}
+}
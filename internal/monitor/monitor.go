@@ -3,17 +3,17 @@ package monitor
 import (
 	"crypto/sha1"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/smtp"
 	"net/url"
 	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/har"
 	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
 )
 
 type MonitorLog struct {
@@ -26,6 +26,26 @@ type MonitorLog struct {
 	Threshold     time.Duration `json:"threshold"`
 	Exceeded      bool          `json:"exceeded"`
 	Success       bool          `json:"success"`
+	Error         string        `json:"error,omitempty"`
+
+	// Metrics holds probe-specific measurements, e.g. tls_days_left.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+
+	// Insecure marks results obtained with TLS certificate verification disabled.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Attempts is recorded when the check was retried; an entry without it passed or failed first try.
+	Attempts []requester.Attempt `json:"attempts,omitempty"`
+
+	// Protocol is the negotiated HTTP protocol and Redirects the hops followed, for HTTP-based checks.
+	Protocol  string          `json:"protocol,omitempty"`
+	Redirects []requester.Hop `json:"redirects,omitempty"`
+
+	// Steps lists the requests of transaction checks with their own timings.
+	Steps []Step `json:"steps,omitempty"`
+
+	// har holds the exchange of HTTP-based checks run with HAROnFailure, written by RunCheck if the check fails.
+	har []har.Entry
 }
 
 // --- Utility Functions ---
@@ -40,30 +60,16 @@ func HashURL(url string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-func appendLogToFile(filename string, log MonitorLog) error {
-	var logs []MonitorLog
-	data, err := ioutil.ReadFile(filename)
-	if err == nil {
-		json.Unmarshal(data, &logs)
-	}
-	logs = append(logs, log)
-
-	newData, err := json.MarshalIndent(logs, "", "  ")
-	if err != nil {
-		return err
-	}
-	return ioutil.WriteFile(filename, newData, 0644)
-}
-
 // --- Email Alert ---
-func sendEmailAlert(message string, userEmail string) {
+// SendEmailAlert emails the message to userEmail using the SMTP settings from the environment.
+func SendEmailAlert(message string, userEmail string) {
 	smtpHost := os.Getenv("SMTP_HOST")
 	smtpPort := os.Getenv("SMTP_PORT")
 	sender := os.Getenv("EMAIL_SENDER")
 	password := os.Getenv("EMAIL_PASSWORD")
 
 	if smtpHost == "" || smtpPort == "" || sender == "" || password == "" || userEmail == "" {
-		fmt.Println("⚠️ Email config missing or user email not provided")
+		fmt.Fprintln(secret.Stdout, "⚠️ Email config missing or user email not provided")
 		return
 	}
 
@@ -73,89 +79,117 @@ func sendEmailAlert(message string, userEmail string) {
 
 	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, sender, []string{userEmail}, []byte(subject+body))
 	if err != nil {
-		fmt.Println("❌ Email alert failed:", err)
+		fmt.Fprintln(secret.Stdout, "❌ Email alert failed:", err)
 		return
 	}
-	fmt.Println("✅ Email alert sent to", userEmail)
+	fmt.Fprintln(secret.Stdout, "✅ Email alert sent to", userEmail)
 }
 
 // --- Monitor Logic ---
 func ExecuteMonitor(
-	rawURL, method, headers, body, timeout string,
+	check config.Check,
 	pretty bool,
-	interval, duration, threshold time.Duration,
+	duration time.Duration,
 	alert bool,
 	userEmail string,
 ) (string, string, time.Duration, int, string, time.Time, error, bool) {
-	if !isValidURL(rawURL) {
+	check = check.WithDefaults()
+	if check.Type == config.TypeHTTP && !isValidURL(check.URL) {
 		return "", "", 0, 0, "", time.Time{}, errors.New("Invalid URL"), false
 	}
+	if err := ValidateTypes([]config.Check{check}); err != nil {
+		return "", "", 0, 0, "", time.Time{}, err, false
+	}
+
+	if check.TLS != nil && check.TLS.Insecure {
+		fmt.Fprintln(secret.Stdout, "⚠️  INSECURE: TLS certificate verification is disabled; results are marked insecure in the logs.")
+	}
 
 	start := time.Now()
 	end := start.Add(duration)
+	interval := check.Interval.Std()
+	threshold := check.Threshold.Std()
 	lastStatus := 0
 	lastStatusText := ""
 	var err error
 
-	logDir := "logs"
-	os.MkdirAll(logDir, os.ModePerm)
-	logFile := filepath.Join(logDir, fmt.Sprintf("monitor_%s.json", HashURL(rawURL)))
-
 	var totalChecks int
-	var thresholdExceeded int
+	var failedChecks int
 
 	for t := start; t.Before(end); t = t.Add(interval) {
 		totalChecks++
 
-		response, status, execDuration, reqErr := requester.ExecuteRequest(rawURL, method, headers, body, timeout, pretty)
+		// A probe error such as a refused connection or NXDOMAIN is an outage to record, not a
+		// reason to stop; err reports whether the last check still failed that way.
+		log, output, reqErr := RunCheck(check)
+		err = reqErr
 		if reqErr != nil {
-			fmt.Println("❌ Request Error:", reqErr)
-			err = reqErr
-			break
+			fmt.Fprintln(secret.Stdout, "❌ Request Error:", reqErr)
+			failedChecks++
+			lastStatus = log.StatusCode
+			lastStatusText = log.StatusText
+			time.Sleep(interval)
+			continue
 		}
 
-		fmt.Printf("✅ HTTP %d\n", status)
-		fmt.Println(response)
-		fmt.Println("⏱️ Execution Time:", execDuration)
-
-		exceeded := execDuration > threshold
-		if exceeded {
-			thresholdExceeded++
-			fmt.Printf("🚨 Terminal Alert: API: %s exceeded threshold (%s > %s)\n", rawURL, execDuration, threshold)
+		if check.Type == config.TypeHTTP {
+			fmt.Fprintf(secret.Stdout, "✅ HTTP %d\n", log.StatusCode)
+		} else {
+			fmt.Fprintln(secret.Stdout, "✅", log.Method, log.StatusText)
+		}
+		fmt.Fprintln(secret.Stdout, output)
+		fmt.Fprintln(secret.Stdout, "⏱️ Execution Time:", log.ExecutionTime)
+		if retries := len(log.Attempts) - 1; retries > 0 {
+			outcome := "Passed"
+			if !log.Success {
+				outcome = "Failed"
+			}
+			fmt.Fprintf(secret.Stdout, "🔁 %s after %d retries:", outcome, retries)
+			for _, a := range log.Attempts[:retries] {
+				fmt.Fprintf(secret.Stdout, " %s (waited %s);", a.Retry, a.Wait)
+			}
+			fmt.Fprintln(secret.Stdout)
 		}
 
-		lastStatus = status
-		lastStatusText = httpStatusText(status)
-
-		log := MonitorLog{
-			Timestamp:     time.Now(),
-			URL:           rawURL,
-			Method:        method,
-			StatusCode:    status,
-			StatusText:    lastStatusText,
-			ExecutionTime: execDuration,
-			Threshold:     threshold,
-			Exceeded:      exceeded,
-			Success:       !exceeded && reqErr == nil,
+		if len(log.Redirects) > 0 {
+			fmt.Fprintf(secret.Stdout, "↪️  Followed %d redirect(s) to %s\n", len(log.Redirects), log.Redirects[len(log.Redirects)-1].Location)
 		}
-		appendLogToFile(logFile, log)
+		if log.Insecure {
+			fmt.Fprintln(secret.Stdout, "⚠️  INSECURE: certificate not verified")
+		}
+		if log.Exceeded {
+			fmt.Fprintf(secret.Stdout, "🚨 Terminal Alert: API: %s exceeded threshold (%s > %s)\n", check.URL, log.ExecutionTime, threshold)
+		}
+		if log.Error != "" {
+			fmt.Fprintf(secret.Stdout, "🚨 Terminal Alert: %s: %s\n", check.URL, log.Error)
+		}
+		if !log.Success {
+			failedChecks++
+		}
+
+		lastStatus = log.StatusCode
+		lastStatusText = log.StatusText
 
 		time.Sleep(interval)
 	}
 
 	success := true
-	failureRate := float64(thresholdExceeded) / float64(totalChecks)
+	failureRate := float64(failedChecks) / float64(totalChecks)
 	const failureThreshold = 0.2 // 20%
 
 	if alert && duration > 2*time.Minute && failureRate >= failureThreshold {
-		message := fmt.Sprintf("⚠️ %d out of %d requests (%.2f%%) exceeded the threshold of %s.\nURL: %s",
-			thresholdExceeded, totalChecks, failureRate*100, threshold, rawURL)
+		message := fmt.Sprintf("⚠️ %d out of %d checks (%.2f%%) failed or exceeded the threshold of %s.\nURL: %s",
+			failedChecks, totalChecks, failureRate*100, threshold, check.URL)
+		if threshold == 0 {
+			message = fmt.Sprintf("⚠️ %d out of %d checks (%.2f%%) failed.\nURL: %s",
+				failedChecks, totalChecks, failureRate*100, check.URL)
+		}
 
-		sendEmailAlert(message, userEmail)
+		SendEmailAlert(message, userEmail)
 		success = false
 	}
 
-	return "Monitoring complete", rawURL, duration, lastStatus, lastStatusText, time.Now(), err, success
+	return "Monitoring complete", check.URL, duration, lastStatus, lastStatusText, time.Now(), err, success
 }
 
 // --- HTTP Status to Text ---
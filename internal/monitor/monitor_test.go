@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+func TestExecuteMonitorReturnsRequestErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	check := config.Check{Name: "refused", URL: "http://" + addr, Interval: config.Duration(100 * time.Millisecond), Timeout: "1s"}
+	_, _, _, _, _, _, err, _ = ExecuteMonitor(check, false, 250*time.Millisecond, false, "")
+	if err == nil {
+		t.Fatal("ExecuteMonitor returned no error for a refused connection")
+	}
+}
+
+func TestExecuteMonitorContinuesAfterOutage(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	// The target is down for the first tick and back up before the second.
+	up := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Error(err)
+		}
+		up <- ln
+	}()
+	defer func() {
+		if ln := <-up; ln != nil {
+			ln.Close()
+		}
+	}()
+
+	check := config.Check{Name: "flaky", Type: config.TypeTCP, URL: addr, Interval: config.Duration(150 * time.Millisecond), Timeout: "1s"}
+	_, _, _, _, _, _, err, _ = ExecuteMonitor(check, false, 400*time.Millisecond, false, "")
+	if err != nil {
+		t.Errorf("ExecuteMonitor returned %v after the target recovered", err)
+	}
+
+	logs, err := ReadLogs(check.LogKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) < 2 {
+		t.Fatalf("monitor stopped after %d check(s)", len(logs))
+	}
+	if logs[0].Success || logs[0].Error == "" {
+		t.Errorf("first check = %+v, want the outage recorded", logs[0])
+	}
+	if !logs[len(logs)-1].Success {
+		t.Errorf("last check = %+v, want it to pass", logs[len(logs)-1])
+	}
+}
+
+func TestTLSCheckIgnoresDefaultThreshold(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	tests := []struct {
+		threshold config.Duration
+		exceeded  bool
+	}{
+		{0, false},
+		{config.Duration(time.Nanosecond), true},
+	}
+	for _, tt := range tests {
+		check := config.Check{Name: "tls", Type: config.TypeTLS, URL: srv.URL, Threshold: tt.threshold}
+		log, _, err := RunCheck(check)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if log.Exceeded != tt.exceeded {
+			t.Errorf("threshold %s: exceeded = %v, want %v (handshake %s)", tt.threshold, log.Exceeded, tt.exceeded, log.ExecutionTime)
+		}
+	}
+}
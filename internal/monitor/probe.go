@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+// Probe runs one check of a given type. It fills in the protocol-specific parts of the entry
+// (Method, StatusCode, StatusText, ExecutionTime, Metrics) and records assertion failures in
+// Error; RunCheck adds the timestamp, threshold and success fields and writes the log.
+// A returned error means the probe could not complete at all.
+type Probe func(check config.Check) (MonitorLog, string, error)
+
+var (
+	probesMu sync.RWMutex
+	probes   = map[string]Probe{
+		config.TypeHTTP:        runHTTPCheck,
+		config.TypeTLS:         runTLSCheck,
+		config.TypeTCP:         runTCPCheck,
+		config.TypeUDP:         runUDPCheck,
+		config.TypeDNS:         runDNSCheck,
+		config.TypeGRPC:        runGRPCCheck,
+		config.TypeWS:          runWSCheck,
+		config.TypeStream:      runStreamCheck,
+		config.TypeGraphQL:     runGraphQLCheck,
+		config.TypeTransaction: runTransactionCheck,
+	}
+)
+
+// RegisterProbe makes a new check type available to RunCheck, the monitor command and the daemon.
+func RegisterProbe(checkType string, probe Probe) {
+	probesMu.Lock()
+	defer probesMu.Unlock()
+	probes[checkType] = probe
+}
+
+func probeFor(checkType string) (Probe, bool) {
+	probesMu.RLock()
+	defer probesMu.RUnlock()
+	probe, ok := probes[checkType]
+	return probe, ok
+}
+
+// CheckTypes lists the registered check types.
+func CheckTypes() []string {
+	probesMu.RLock()
+	defer probesMu.RUnlock()
+	types := make([]string, 0, len(probes))
+	for t := range probes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ValidateTypes rejects checks whose type has no registered probe.
+func ValidateTypes(checks []config.Check) error {
+	for _, check := range checks {
+		check = check.WithDefaults()
+		if _, ok := probeFor(check.Type); !ok {
+			return fmt.Errorf("check %q: unknown type %q (supported: %v)", check.Name, check.Type, CheckTypes())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+)
+
+// runDNSCheck resolves the check's name for RecordType, optionally against a specific resolver,
+// and fails when any of the expected Answers is missing from the response.
+func runDNSCheck(check config.Check) (MonitorLog, string, error) {
+	name := config.HostPort(check.URL)
+	recordType := strings.ToUpper(check.RecordType)
+	log := MonitorLog{Method: "DNS " + recordType}
+
+	resolver := net.DefaultResolver
+	if check.Resolver != "" {
+		server := check.Resolver
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout(check))
+	defer cancel()
+
+	span := telemetry.StartCheck("pingify.monitor.dns", log.Method, name)
+	start := time.Now()
+	answers, err := lookup(ctx, resolver, recordType, name)
+	log.ExecutionTime = time.Since(start)
+	span.End(nil, err)
+	if err != nil {
+		return log, "", fmt.Errorf("DNS %s lookup of %s failed: %w", recordType, name, err)
+	}
+
+	log.StatusText = fmt.Sprintf("%d answers", len(answers))
+	var missing []string
+	for _, want := range check.Answers {
+		if !slices.ContainsFunc(answers, func(got string) bool { return strings.EqualFold(got, strings.TrimSuffix(want, ".")) }) {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		log.Error = fmt.Sprintf("expected answers missing: %s (got %s)", strings.Join(missing, ", "), strings.Join(answers, ", "))
+	}
+	return log, strings.Join(answers, "\n"), nil
+}
+
+// lookup returns the answers for one record type, with trailing dots removed from names.
+func lookup(ctx context.Context, r *net.Resolver, recordType, name string) ([]string, error) {
+	var answers []string
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := r.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, mx.Host)
+		}
+	case "NS":
+		records, err := r.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			answers = append(answers, ns.Host)
+		}
+	case "TXT":
+		records, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = records
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+
+	for i, a := range answers {
+		answers[i] = strings.TrimSuffix(a, ".")
+	}
+	return answers, nil
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSServer answers queries for api.pingify.test over UDP with fixed A, TXT and MX records,
+// and NXDOMAIN for every other name.
+func startDNSServer(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	zone := dnsmessage.MustNewName("api.pingify.test.")
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			header, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+			header.Response, header.Authoritative, header.RecursionAvailable = true, true, true
+			if !strings.EqualFold(q.Name.String(), zone.String()) {
+				header.RCode = dnsmessage.RCodeNameError
+			}
+			b := dnsmessage.NewBuilder(nil, header)
+			b.EnableCompression()
+			b.StartQuestions()
+			b.Question(q)
+			b.StartAnswers()
+			rr := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+			if header.RCode == dnsmessage.RCodeSuccess {
+				switch q.Type {
+				case dnsmessage.TypeA:
+					b.AResource(rr, dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+					b.AResource(rr, dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}})
+				case dnsmessage.TypeTXT:
+					b.TXTResource(rr, dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}})
+				case dnsmessage.TypeMX:
+					b.MXResource(rr, dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.pingify.test.")})
+				}
+			}
+			msg, err := b.Finish()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(msg, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDNSCheck(t *testing.T) {
+	t.Chdir(t.TempDir())
+	resolver := startDNSServer(t)
+
+	tests := []struct {
+		name    string
+		check   config.Check
+		success bool
+		errText string // expected in log.Error
+		output  string // expected in the probe output
+	}{
+		{"a records", config.Check{}, true, "", "10.0.0.1\n10.0.0.2"},
+		{"expected answers", config.Check{Answers: []string{"10.0.0.2", "10.0.0.1"}}, true, "", ""},
+		{"missing answer", config.Check{Answers: []string{"10.0.0.1", "10.0.0.9"}}, false, "expected answers missing: 10.0.0.9", ""},
+		{"txt record", config.Check{RecordType: "txt", Answers: []string{"v=spf1 -all"}}, true, "", "v=spf1 -all"},
+		{"mx with trailing dot", config.Check{RecordType: "MX", Answers: []string{"MAIL.pingify.test."}}, true, "", "mail.pingify.test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.check
+			check.Name = tt.name
+			check.Type = config.TypeDNS
+			check.URL = "api.pingify.test"
+			check.Resolver = resolver
+			check.Timeout = "2s"
+			log, output, err := RunCheck(check)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if log.Success != tt.success {
+				t.Errorf("success = %v, want %v (error %q)", log.Success, tt.success, log.Error)
+			}
+			if !strings.Contains(log.Error, tt.errText) {
+				t.Errorf("error = %q, want it to mention %q", log.Error, tt.errText)
+			}
+			if !strings.Contains(output, tt.output) {
+				t.Errorf("output = %q, want it to contain %q", output, tt.output)
+			}
+		})
+	}
+}
+
+func TestDNSCheckUnknownName(t *testing.T) {
+	t.Chdir(t.TempDir())
+	check := config.Check{Name: "nx", Type: config.TypeDNS, URL: "nope.pingify.test", Resolver: startDNSServer(t), Timeout: "2s"}
+	if _, _, err := RunCheck(check); err == nil || !strings.Contains(err.Error(), "lookup of nope.pingify.test failed") {
+		t.Errorf("err = %v, want the failed lookup reported", err)
+	}
+}
@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/graphql"
+	"github.com/Aditya251610/pingify/internal/har"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+)
+
+// runGraphQLCheck POSTs the check's query and fails on HTTP errors or a non-empty errors array,
+// even when the status is 200.
+func runGraphQLCheck(check config.Check) (MonitorLog, string, error) {
+	log := MonitorLog{Method: "POST"}
+
+	auth, signer, err := credentials(check)
+	if err != nil {
+		return log, "", err
+	}
+
+	query := check.Query
+	if check.QueryFile != "" {
+		q, err := graphql.LoadQuery(check.QueryFile)
+		if err != nil {
+			return log, "", err
+		}
+		query = q
+	}
+
+	httpOpts, err := httpOptions(check)
+	if err != nil {
+		return log, "", err
+	}
+	maxBody, err := maxBody(check)
+	if err != nil {
+		return log, "", err
+	}
+
+	span := telemetry.StartCheck("pingify.monitor.graphql", log.Method, check.URL)
+	result, err := graphql.Do(graphql.Request{
+		URL:           check.URL,
+		Query:         query,
+		Variables:     check.Variables,
+		OperationName: check.Operation,
+		Headers:       check.Headers,
+		Timeout:       check.Timeout,
+		Header:        span.Header(),
+		Retry:         retryPolicy(check),
+		Auth:          auth,
+		Signer:        signer,
+		TLS:           tlsOptions(check),
+		Net:           netOptions(check),
+		HTTP:          httpOpts,
+		MaxBody:       maxBody,
+	})
+	if result == nil {
+		span.End(nil, err)
+		return log, "", err
+	}
+	span.End(result.Response, err)
+
+	log.StatusCode = result.Response.StatusCode
+	log.StatusText = httpStatusText(result.Response.StatusCode)
+	log.ExecutionTime = result.Response.Duration
+	log.Protocol = result.Response.Proto
+	log.Redirects = result.Response.Redirects
+	if check.HAROnFailure != "" {
+		log.har = har.FromResponse(result.Response, err)
+	}
+	if len(result.Response.Attempts) > 1 {
+		log.Attempts = result.Response.Attempts
+	}
+	if err != nil {
+		return log, "", err
+	}
+	log.Error = result.Problem()
+	log.Metrics = map[string]float64{"graphql_errors": float64(len(result.Errors))}
+	return log, result.PrettyData(), nil
+}
@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/grpccall"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+)
+
+// runGRPCCheck performs a health check or unary call and fails when the status code differs from
+// ExpectCode (OK by default) or a health check doesn't report SERVING.
+func runGRPCCheck(check config.Check) (MonitorLog, string, error) {
+	log := MonitorLog{Method: "GRPC"}
+
+	expect, err := grpccall.ParseCode(check.ExpectCode)
+	if err != nil {
+		return log, "", err
+	}
+	md, err := grpccall.ParseMetadata(check.Headers)
+	if err != nil {
+		return log, "", err
+	}
+
+	tlsConf, err := tlsConfig(check)
+	if err != nil {
+		return log, "", err
+	}
+	dialer, err := netOptions(check).GRPCDialer()
+	if err != nil {
+		return log, "", err
+	}
+
+	body, err := requester.ReadData(check.Body)
+	if err != nil {
+		return log, "", err
+	}
+
+	span := telemetry.StartCheck("pingify.monitor.grpc", log.Method, check.URL)
+	resp, err := grpccall.Do(grpccall.Request{
+		Target:    check.URL,
+		Plaintext: check.Plaintext,
+		Service:   check.GRPCService,
+		Method:    check.GRPCMethod,
+		Body:      body,
+		Metadata:  md,
+		Timeout:   checkTimeout(check),
+		Protoset:  check.Protoset,
+		TLS:       tlsConf,
+		Dialer:    dialer,
+	})
+	if err != nil {
+		span.End(nil, err)
+		return log, "", err
+	}
+	span.End(nil, nil)
+
+	log.StatusCode = int(resp.Code)
+	log.StatusText = resp.Code.String()
+	log.ExecutionTime = resp.Duration
+	switch {
+	case resp.Code != expect:
+		log.Error = fmt.Sprintf("expected status %s, got %s: %s", expect, resp.Code, resp.Message)
+	case check.GRPCMethod == "" && resp.HealthStatus != "" && resp.HealthStatus != "SERVING":
+		log.Error = "health status " + resp.HealthStatus
+	}
+	return log, resp.Body, nil
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// startGRPCServer runs an in-process server with the health service and reflection. Unary calls
+// carrying an x-token other than "s3cret" in their metadata are rejected as Unauthenticated.
+func startGRPCServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if v := md.Get("x-token"); len(v) > 0 && v[0] != "s3cret" {
+			return nil, status.Error(codes.Unauthenticated, "bad token")
+		}
+		return handler(ctx, req)
+	}
+	srv := grpc.NewServer(grpc.UnaryInterceptor(auth))
+	hs := health.NewServer()
+	hs.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("billing", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	reflection.Register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestGRPCCheck(t *testing.T) {
+	t.Chdir(t.TempDir())
+	addr := startGRPCServer(t)
+
+	tests := []struct {
+		name    string
+		check   config.Check
+		success bool
+		errText string // expected in log.Error
+		output  string // expected in the probe output
+	}{
+		{"server health", config.Check{}, true, "", ""},
+		{"service serving", config.Check{GRPCService: "orders"}, true, "", ""},
+		{"service not serving", config.Check{GRPCService: "billing"}, false, "health status NOT_SERVING", ""},
+		{"unknown service", config.Check{GRPCService: "nope"}, false, "expected status OK, got NotFound", ""},
+		{"expected code", config.Check{GRPCService: "nope", ExpectCode: "NOT_FOUND"}, true, "", ""},
+		{"metadata sent", config.Check{Headers: `{"x-token":"s3cret"}`}, true, "", ""},
+		{"metadata rejected", config.Check{Headers: `{"x-token":"wrong"}`}, false, "got Unauthenticated", ""},
+		{"unary call via reflection", config.Check{GRPCMethod: "grpc.health.v1.Health/Check", Body: `{"service":"orders"}`}, true, "", "SERVING"},
+		{"unary call with grpc:// target", config.Check{URL: "grpc://" + addr, GRPCMethod: "grpc.health.v1.Health/Check", Body: `{"service":"billing"}`}, true, "", "NOT_SERVING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.check
+			check.Name = tt.name
+			check.Type = config.TypeGRPC
+			if check.URL == "" {
+				check.URL = addr
+				check.Plaintext = true
+			}
+			log, output, err := RunCheck(check)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if log.Success != tt.success {
+				t.Errorf("success = %v, want %v (error %q)", log.Success, tt.success, log.Error)
+			}
+			if !strings.Contains(log.Error, tt.errText) {
+				t.Errorf("error = %q, want it to mention %q", log.Error, tt.errText)
+			}
+			if !strings.Contains(output, tt.output) {
+				t.Errorf("output = %q, want it to contain %q", output, tt.output)
+			}
+		})
+	}
+}
+
+func TestGRPCCheckUnreachable(t *testing.T) {
+	t.Chdir(t.TempDir())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	log, _, err := RunCheck(config.Check{Name: "down", Type: config.TypeGRPC, URL: addr, Plaintext: true, Timeout: "1s"})
+	if err == nil && log.Success {
+		t.Fatal("check against a closed port passed")
+	}
+}
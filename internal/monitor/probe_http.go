@@ -0,0 +1,257 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/har"
+	"github.com/Aditya251610/pingify/internal/jsonpath"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+)
+
+func runHTTPCheck(check config.Check) (MonitorLog, string, error) {
+	auth, signer, err := credentials(check)
+	if err != nil {
+		return MonitorLog{Method: check.Method}, "", err
+	}
+	httpOpts, err := httpOptions(check)
+	if err != nil {
+		return MonitorLog{Method: check.Method}, "", err
+	}
+	body, contentType, err := bodyOptions(check).Load()
+	if err != nil {
+		return MonitorLog{Method: check.Method}, "", err
+	}
+	maxBody, err := maxBody(check)
+	if err != nil {
+		return MonitorLog{Method: check.Method}, "", err
+	}
+	span := telemetry.StartCheck("pingify.monitor.check", check.Method, check.URL)
+	resp, err := requester.Do(requester.Request{
+		URL:         check.URL,
+		Method:      check.Method,
+		Headers:     check.Headers,
+		Body:        body,
+		ContentType: contentType,
+		Timeout:     check.Timeout,
+		Header:      span.Header(),
+		Retry:       retryPolicy(check),
+		Auth:        auth,
+		Signer:      signer,
+		TLS:         tlsOptions(check),
+		Net:         netOptions(check),
+		HTTP:        httpOpts,
+		MaxBody:     maxBody,
+	})
+	span.End(resp, err)
+
+	log := MonitorLog{Method: check.Method}
+	if resp == nil {
+		return log, "", err
+	}
+	log.StatusCode = resp.StatusCode
+	log.StatusText = httpStatusText(resp.StatusCode)
+	log.ExecutionTime = resp.Duration
+	log.Protocol = resp.Proto
+	log.Redirects = resp.Redirects
+	if check.HAROnFailure != "" {
+		log.har = har.FromResponse(resp, err)
+	}
+	if len(resp.Attempts) > 1 {
+		log.Attempts = resp.Attempts
+	}
+	if resp.Encoding != "" {
+		log.Metrics = map[string]float64{"body_bytes": float64(resp.Size), "wire_bytes": float64(resp.WireSize)}
+	}
+	if err != nil {
+		return log, responseOutput(resp), err
+	}
+	output, problem := checkContent(check, resp)
+	log.Error = problem
+	return log, output, nil
+}
+
+// checkContent applies the check's select and expect to the response, returning what to print
+// and why the content fails the check, if it does.
+func checkContent(check config.Check, resp *requester.Response) (string, string) {
+	if check.Select == "" {
+		if check.Expect != "" && !strings.Contains(resp.Body, check.Expect) {
+			return responseOutput(resp), fmt.Sprintf("response does not contain %q", check.Expect)
+		}
+		return responseOutput(resp), ""
+	}
+
+	values, err := jsonpath.Select(check.Select, resp.Body, resp.Header)
+	if err != nil {
+		return responseOutput(resp), fmt.Sprintf("select %s: %v", check.Select, err)
+	}
+	if len(values) == 0 {
+		return responseOutput(resp), fmt.Sprintf("select %s matched nothing", check.Select)
+	}
+	output := jsonpath.Format(values)
+	if check.Expect != "" && !strings.Contains(output, check.Expect) {
+		return output, fmt.Sprintf("select %s: %q does not contain %q", check.Select, output, check.Expect)
+	}
+	return output, ""
+}
+
+// defaultMaxBody keeps long monitor runs from buffering unbounded responses.
+const defaultMaxBody = 10_000_000
+
+// maxBody parses the check's max_body, falling back to defaultMaxBody.
+func maxBody(check config.Check) (int64, error) {
+	if check.MaxBody == "" {
+		return defaultMaxBody, nil
+	}
+	return requester.ParseSize(check.MaxBody)
+}
+
+// responseOutput is the body as printed by the monitor: binary bodies are summarised and
+// truncation is noted.
+func responseOutput(resp *requester.Response) string {
+	out := resp.Body
+	if resp.Binary {
+		out = fmt.Sprintf("<binary body, %s>", requester.FormatSize(resp.Size))
+	}
+	if resp.Truncated {
+		out += fmt.Sprintf("\n… truncated at %s", requester.FormatSize(resp.Size))
+	}
+	return out
+}
+
+// credentials builds the check's authenticator and signer. OAuth2 tokens are cached across runs by requester.
+func credentials(check config.Check) (requester.Authenticator, requester.Signer, error) {
+	var auth requester.Authenticator
+	var signer requester.Signer
+	var err error
+	if a := check.Auth; a != nil {
+		auth, err = requester.NewAuthenticator(requester.AuthOptions{
+			Type:         a.Type,
+			Username:     a.Username,
+			Password:     a.Password,
+			Token:        a.Token,
+			APIKeyName:   a.APIKeyName,
+			APIKeyValue:  a.APIKeyValue,
+			APIKeyIn:     a.APIKeyIn,
+			TokenURL:     a.TokenURL,
+			ClientID:     a.ClientID,
+			ClientSecret: a.ClientSecret,
+			Scopes:       a.Scopes,
+			RefreshToken: a.RefreshToken,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if s := check.Sign; s != nil {
+		signer, err = requester.NewSigner(requester.SignOptions{
+			Type:            s.Type,
+			Region:          s.Region,
+			Service:         s.Service,
+			Profile:         s.Profile,
+			Secret:          s.Secret,
+			Header:          s.Header,
+			Algorithm:       s.Algorithm,
+			Encoding:        s.Encoding,
+			Prefix:          s.Prefix,
+			TimestampHeader: s.TimestampHeader,
+			Parts:           s.Parts,
+			Separator:       s.Separator,
+		})
+	}
+	return auth, signer, err
+}
+
+// tlsOptions converts the check's TLS client settings, or returns nil for Go's defaults.
+func tlsOptions(check config.Check) *requester.TLSOptions {
+	t := check.TLS
+	if t == nil {
+		return nil
+	}
+	return &requester.TLSOptions{
+		CertFile:       t.Cert,
+		KeyFile:        t.Key,
+		PKCS12File:     t.PKCS12,
+		PKCS12Password: t.PKCS12Password,
+		CAFile:         t.CA,
+		ServerName:     t.ServerName,
+		MinVersion:     t.MinVersion,
+		MaxVersion:     t.MaxVersion,
+		CipherSuites:   t.Ciphers,
+		Insecure:       t.Insecure,
+	}
+}
+
+// tlsConfig builds the tls.Config for checks that dial without requester, or nil for the defaults.
+func tlsConfig(check config.Check) (*tls.Config, error) {
+	opts := tlsOptions(check)
+	if opts.IsZero() {
+		return nil, nil
+	}
+	return opts.Config()
+}
+
+// netOptions converts the check's connection routing, or returns nil for Go's defaults.
+func netOptions(check config.Check) *requester.NetOptions {
+	n := check.Network
+	if n == nil {
+		return nil
+	}
+	return &requester.NetOptions{
+		Proxy:        n.Proxy,
+		NoProxy:      n.NoProxy,
+		Resolve:      n.Resolve,
+		ConnectTo:    n.ConnectTo,
+		IPVersion:    n.IPVersion,
+		LocalAddress: n.LocalAddress,
+		UnixSocket:   n.UnixSocket,
+	}
+}
+
+// bodyOptions collects the check's body sources.
+func bodyOptions(check config.Check) requester.BodyOptions {
+	return requester.BodyOptions{
+		Data:       check.Body,
+		Binary:     check.DataBinary,
+		Form:       check.Form,
+		URLEncoded: check.URLEncoded,
+	}
+}
+
+// httpOptions converts the check's redirect, cookie and protocol settings, or returns nil for
+// Go's defaults. Cookie jars are shared by file, so they carry over between runs.
+func httpOptions(check config.Check) (*requester.HTTPOptions, error) {
+	h := check.HTTP
+	if h == nil {
+		return nil, nil
+	}
+	opts := &requester.HTTPOptions{
+		NoRedirects:  h.NoRedirects,
+		MaxRedirects: h.MaxRedirects,
+		Version:      h.Version,
+	}
+	if h.CookieJar != "" {
+		jar, err := requester.OpenCookieJar(h.CookieJar)
+		if err != nil {
+			return nil, err
+		}
+		opts.Jar = jar
+	}
+	return opts, nil
+}
+
+// retryPolicy converts the check's retry settings for requester.Do.
+func retryPolicy(check config.Check) requester.RetryPolicy {
+	if check.Retry == nil {
+		return requester.RetryPolicy{}
+	}
+	return requester.RetryPolicy{
+		MaxAttempts: check.Retry.Attempts,
+		Backoff:     check.Retry.Backoff.Std(),
+		MaxBackoff:  check.Retry.MaxBackoff.Std(),
+		RetryOn:     check.Retry.OnStatus,
+	}
+}
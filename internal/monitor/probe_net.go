@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+)
+
+// bannerLimit caps how much of a TCP/UDP reply is read and logged.
+const bannerLimit = 4096
+
+func checkTimeout(check config.Check) time.Duration {
+	timeout, err := time.ParseDuration(check.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout, _ = time.ParseDuration(config.DefaultTimeout)
+	}
+	return timeout
+}
+
+// runTCPCheck connects to the target and, when configured, sends a payload and expects a reply
+// containing Expect (e.g. send "PING\r\n", expect "+PONG" for Redis).
+func runTCPCheck(check config.Check) (MonitorLog, string, error) {
+	return runSocketCheck(check, "tcp")
+}
+
+// runUDPCheck sends the payload in one datagram and expects a reply containing Expect.
+func runUDPCheck(check config.Check) (MonitorLog, string, error) {
+	return runSocketCheck(check, "udp")
+}
+
+func runSocketCheck(check config.Check, network string) (MonitorLog, string, error) {
+	addr := config.HostPort(check.URL)
+	timeout := checkTimeout(check)
+	log := MonitorLog{Method: strings.ToUpper(network)}
+
+	span := telemetry.StartCheck("pingify.monitor."+network, log.Method, check.URL)
+	start := time.Now()
+	conn, err := net.DialTimeout(network, addr, timeout)
+	if err != nil {
+		span.End(nil, err)
+		return log, "", fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+	connected := time.Since(start)
+
+	log.StatusText = "Connected"
+	log.Metrics = map[string]float64{"connect_ms": ms(connected)}
+
+	var reply string
+	if check.Send != "" || check.Expect != "" {
+		_ = conn.SetDeadline(start.Add(timeout))
+		if check.Send != "" {
+			if _, err := conn.Write([]byte(check.Send)); err != nil {
+				span.End(nil, err)
+				return log, "", fmt.Errorf("failed to send to %s: %w", addr, err)
+			}
+		}
+
+		buf := make([]byte, bannerLimit)
+		n, err := readReply(conn, buf, check.Expect)
+		reply = string(buf[:n])
+		if err != nil && n == 0 {
+			span.End(nil, err)
+			return log, "", fmt.Errorf("no reply from %s: %w", addr, err)
+		}
+		log.StatusText = "Replied"
+		if check.Expect != "" && !strings.Contains(reply, check.Expect) {
+			log.Error = fmt.Sprintf("reply %q does not contain %q", truncate(reply, 80), check.Expect)
+		}
+	}
+	log.ExecutionTime = time.Since(start)
+	span.End(nil, nil)
+	return log, reply, nil
+}
+
+// readReply reads until the expected text arrives, the buffer fills or the deadline passes.
+// UDP replies are a single datagram, so one read is enough there.
+func readReply(conn net.Conn, buf []byte, expect string) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if _, isUDP := conn.(*net.UDPConn); isUDP || expect == "" || strings.Contains(string(buf[:total]), expect) {
+			return total, nil
+		}
+	}
+	return total, nil
+}
+
+// ms converts a duration to fractional milliseconds for MonitorLog.Metrics.
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
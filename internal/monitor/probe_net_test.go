@@ -0,0 +1,138 @@
+package monitor
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+// startTCPServer answers "PING" lines with "+PONG", like a Redis server would, and anything else
+// with "-ERR" before closing the connection.
+func startTCPServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				lines := bufio.NewScanner(conn)
+				for lines.Scan() {
+					if strings.TrimSpace(lines.Text()) != "PING" {
+						conn.Write([]byte("-ERR unknown command\r\n"))
+						return
+					}
+					conn.Write([]byte("+PONG\r\n"))
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// startUDPServer echoes each datagram back upper-cased.
+func startUDPServer(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo([]byte(strings.ToUpper(string(buf[:n]))), addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestSocketChecks(t *testing.T) {
+	t.Chdir(t.TempDir())
+	tcpAddr := startTCPServer(t)
+	udpAddr := startUDPServer(t)
+
+	tests := []struct {
+		name    string
+		check   config.Check
+		success bool
+		status  string
+		errText string // expected in log.Error
+		output  string // expected in the probe output
+	}{
+		{"tcp connect", config.Check{Type: config.TypeTCP, URL: tcpAddr}, true, "Connected", "", ""},
+		{"tcp scheme", config.Check{Type: config.TypeTCP, URL: "tcp://" + tcpAddr}, true, "Connected", "", ""},
+		{"tcp send and expect", config.Check{Type: config.TypeTCP, URL: tcpAddr, Send: "PING\r\n", Expect: "+PONG"}, true, "Replied", "", "+PONG"},
+		{"tcp unexpected reply", config.Check{Type: config.TypeTCP, URL: tcpAddr, Send: "HELLO\r\n", Expect: "+PONG"}, false, "Replied", `does not contain "+PONG"`, "-ERR"},
+		{"udp send and expect", config.Check{Type: config.TypeUDP, URL: "udp://" + udpAddr, Send: "ping", Expect: "PING"}, true, "Replied", "", "PING"},
+		{"udp unexpected reply", config.Check{Type: config.TypeUDP, URL: udpAddr, Send: "ping", Expect: "pong"}, false, "Replied", `does not contain "pong"`, "PING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.check
+			check.Name = tt.name
+			check.Timeout = "2s"
+			log, output, err := RunCheck(check)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if log.Success != tt.success || log.StatusText != tt.status {
+				t.Errorf("success = %v, status = %q; want %v, %q (error %q)", log.Success, log.StatusText, tt.success, tt.status, log.Error)
+			}
+			if !strings.Contains(log.Error, tt.errText) {
+				t.Errorf("error = %q, want it to mention %q", log.Error, tt.errText)
+			}
+			if !strings.Contains(output, tt.output) {
+				t.Errorf("output = %q, want it to contain %q", output, tt.output)
+			}
+			if _, ok := log.Metrics["connect_ms"]; !ok {
+				t.Errorf("metrics = %v, want connect_ms", log.Metrics)
+			}
+		})
+	}
+}
+
+func TestSocketChecksFail(t *testing.T) {
+	t.Chdir(t.TempDir())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := lis.Addr().String()
+	lis.Close()
+
+	// A UDP server that never answers.
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+
+	tests := map[string]config.Check{
+		"tcp closed port": {Type: config.TypeTCP, URL: closed},
+		"udp no reply":    {Type: config.TypeUDP, URL: silent.LocalAddr().String(), Send: "ping"},
+	}
+	for name, check := range tests {
+		t.Run(name, func(t *testing.T) {
+			check.Name = name
+			check.Timeout = "200ms"
+			if _, _, err := RunCheck(check); err == nil {
+				t.Error("check passed")
+			}
+		})
+	}
+}
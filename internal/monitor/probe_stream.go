@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+)
+
+// runStreamCheck reads an SSE or line-delimited stream until it ends, MaxEvents arrive or Timeout
+// passes, recording time-to-first-event and inter-event gaps. A stream without events, or without
+// an event containing Expect, fails the check.
+func runStreamCheck(check config.Check) (MonitorLog, string, error) {
+	log := MonitorLog{Method: check.Method}
+
+	auth, signer, err := credentials(check)
+	if err != nil {
+		return log, "", err
+	}
+
+	httpOpts, err := httpOptions(check)
+	if err != nil {
+		return log, "", err
+	}
+
+	body, contentType, err := bodyOptions(check).Load()
+	if err != nil {
+		return log, "", err
+	}
+	maxBody, err := maxBody(check)
+	if err != nil {
+		return log, "", err
+	}
+
+	var matched bool
+	var events []string
+	span := telemetry.StartCheck("pingify.monitor.stream", check.Method, check.URL)
+	result, err := requester.Stream(requester.Request{
+		URL:         check.URL,
+		Method:      check.Method,
+		Headers:     check.Headers,
+		Body:        body,
+		ContentType: contentType,
+		Timeout:     check.Timeout,
+		Header:      span.Header(),
+		Retry:       retryPolicy(check),
+		Auth:        auth,
+		Signer:      signer,
+		TLS:         tlsOptions(check),
+		Net:         netOptions(check),
+		HTTP:        httpOpts,
+		MaxBody:     maxBody,
+	}, requester.StreamOptions{MaxEvents: check.MaxEvents}, func(ev requester.Event) {
+		if check.Expect != "" && strings.Contains(ev.Data, check.Expect) {
+			matched = true
+		}
+		if len(events) < 20 {
+			events = append(events, ev.Data)
+		}
+	})
+	if result == nil {
+		span.End(nil, err)
+		return log, "", err
+	}
+	span.End(&requester.Response{StatusCode: result.StatusCode, Duration: result.Duration, Phases: result.Phases}, err)
+
+	if len(result.Attempts) > 1 {
+		log.Attempts = result.Attempts
+	}
+	log.StatusCode = result.StatusCode
+	log.StatusText = httpStatusText(result.StatusCode)
+	log.ExecutionTime = result.TimeToFirstEvent
+	log.Metrics = map[string]float64{
+		"events":     float64(result.Events),
+		"ttfe_ms":    ms(result.TimeToFirstEvent),
+		"max_gap_ms": ms(result.MaxGap),
+		"avg_gap_ms": ms(result.AvgGap),
+		"stream_ms":  ms(result.Duration),
+	}
+
+	switch {
+	case err != nil:
+	case result.StatusCode >= 400:
+		log.Error = fmt.Sprintf("stream returned HTTP %d", result.StatusCode)
+	case result.Events == 0:
+		log.Error = "stream produced no events"
+		log.ExecutionTime = result.Duration
+	case check.Expect != "" && !matched:
+		log.Error = fmt.Sprintf("no event contained %q", check.Expect)
+	}
+	return log, strings.Join(events, "\n"), err
+}
@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+	"github.com/Aditya251610/pingify/internal/tlsinfo"
+)
+
+// runTLSCheck inspects the served certificate chain; expiry within WarnDays, hostname mismatches
+// and weak protocols fail the check.
+func runTLSCheck(check config.Check) (MonitorLog, string, error) {
+	timeout, _ := time.ParseDuration(check.Timeout)
+	log := MonitorLog{Method: "TLS"}
+
+	// Only the trust roots and server name apply; the point of the check is to verify the chain.
+	opts := tlsinfo.Options{Timeout: timeout}
+	if check.TLS != nil {
+		conf, err := (&requester.TLSOptions{CAFile: check.TLS.CA}).Config()
+		if err != nil {
+			return log, "", err
+		}
+		opts.Roots = conf.RootCAs
+		opts.ServerName = check.TLS.ServerName
+	}
+
+	span := telemetry.StartCheck("pingify.monitor.tls", "TLS", check.URL)
+	info, err := tlsinfo.Inspect(check.URL, opts)
+	span.End(nil, err)
+	if err != nil {
+		return log, "", err
+	}
+
+	now := time.Now()
+	log.StatusText = info.Version
+	log.ExecutionTime = info.Handshake
+	log.Metrics = map[string]float64{"tls_days_left": float64(info.Chain[0].DaysLeft(now))}
+	if problems := info.Problems(check.WarnDays, now); len(problems) > 0 {
+		log.Error = strings.Join(problems, "; ")
+	}
+	return log, info.Summary(now), nil
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/collection"
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/environment"
+)
+
+// Step is the outcome of one request of a transaction check.
+type Step struct {
+	Name          string        `json:"name"`
+	Method        string        `json:"method"`
+	URL           string        `json:"url"`
+	StatusCode    int           `json:"status_code,omitempty"`
+	ExecutionTime time.Duration `json:"execution_time"`
+	Error         string        `json:"error,omitempty"`
+	Skipped       bool          `json:"skipped,omitempty"`
+}
+
+// runTransactionCheck runs the collection file at the check's URL with the check's environment,
+// both re-read on every run, and logs the time of each step. The first failing step fails the
+// check; teardown steps still run.
+func runTransactionCheck(check config.Check) (MonitorLog, string, error) {
+	log := MonitorLog{Method: "TRANSACTION"}
+	c, err := collection.Load(check.URL)
+	if err != nil {
+		return log, "", err
+	}
+	auth, signer, err := credentials(check)
+	if err != nil {
+		return log, "", err
+	}
+	httpOpts, err := httpOptions(check)
+	if err != nil {
+		return log, "", err
+	}
+	vars := environment.Vars{}
+	if check.Environment != "" {
+		env, err := environment.Load(check.Environment)
+		if err != nil {
+			return log, "", err
+		}
+		if vars, err = env.Vars(); err != nil {
+			return log, "", err
+		}
+	}
+
+	suite := collection.Run(c, collection.Options{
+		Auth:   auth,
+		Signer: signer,
+		TLS:    tlsOptions(check),
+		Net:    netOptions(check),
+		HTTP:   httpOpts,
+		Vars:   vars,
+	})
+
+	var lines []string
+	passed := 0
+	for _, r := range suite.Results {
+		step := Step{Name: r.Label(), Method: r.Method, URL: r.URL, StatusCode: r.StatusCode, ExecutionTime: r.Duration, Skipped: r.Skipped}
+		switch {
+		case r.Error != "":
+			step.Error = r.Error
+		case len(r.Failures) > 0:
+			step.Error = strings.Join(r.Failures, "; ")
+		}
+		log.Steps = append(log.Steps, step)
+		log.ExecutionTime += r.Duration
+
+		switch {
+		case r.Skipped:
+			lines = append(lines, fmt.Sprintf("⏭️  %s (skipped)", step.Name))
+		case step.Error != "":
+			lines = append(lines, fmt.Sprintf("❌ %s — HTTP %d in %s: %s", step.Name, r.StatusCode, r.Duration, step.Error))
+			if log.Error == "" {
+				log.Error = fmt.Sprintf("step %q failed: %s", step.Name, step.Error)
+			}
+		default:
+			lines = append(lines, fmt.Sprintf("✅ %s — HTTP %d in %s", step.Name, r.StatusCode, r.Duration))
+			passed++
+		}
+		if r.StatusCode != 0 {
+			log.StatusCode = r.StatusCode
+		}
+	}
+	log.StatusText = fmt.Sprintf("%d/%d steps passed", passed, len(suite.Results))
+	return log, strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/telemetry"
+	"github.com/Aditya251610/pingify/internal/wscall"
+)
+
+// runWSCheck measures the WebSocket handshake and one round trip: a ping/pong by default, or
+// sending Send and waiting for a message containing Expect. Unexpected closes fail the check.
+func runWSCheck(check config.Check) (MonitorLog, string, error) {
+	log := MonitorLog{Method: "WS"}
+	timeout := checkTimeout(check)
+
+	header, err := requester.ParseHeaders(check.Headers)
+	if err != nil {
+		return log, "", err
+	}
+
+	opts := wscall.Options{Timeout: timeout}
+	if opts.TLS, err = tlsConfig(check); err != nil {
+		return log, "", err
+	}
+	if n := netOptions(check); n != nil {
+		if opts.NetDial, err = n.Dialer(); err != nil {
+			return log, "", err
+		}
+		if opts.Proxy, err = n.ProxyFunc(); err != nil {
+			return log, "", err
+		}
+	}
+
+	span := telemetry.StartCheck("pingify.monitor.ws", log.Method, check.URL)
+	session, err := wscall.Dial(check.URL, header, opts)
+	if err != nil {
+		span.End(nil, err)
+		return log, "", err
+	}
+	defer session.Close()
+
+	var reply string
+	var roundTrip time.Duration
+	var exchangeErr error
+	if check.Send == "" {
+		roundTrip, exchangeErr = session.Ping(timeout)
+	} else if exchangeErr = session.Send(check.Send); exchangeErr == nil {
+		reply, roundTrip, exchangeErr = session.Expect(check.Expect, timeout)
+	}
+	if exchangeErr == nil {
+		exchangeErr = session.UnexpectedClose()
+	}
+	span.End(nil, exchangeErr)
+
+	log.StatusText = "Connected"
+	log.ExecutionTime = session.Handshake + roundTrip
+	log.Metrics = map[string]float64{
+		"handshake_ms": ms(session.Handshake),
+		"rtt_ms":       ms(roundTrip),
+	}
+	if exchangeErr != nil {
+		log.Error = exchangeErr.Error()
+	}
+	return log, reply, nil
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+
+	"github.com/Aditya251610/pingify/internal/secret"
 )
 
 type OpenAIRequest struct {
@@ -41,6 +43,8 @@ func GetSuggestionsFromLogs(logContent string, explain bool) string {
 	if len(logContent) == 0 {
 		return "❌ Log file is empty. No content to analyze."
 	}
+	// Logs are masked when written, but older ones may predate a secret being registered.
+	logContent = secret.Mask(logContent)
 
 	systemPrompt := `
 You are an expert API performance and architecture engineer embedded in a CLI tool.
@@ -7,9 +7,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/Aditya251610/pingify/internal/monitor"
+	"github.com/Aditya251610/pingify/internal/secret"
 )
 
 type ReportSummary struct {
@@ -19,6 +23,12 @@ type ReportSummary struct {
 	SuccessCount     int           `json:"success_count"`
 	ExceededCount    int           `json:"exceeded_count"`
 	AvgExecutionTime time.Duration `json:"avg_execution_time"`
+
+	// RetriedCount checks needed more than one attempt; RecoveredCount of them passed in the end.
+	RetriedCount   int     `json:"retried_count"`
+	RecoveredCount int     `json:"recovered_count"`
+	TotalRetries   int     `json:"total_retries"`
+	RetryRate      float64 `json:"retry_rate"`
 }
 
 type MonitorLog struct {
@@ -28,9 +38,10 @@ type MonitorLog struct {
 	ExecutionTime time.Duration `json:"execution_time"`
 	Success       bool          `json:"success"`
 	Exceeded      bool          `json:"exceeded"`
+	Attempts      []struct{}    `json:"attempts,omitempty"`
 }
 
-// GenerateReport generates a summary report for the given URL
+// GenerateReport generates a summary report for the given log key: a URL, check://name or heartbeat://name
 func GenerateReport(rawURL string) (*ReportSummary, error) {
 	logs, err := ReadLogsForURL(rawURL)
 	if err != nil {
@@ -41,7 +52,7 @@ func GenerateReport(rawURL string) (*ReportSummary, error) {
 	}
 
 	var total time.Duration
-	var success, exceeded int
+	var success, exceeded, retried, recovered, retries int
 
 	for _, log := range logs {
 		total += log.ExecutionTime
@@ -51,6 +62,13 @@ func GenerateReport(rawURL string) (*ReportSummary, error) {
 		if log.Exceeded {
 			exceeded++
 		}
+		if len(log.Attempts) > 1 {
+			retried++
+			retries += len(log.Attempts) - 1
+			if log.Success {
+				recovered++
+			}
+		}
 	}
 
 	avg := total / time.Duration(len(logs))
@@ -62,6 +80,10 @@ func GenerateReport(rawURL string) (*ReportSummary, error) {
 		SuccessCount:     success,
 		ExceededCount:    exceeded,
 		AvgExecutionTime: avg,
+		RetriedCount:     retried,
+		RecoveredCount:   recovered,
+		TotalRetries:     retries,
+		RetryRate:        float64(retried) / float64(len(logs)),
 	}
 
 	saveReportToFile(rawURL, summary)
@@ -69,24 +91,29 @@ func GenerateReport(rawURL string) (*ReportSummary, error) {
 	return summary, nil
 }
 
-// ReadLogsForURL reads logs from the corresponding monitor log file for a given URL
+// ReadLogsForURL reads logs from the corresponding monitor log file for a given log key.
+// Monitor only appends whole lines or replaces the file atomically, so this needs no locking:
+// a last entry cut short by an append in progress is skipped.
 func ReadLogsForURL(rawURL string) ([]MonitorLog, error) {
-	hash := sha1.Sum([]byte(rawURL))
-	filename := "monitor_" + hex.EncodeToString(hash[:]) + ".json"
-	path := filepath.Join("logs", filename)
-
-	data, err := os.ReadFile(path)
+	f, err := os.Open(monitor.LogPath(rawURL))
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
 
 	var logs []MonitorLog
-	err = json.Unmarshal(data, &logs)
-	if err != nil {
-		return nil, err
+	dec := json.NewDecoder(f)
+	for {
+		var log MonitorLog
+		err := dec.Decode(&log)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return logs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
 	}
-
-	return logs, nil
 }
 
 // saveReportToFile creates a report directory and writes a text summary for the given URL
@@ -99,7 +126,7 @@ func saveReportToFile(rawURL string, report *ReportSummary) {
 
 	file, err := os.Create(path)
 	if err != nil {
-		fmt.Println("❌ Failed to write report file:", err)
+		fmt.Fprintln(secret.Stdout, "❌ Failed to write report file:", err)
 		return
 	}
 	defer file.Close()
@@ -112,6 +139,7 @@ func saveReportToFile(rawURL string, report *ReportSummary) {
 ✅ Success:     %d
 ⚠️ Exceeded:    %d
 ⏱️ Avg Time:    %s
+🔁 Retried:     %d (%.1f%%, %d passed after retrying, %d retries in total)
 `,
 		hash,
 		report.StartTime.Format("2006-01-02 15:04:05"),
@@ -120,6 +148,10 @@ func saveReportToFile(rawURL string, report *ReportSummary) {
 		report.SuccessCount,
 		report.ExceededCount,
 		report.AvgExecutionTime,
+		report.RetriedCount,
+		report.RetryRate*100,
+		report.RecoveredCount,
+		report.TotalRetries,
 	)
 
 	_, _ = file.WriteString(reportContent)
@@ -0,0 +1,314 @@
+package requester
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+// Authenticator adds credentials to an outgoing request. It is applied to every attempt,
+// so implementations can refresh what they send.
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// AuthOptions describes credentials for NewAuthenticator. Secret values may be given literally,
+// as env:NAME or as file:path so they stay out of shell history and config files (see ResolveSecret).
+type AuthOptions struct {
+	Type string // basic, bearer, apikey or oauth2; empty picks one from the fields that are set
+
+	Username string
+	Password string
+
+	Token string
+
+	// APIKeyName is the header or query parameter the key is sent in; APIKeyIn is "header" (default) or "query".
+	APIKeyName  string
+	APIKeyValue string
+	APIKeyIn    string
+
+	// OAuth2 client-credentials, or refresh-token when RefreshToken is set.
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RefreshToken string
+}
+
+// Auth types accepted by AuthOptions.Type.
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthAPIKey = "apikey"
+	AuthOAuth2 = "oauth2"
+)
+
+func (o AuthOptions) kind() string {
+	switch {
+	case o.Type != "":
+		return strings.ToLower(o.Type)
+	case o.TokenURL != "":
+		return AuthOAuth2
+	case o.APIKeyName != "":
+		return AuthAPIKey
+	case o.Token != "":
+		return AuthBearer
+	case o.Username != "":
+		return AuthBasic
+	}
+	return ""
+}
+
+// NewAuthenticator resolves the secrets in o and returns the matching Authenticator,
+// or nil when no credentials are configured.
+func NewAuthenticator(o AuthOptions) (Authenticator, error) {
+	switch o.kind() {
+	case "":
+		return nil, nil
+	case AuthBasic:
+		if o.Username == "" {
+			return nil, errors.New("basic auth needs a username")
+		}
+		password, err := ResolveSecret(o.Password)
+		if err != nil {
+			return nil, err
+		}
+		return basicAuth{username: o.Username, password: password}, nil
+	case AuthBearer:
+		token, err := ResolveSecret(o.Token)
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return nil, errors.New("bearer auth needs a token")
+		}
+		return bearerAuth{token: token}, nil
+	case AuthAPIKey:
+		value, err := ResolveSecret(o.APIKeyValue)
+		if err != nil {
+			return nil, err
+		}
+		in := strings.ToLower(o.APIKeyIn)
+		if o.APIKeyName == "" || value == "" || (in != "" && in != "header" && in != "query") {
+			return nil, errors.New(`api key auth needs a name, a value and "header" or "query" placement`)
+		}
+		return apiKeyAuth{name: o.APIKeyName, value: value, query: in == "query"}, nil
+	case AuthOAuth2:
+		return newOAuth2(o)
+	}
+	return nil, fmt.Errorf("unknown auth type %q (supported: basic, bearer, apikey, oauth2)", o.Type)
+}
+
+// ResolveSecret returns the value of an env:NAME or file:path reference, or s itself.
+// File contents are trimmed of surrounding whitespace. Values read from references are
+// registered with package secret, so they are masked in output and logs.
+func ResolveSecret(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, "env:"):
+		name := strings.TrimPrefix(s, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		secret.Register(v)
+		return v, nil
+	case strings.HasPrefix(s, "file:"):
+		data, err := os.ReadFile(strings.TrimPrefix(s, "file:"))
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret: %w", err)
+		}
+		v := strings.TrimSpace(string(data))
+		secret.Register(v)
+		return v, nil
+	}
+	return s, nil
+}
+
+type basicAuth struct{ username, password string }
+
+func (a basicAuth) Apply(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type bearerAuth struct{ token string }
+
+func (a bearerAuth) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+type apiKeyAuth struct {
+	name, value string
+	query       bool
+}
+
+func (a apiKeyAuth) Apply(req *http.Request) error {
+	if a.query {
+		q := req.URL.Query()
+		q.Set(a.name, a.value)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+	req.Header.Set(a.name, a.value)
+	return nil
+}
+
+// tokenSkew refreshes OAuth2 tokens this long before they expire so they don't lapse mid-request.
+const tokenSkew = 30 * time.Second
+
+// oauth2Auth fetches access tokens from a token endpoint. Tokens are cached per endpoint, client
+// and scope set for the life of the process, so checks in a long monitor run share one token
+// and only refresh it when it is about to expire or the server rejects it.
+type oauth2Auth struct {
+	opts  AuthOptions
+	entry *tokenEntry
+}
+
+type tokenEntry struct {
+	mu           sync.Mutex
+	accessToken  string
+	tokenType    string
+	expiry       time.Time
+	refreshToken string
+}
+
+var tokenCache sync.Map // cache key -> *tokenEntry
+
+func newOAuth2(o AuthOptions) (*oauth2Auth, error) {
+	if o.TokenURL == "" || o.ClientID == "" {
+		return nil, errors.New("oauth2 auth needs a token URL and a client ID")
+	}
+	var err error
+	if o.ClientSecret, err = ResolveSecret(o.ClientSecret); err != nil {
+		return nil, err
+	}
+	if o.RefreshToken, err = ResolveSecret(o.RefreshToken); err != nil {
+		return nil, err
+	}
+
+	key := strings.Join([]string{o.TokenURL, o.ClientID, strings.Join(o.Scopes, " "), o.RefreshToken}, "\x00")
+	entry, _ := tokenCache.LoadOrStore(key, &tokenEntry{refreshToken: o.RefreshToken})
+	return &oauth2Auth{opts: o, entry: entry.(*tokenEntry)}, nil
+}
+
+// transportKey is the context key under which Request.build passes its transport to
+// authenticators that make requests of their own.
+type transportKey struct{}
+
+func (a *oauth2Auth) Apply(req *http.Request) error {
+	transport, _ := req.Context().Value(transportKey{}).(http.RoundTripper)
+	token, tokenType, err := a.token(transport)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", tokenType+" "+token)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one.
+func (a *oauth2Auth) Invalidate() {
+	a.entry.mu.Lock()
+	defer a.entry.mu.Unlock()
+	a.entry.accessToken = ""
+}
+
+func (a *oauth2Auth) token(transport http.RoundTripper) (string, string, error) {
+	e := a.entry
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.accessToken != "" && (e.expiry.IsZero() || time.Until(e.expiry) > tokenSkew) {
+		return e.accessToken, e.tokenType, nil
+	}
+
+	tok, err := a.fetch(transport, e.refreshToken)
+	if err != nil && e.refreshToken != "" && a.opts.RefreshToken == "" {
+		// A refresh token handed out with client credentials may expire; start over without it.
+		e.refreshToken = ""
+		tok, err = a.fetch(transport, "")
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	// Fetched tokens show up in -v dumps, HAR files and AI payloads like any other credential.
+	secret.Register(tok.AccessToken, tok.RefreshToken)
+	e.accessToken = tok.AccessToken
+	e.tokenType = "Bearer"
+	if tok.TokenType != "" && !strings.EqualFold(tok.TokenType, "bearer") {
+		e.tokenType = tok.TokenType
+	}
+	e.expiry = time.Time{}
+	if tok.ExpiresIn > 0 {
+		e.expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	if tok.RefreshToken != "" {
+		e.refreshToken = tok.RefreshToken
+	}
+	return e.accessToken, e.tokenType, nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// fetch requests a token with the refresh-token grant, or client credentials when refreshToken is
+// empty. A nil transport uses Go's default.
+func (a *oauth2Auth) fetch(transport http.RoundTripper, refreshToken string) (*tokenResponse, error) {
+	form := url.Values{}
+	if refreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", refreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if len(a.opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.opts.Scopes, " "))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, a.opts.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid token URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.opts.ClientID), url.QueryEscape(a.opts.ClientSecret))
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second, Transport: transport}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tok tokenResponse
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return nil, fmt.Errorf("token endpoint returned HTTP %d with a non-JSON body", resp.StatusCode)
+	}
+	if tok.Error != "" || resp.StatusCode >= 400 || tok.AccessToken == "" {
+		msg := strings.TrimSpace(tok.Error + " " + tok.ErrorDescription)
+		if msg == "" {
+			msg = "no access_token in response"
+		}
+		return nil, fmt.Errorf("token endpoint returned HTTP %d: %s", resp.StatusCode, msg)
+	}
+	return &tok, nil
+}
@@ -0,0 +1,289 @@
+package requester
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+// tokenServer is a fake OAuth2 token endpoint handing out tok-1, tok-2, ... and recording the grants it saw.
+type tokenServer struct {
+	*httptest.Server
+	expiresIn    int
+	refreshToken string
+	fail         bool
+
+	mu     sync.Mutex
+	grants []string
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{expiresIn: 3600}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		r.ParseForm()
+		ts.mu.Lock()
+		ts.grants = append(ts.grants, r.Form.Get("grant_type"))
+		n := len(ts.grants)
+		ts.mu.Unlock()
+		if ts.fail {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "expired"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  fmt.Sprintf("tok-%d", n),
+			"token_type":    "bearer",
+			"expires_in":    ts.expiresIn,
+			"refresh_token": ts.refreshToken,
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *tokenServer) fetched() []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]string(nil), ts.grants...)
+}
+
+// apiServer accepts only the listed bearer tokens and records the ones it was sent.
+func apiServer(t *testing.T, accept ...string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		mu.Lock()
+		seen = append(seen, auth)
+		mu.Unlock()
+		for _, tok := range accept {
+			if auth == "Bearer "+tok {
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func oauth2Options(tokenURL string) AuthOptions {
+	return AuthOptions{TokenURL: tokenURL, ClientID: "client", ClientSecret: "s3cret", Scopes: []string{"read"}}
+}
+
+func TestOAuth2(t *testing.T) {
+	tests := []struct {
+		name         string
+		expiresIn    int
+		refreshToken string
+		accept       []string
+		requests     int
+		wantGrants   []string
+		wantSent     []string
+	}{
+		{
+			name:       "token cached across requests",
+			expiresIn:  3600,
+			accept:     []string{"tok-1"},
+			requests:   3,
+			wantGrants: []string{"client_credentials"},
+			wantSent:   []string{"Bearer tok-1", "Bearer tok-1", "Bearer tok-1"},
+		},
+		{
+			name:       "token about to expire is refetched",
+			expiresIn:  10,
+			accept:     []string{"tok-1", "tok-2"},
+			requests:   2,
+			wantGrants: []string{"client_credentials", "client_credentials"},
+			wantSent:   []string{"Bearer tok-1", "Bearer tok-2"},
+		},
+		{
+			name:         "refresh token used once issued",
+			expiresIn:    10,
+			refreshToken: "refresh-me",
+			accept:       []string{"tok-1", "tok-2"},
+			requests:     2,
+			wantGrants:   []string{"client_credentials", "refresh_token"},
+			wantSent:     []string{"Bearer tok-1", "Bearer tok-2"},
+		},
+		{
+			name:       "rejected token refreshed once",
+			expiresIn:  3600,
+			accept:     []string{"tok-2"},
+			requests:   1,
+			wantGrants: []string{"client_credentials", "client_credentials"},
+			wantSent:   []string{"Bearer tok-1", "Bearer tok-2"},
+		},
+		{
+			name:       "second rejection returned",
+			expiresIn:  3600,
+			requests:   1,
+			wantGrants: []string{"client_credentials", "client_credentials"},
+			wantSent:   []string{"Bearer tok-1", "Bearer tok-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTokenServer(t)
+			ts.expiresIn = tt.expiresIn
+			ts.refreshToken = tt.refreshToken
+			api, sent := apiServer(t, tt.accept...)
+
+			var last *Response
+			for range tt.requests {
+				// A fresh authenticator per request, as monitor does per check run; the cache is shared.
+				auth, err := NewAuthenticator(oauth2Options(ts.URL))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if last, err = Do(Request{URL: api.URL, Method: http.MethodGet, Auth: auth}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := ts.fetched(); strings.Join(got, ",") != strings.Join(tt.wantGrants, ",") {
+				t.Errorf("token grants = %q, want %q", got, tt.wantGrants)
+			}
+			if got := sent(); strings.Join(got, ",") != strings.Join(tt.wantSent, ",") {
+				t.Errorf("sent %q, want %q", got, tt.wantSent)
+			}
+			wantStatus := http.StatusOK
+			if len(tt.accept) == 0 {
+				wantStatus = http.StatusUnauthorized
+			}
+			if last.StatusCode != wantStatus {
+				t.Errorf("status = %d, want %d", last.StatusCode, wantStatus)
+			}
+		})
+	}
+}
+
+func TestOAuth2TokenMasked(t *testing.T) {
+	ts := newTokenServer(t)
+	ts.refreshToken = "refresh-me-too"
+	api, _ := apiServer(t, "tok-1")
+	auth, err := NewAuthenticator(oauth2Options(ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Do(Request{URL: api.URL, Method: http.MethodGet, Auth: auth}); err != nil {
+		t.Fatal(err)
+	}
+	if got := secret.Mask("Authorization: Bearer tok-1, refresh refresh-me-too"); got != "Authorization: Bearer ****, refresh ****" {
+		t.Errorf("fetched tokens are not masked: %q", got)
+	}
+}
+
+func TestOAuth2UsesRequestTLS(t *testing.T) {
+	// Both servers present httptest's certificate, which only the --cacert bundle trusts.
+	idp := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "internal-tok", "expires_in": 3600})
+	}))
+	defer idp.Close()
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer internal-tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer api.Close()
+
+	auth, err := NewAuthenticator(oauth2Options(idp.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := Do(Request{URL: api.URL, Method: http.MethodGet, Auth: auth, TLS: &TLSOptions{CAFile: writeCA(t, api)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestOAuth2Errors(t *testing.T) {
+	ts := newTokenServer(t)
+	ts.fail = true
+	api, _ := apiServer(t)
+
+	tests := []struct {
+		name string
+		opts AuthOptions
+		want string
+	}{
+		{"grant rejected", oauth2Options(ts.URL), "invalid_grant expired"},
+		{"bad client", AuthOptions{TokenURL: ts.URL, ClientID: "client", ClientSecret: "wrong"}, "HTTP 401: invalid_client"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := NewAuthenticator(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = Do(Request{URL: api.URL, Method: http.MethodGet, Auth: auth})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	t.Setenv("PINGIFY_TEST_TOKEN", "from-env")
+	tests := []struct {
+		name    string
+		opts    AuthOptions
+		header  string
+		want    string
+		query   string
+		wantErr bool
+	}{
+		{"none", AuthOptions{}, "Authorization", "", "", false},
+		{"basic", AuthOptions{Username: "user", Password: "pass"}, "Authorization", "Basic dXNlcjpwYXNz", "", false},
+		{"bearer", AuthOptions{Token: "abc"}, "Authorization", "Bearer abc", "", false},
+		{"bearer from env", AuthOptions{Token: "env:PINGIFY_TEST_TOKEN"}, "Authorization", "Bearer from-env", "", false},
+		{"api key header", AuthOptions{APIKeyName: "X-Api-Key", APIKeyValue: "k"}, "X-Api-Key", "k", "", false},
+		{"api key query", AuthOptions{APIKeyName: "key", APIKeyValue: "k", APIKeyIn: "query"}, "", "", "key=k", false},
+		{"missing env", AuthOptions{Token: "env:PINGIFY_TEST_UNSET"}, "", "", "", true},
+		{"bad placement", AuthOptions{APIKeyName: "key", APIKeyValue: "k", APIKeyIn: "cookie"}, "", "", "", true},
+		{"oauth2 without client", AuthOptions{TokenURL: "http://127.0.0.1/token"}, "", "", "", true},
+		{"unknown type", AuthOptions{Type: "digest"}, "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := NewAuthenticator(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil || auth == nil {
+				return
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			if err := auth.Apply(req); err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" && req.Header.Get(tt.header) != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, req.Header.Get(tt.header), tt.want)
+			}
+			if req.URL.RawQuery != tt.query {
+				t.Errorf("query = %q, want %q", req.URL.RawQuery, tt.query)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package requester
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// BodyOptions describes where a request body comes from. At most one of Data, Binary and Form
+// may be set. Values starting with @ name a file to read, and @- reads stdin.
+type BodyOptions struct {
+	// Data is a JSON body, inline or @file.
+	Data string
+	// Binary is sent byte for byte, typed from the file extension unless a Content-Type header is given.
+	Binary string
+	// Form lists key=value and key=@path[;type=mime] fields, sent as multipart/form-data.
+	Form []string
+	// URLEncoded sends Form as application/x-www-form-urlencoded instead; files become values.
+	URLEncoded bool
+}
+
+// IsZero reports whether no body was given.
+func (o BodyOptions) IsZero() bool {
+	return o.Data == "" && o.Binary == "" && len(o.Form) == 0
+}
+
+// Load reads the body and returns it with its Content-Type. Sources are read on every call, so
+// monitors pick up changes to body files between runs.
+func (o BodyOptions) Load() (body, contentType string, err error) {
+	set := 0
+	for _, given := range []bool{o.Data != "", o.Binary != "", len(o.Form) > 0} {
+		if given {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", "", errors.New("only one of body, binary data and form fields can be given")
+	}
+
+	switch {
+	case o.Data != "":
+		body, err = ReadData(o.Data)
+		return body, "application/json", err
+	case o.Binary != "":
+		body, err = ReadData(o.Binary)
+		contentType = "application/octet-stream"
+		if strings.HasPrefix(o.Binary, "@") {
+			if t := mime.TypeByExtension(filepath.Ext(o.Binary)); t != "" {
+				contentType = t
+			}
+		}
+		return body, contentType, err
+	case o.URLEncoded:
+		return urlencodedForm(o.Form)
+	case len(o.Form) > 0:
+		return multipartForm(o.Form)
+	}
+	return "", "", nil
+}
+
+var (
+	stdinOnce sync.Once
+	stdinData string
+	stdinErr  error
+)
+
+// ReadData resolves an inline value, @file or @- (stdin). Stdin is read once and reused, so a
+// monitor reading its body from a pipe sends the same body on every run.
+func ReadData(s string) (string, error) {
+	if !strings.HasPrefix(s, "@") {
+		return s, nil
+	}
+	path := s[1:]
+	if path == "-" {
+		stdinOnce.Do(func() {
+			data, err := io.ReadAll(os.Stdin)
+			stdinData, stdinErr = string(data), err
+		})
+		if stdinErr != nil {
+			return "", fmt.Errorf("failed to read body from stdin: %w", stdinErr)
+		}
+		return stdinData, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body file: %w", err)
+	}
+	return string(data), nil
+}
+
+// formField is one parsed --form entry.
+type formField struct {
+	name, value string
+	file        string // set for key=@path
+	contentType string // from ;type=
+}
+
+func parseFormField(raw string) (formField, error) {
+	name, value, ok := strings.Cut(raw, "=")
+	if !ok || name == "" {
+		return formField{}, fmt.Errorf("form field %q must be key=value or key=@path", raw)
+	}
+	f := formField{name: name, value: value}
+	if strings.HasPrefix(value, "@") {
+		f.file = value[1:]
+		if path, t, ok := strings.Cut(f.file, ";type="); ok {
+			f.file, f.contentType = path, t
+		}
+	}
+	return f, nil
+}
+
+func urlencodedForm(fields []string) (string, string, error) {
+	values := url.Values{}
+	for _, raw := range fields {
+		f, err := parseFormField(raw)
+		if err != nil {
+			return "", "", err
+		}
+		if f.file != "" {
+			data, err := ReadData("@" + f.file)
+			if err != nil {
+				return "", "", err
+			}
+			f.value = data
+		}
+		values.Add(f.name, f.value)
+	}
+	return values.Encode(), "application/x-www-form-urlencoded", nil
+}
+
+// quoteEscaper escapes Content-Disposition parameters the way mime/multipart does.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func multipartForm(fields []string) (string, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, raw := range fields {
+		f, err := parseFormField(raw)
+		if err != nil {
+			return "", "", err
+		}
+		if f.file == "" {
+			if err := w.WriteField(f.name, f.value); err != nil {
+				return "", "", err
+			}
+			continue
+		}
+
+		data, err := ReadData("@" + f.file)
+		if err != nil {
+			return "", "", err
+		}
+		contentType := f.contentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(f.file))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		filename := filepath.Base(f.file)
+		if f.file == "-" {
+			filename = "stdin"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(f.name), quoteEscaper.Replace(filename)))
+		h.Set("Content-Type", contentType)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := io.WriteString(part, data); err != nil {
+			return "", "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", "", err
+	}
+	return buf.String(), w.FormDataContentType(), nil
+}
@@ -2,14 +2,104 @@ package requester
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
+// Request describes a single HTTP call made by Do.
+type Request struct {
+	URL     string
+	Method  string
+	Headers string
+	Body    string
+	Timeout string
+	Pretty  bool
+
+	// ContentType is sent unless Headers override it; empty means application/json.
+	ContentType string
+
+	// Header holds extra headers set on the outgoing request (e.g. traceparent).
+	Header http.Header
+
+	// Retry makes Do retry transient failures; the zero value makes a single attempt.
+	Retry RetryPolicy
+
+	// Auth, when set, adds credentials to every attempt.
+	Auth Authenticator
+
+	// Signer, when set, signs every attempt after headers and auth are applied.
+	Signer Signer
+
+	// TLS, when set, replaces Go's default TLS client settings.
+	TLS *TLSOptions
+
+	// Net, when set, routes connections through proxies, pinned addresses or a Unix socket.
+	Net *NetOptions
+
+	// HTTP, when set, controls redirects, cookies and the protocol version.
+	HTTP *HTTPOptions
+
+	// MaxBody stops reading the decoded body after this many bytes; zero reads it all.
+	MaxBody int64
+
+	// Output, when set, receives the decoded body instead of Response.Body. Files are truncated
+	// before each attempt so retries don't leave partial bodies behind.
+	Output io.Writer
+
+	// Progress is called as body bytes arrive with the bytes received and the Content-Length
+	// (-1 when unknown), both counted before decompression.
+	Progress func(received, total int64)
+}
+
+// Response is the outcome of a request made by Do.
+type Response struct {
+	Body       string
+	StatusCode int
+	Header     http.Header
+	Duration   time.Duration
+	Phases     []Phase
+
+	// Attempts lists every try Do made; Duration and Phases describe the last one.
+	Attempts []Attempt
+
+	// TLS is the negotiated connection state of https responses.
+	TLS *tls.ConnectionState
+
+	// Proto is the negotiated protocol, e.g. "HTTP/2.0".
+	Proto string
+
+	// Redirects lists the hops followed before the final response.
+	Redirects []Hop
+
+	// Size is the decoded body size and WireSize the bytes received for it, which differ when
+	// Encoding (e.g. "gzip") was decoded.
+	Size     int64
+	WireSize int64
+	Encoding string
+
+	// Charset is set when the body was converted from it to UTF-8.
+	Charset string
+
+	// Binary is set for bodies that should not be printed; Truncated when MaxBody cut the body short.
+	Binary    bool
+	Truncated bool
+
+	// Request is the last request sent, with the headers added by auth and signing, and Started
+	// the time the last attempt began. Both are kept for HAR export.
+	Request *http.Request
+	Started time.Time
+}
+
 func isValidURL(rawURL string) bool {
 	parsedURL, err := url.ParseRequestURI(rawURL)
 	if err != nil {
@@ -20,40 +110,330 @@ func isValidURL(rawURL string) bool {
 }
 
 func ExecuteRequest(rawURL, method, headers, body, timeout string, pretty bool) (string, int, time.Duration, error) {
-	if rawURL == "" || !isValidURL(rawURL) {
-		return "", 0, 0, errors.New("URL is required") // ✅ now returns 4 values
+	resp, err := Do(Request{
+		URL:     rawURL,
+		Method:  method,
+		Headers: headers,
+		Body:    body,
+		Timeout: timeout,
+		Pretty:  pretty,
+	})
+	if resp == nil {
+		return "", 0, 0, err
 	}
+	return resp.Body, resp.StatusCode, resp.Duration, err
+}
 
-	if method == "" || method == "GET" {
-		method = "GET"
+// Do executes the request, retrying according to r.Retry, and records the httptrace phases of the
+// final attempt. Timeout applies to each attempt. On transport failures a partial Response carrying
+// the duration, phases and attempts is returned with the error.
+func Do(r Request) (*Response, error) {
+	policy := r.Retry.withDefaults()
+	var attempts []Attempt
+	reauthed := false
+	for n := 1; ; n++ {
+		resp, err := r.do()
+		if resp == nil {
+			return nil, err
+		}
+
+		// A rejected cached token is dropped and the request repeated once with a fresh one.
+		if inv, ok := r.Auth.(interface{ Invalidate() }); ok && resp.StatusCode == http.StatusUnauthorized && !reauthed {
+			inv.Invalidate()
+			reauthed = true
+			attempts = append(attempts, Attempt{Number: n, StatusCode: resp.StatusCode, Duration: resp.Duration, Retry: "token rejected"})
+			continue
+		}
+
+		attempt := Attempt{Number: n, StatusCode: resp.StatusCode, Duration: resp.Duration}
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		if n < policy.MaxAttempts {
+			if reason := policy.retryReason(resp, err); reason != "" {
+				if wait, ok := policy.delay(n, resp.Header); ok {
+					attempt.Retry = reason
+					attempt.Wait = wait
+					attempts = append(attempts, attempt)
+					time.Sleep(wait)
+					continue
+				}
+			}
+		}
+
+		resp.Attempts = append(attempts, attempt)
+		return resp, err
 	}
+}
 
-	client := &http.Client{}
-	var requestBody io.Reader
-	if body != "" {
-		requestBody = bytes.NewBufferString(body)
+// do makes a single attempt.
+func (r Request) do() (*Response, error) {
+	ctx := context.Background()
+	if timeout, err := time.ParseDuration(r.Timeout); err == nil && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	req, err := http.NewRequest(method, rawURL, requestBody)
+	req, rec, err := r.build(ctx)
 	if err != nil {
-		return "", 0, 0, fmt.Errorf("failed to create request: %w", err) // ✅ 4 values
+		return nil, err
+	}
+	client, redirects, err := r.client()
+	if err != nil {
+		return nil, err
 	}
-
-	req.Header.Set("Content-Type", "application/json")
 
 	start := time.Now()
+	redirects.last = start
+	redirects.onHop = rec.reset
 	resp, err := client.Do(req)
-	duration := time.Since(start)
 	if err != nil {
-		return "", 0, duration, fmt.Errorf("failed to execute request: %w", err) // ✅ 4 values
+		duration := time.Since(start)
+		return &Response{Duration: duration, Phases: rec.finish(start, time.Now()), Redirects: redirects.hops, Request: req, Started: start}, fmt.Errorf("failed to execute request: %w", err)
 	}
 
 	defer resp.Body.Close()
+	if r.HTTP != nil && r.HTTP.Jar != nil {
+		if err := r.HTTP.Jar.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save cookies: %w", err)
+		}
+	}
+
+	result := &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		TLS:        resp.TLS,
+		Proto:      resp.Proto,
+		Redirects:  redirects.hops,
+		Request:    resp.Request,
+		Started:    start,
+	}
+	err = r.readBody(resp, result)
+	result.Duration = time.Since(start)
+	result.Phases = rec.finish(start, time.Now())
+	if err != nil {
+		return result, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return result, nil
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
+// readBody decodes the response body into result, or into r.Output, honouring r.MaxBody.
+func (r Request) readBody(resp *http.Response, result *Response) error {
+	wire := &countingReader{r: resp.Body, total: resp.ContentLength, progress: r.Progress}
+	defer func() { result.WireSize = wire.n }()
+
+	result.Encoding = resp.Header.Get("Content-Encoding")
+	if strings.EqualFold(result.Encoding, "identity") {
+		result.Encoding = ""
+	}
+	encoding := result.Encoding
+	if resp.Request != nil && resp.Request.Method == http.MethodHead ||
+		resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		encoding = "" // no body to decode
+	}
+	decoded, err := decompress(encoding, wire)
 	if err != nil {
-		return "", 0, duration, fmt.Errorf("failed to read response body: %w", err) // ✅ 4 values
+		return err
+	}
+	defer decoded.Close()
+	var body io.Reader = decoded
+	if r.MaxBody > 0 {
+		body = io.LimitReader(body, r.MaxBody+1)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if r.Output != nil {
+		if f, ok := r.Output.(interface {
+			Truncate(int64) error
+			Seek(int64, int) (int64, error)
+		}); ok {
+			if err := f.Truncate(0); err != nil {
+				return err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		out := &limitWriter{w: r.Output, limit: r.MaxBody}
+		_, err := io.Copy(out, body)
+		result.Size = out.written
+		result.Truncated = out.dropped
+		return err
 	}
 
-	return string(responseBody), resp.StatusCode, duration, nil
+	data, err := io.ReadAll(body)
+	if r.MaxBody > 0 && int64(len(data)) > r.MaxBody {
+		data = data[:r.MaxBody]
+		result.Truncated = true
+	}
+	result.Size = int64(len(data))
+	data, result.Charset = toUTF8(contentType, data)
+	result.Binary = IsBinary(contentType, data)
+	if result.Truncated && !result.Binary {
+		data = trimPartialRune(data, result.Charset != "")
+	}
+	result.Body = string(data)
+	return err
+}
+
+// client returns the HTTP client for the request's transport options, along with the recorder
+// for the redirects it follows.
+func (r Request) client() (*http.Client, *redirectRecorder, error) {
+	redirects := &redirectRecorder{}
+	client := &http.Client{CheckRedirect: r.HTTP.checkRedirect(redirects)}
+	if r.HTTP != nil && r.HTTP.Jar != nil {
+		client.Jar = r.HTTP.Jar
+	}
+	transport, err := r.transport()
+	if err != nil {
+		return nil, nil, err
+	}
+	if transport != nil {
+		client.Transport = transport
+	}
+	return client, redirects, nil
+}
+
+// transports caches one transport per set of TLS, network and protocol options, so retries, checks
+// run on an interval and the OAuth2 token request share connections instead of each leaving an
+// idle one behind.
+var transports = struct {
+	sync.Mutex
+	m map[string]*http.Transport
+}{m: map[string]*http.Transport{}}
+
+// transport returns the transport for the request's TLS, network and protocol options, or nil when
+// they leave Go's defaults alone.
+func (r Request) transport() (*http.Transport, error) {
+	protocols, err := r.HTTP.protocols()
+	if err != nil {
+		return nil, err
+	}
+	if r.TLS.IsZero() && r.Net.IsZero() && protocols == nil {
+		return nil, nil
+	}
+
+	key := struct {
+		TLS     *TLSOptions
+		Net     *NetOptions
+		Version string
+	}{r.TLS, r.Net, ""}
+	if r.HTTP != nil {
+		key.Version = r.HTTP.Version
+	}
+	id, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	transports.Lock()
+	defer transports.Unlock()
+	if t, ok := transports.m[string(id)]; ok {
+		return t, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Protocols = protocols
+	if !r.TLS.IsZero() {
+		tlsConfig, err := r.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	if !r.Net.IsZero() {
+		dial, err := r.Net.Dialer()
+		if err != nil {
+			return nil, err
+		}
+		proxy, err := r.Net.ProxyFunc()
+		if err != nil {
+			return nil, err
+		}
+		transport.DialContext = dial
+		transport.Proxy = proxy
+	}
+	transports.m[string(id)] = transport
+	return transport, nil
+}
+
+// build validates the request and turns it into an *http.Request that records httptrace phases.
+func (r Request) build(ctx context.Context) (*http.Request, *phaseRecorder, error) {
+	if r.URL == "" || !isValidURL(r.URL) {
+		return nil, nil, errors.New("URL is required")
+	}
+
+	method := r.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	var requestBody io.Reader
+	if r.Body != "" {
+		requestBody = bytes.NewBufferString(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.URL, requestBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	contentType := r.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept-Encoding", AcceptEncoding)
+	headers, err := ParseHeaders(r.Headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+	for key, values := range r.Header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	if r.Auth != nil {
+		// OAuth2 fetches its token through the same TLS, proxy and network settings as the request.
+		transport, err := r.transport()
+		if err != nil {
+			return nil, nil, err
+		}
+		if transport != nil {
+			req = req.WithContext(context.WithValue(req.Context(), transportKey{}, transport))
+		}
+		if err := r.Auth.Apply(req); err != nil {
+			return nil, nil, fmt.Errorf("failed to apply auth: %w", err)
+		}
+	}
+	if r.Signer != nil {
+		if err := r.Signer.Sign(req, []byte(r.Body)); err != nil {
+			return nil, nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	rec := &phaseRecorder{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), rec.clientTrace()))
+	return req, rec, nil
+}
+
+// ParseHeaders reads the --headers flag format: a JSON object of header names to values.
+func ParseHeaders(raw string) (http.Header, error) {
+	header := http.Header{}
+	if strings.TrimSpace(raw) == "" {
+		return header, nil
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, fmt.Errorf("headers must be a JSON object of strings: %w", err)
+	}
+	for k, v := range values {
+		header.Set(k, v)
+	}
+	return header, nil
 }
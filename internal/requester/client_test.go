@@ -0,0 +1,69 @@
+package requester
+
+import (
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// writeCA saves the test server's certificate as a PEM bundle for TLSOptions.CAFile.
+func writeCA(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDoReusesTransport(t *testing.T) {
+	var conns, calls atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tlsOpts := &TLSOptions{CAFile: writeCA(t, srv)}
+	for i := 0; i < 3; i++ {
+		// A copy of the options, as a config reload or a new monitor tick would build.
+		opts := *tlsOpts
+		resp, err := Do(Request{URL: srv.URL, Method: http.MethodGet, Timeout: "5s", TLS: &opts,
+			Retry: RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d", resp.StatusCode)
+		}
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("opened %d connections for 4 requests, want 1", n)
+	}
+
+	other, _, err := Request{TLS: &TLSOptions{CAFile: tlsOpts.CAFile, ServerName: "example.com"}}.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, _, err := Request{TLS: tlsOpts}.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Transport == same.Transport {
+		t.Error("different TLS options share a transport")
+	}
+}
@@ -0,0 +1,210 @@
+package requester
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieJar is an in-memory cookie jar that can be saved to and loaded from a Netscape cookie
+// file, the format curl's -b/-c options use.
+type CookieJar struct {
+	jar  *cookiejar.Jar
+	path string
+
+	mu      sync.Mutex
+	entries map[string]cookieEntry // domain;path;name -> cookie
+}
+
+type cookieEntry struct {
+	Domain     string
+	Subdomains bool
+	Path       string
+	Secure     bool
+	HTTPOnly   bool
+	Expires    time.Time // zero for session cookies
+	Name       string
+	Value      string
+}
+
+var (
+	jarsMu sync.Mutex
+	jars   = map[string]*CookieJar{}
+)
+
+// OpenCookieJar returns the jar backed by path, loading it on first use. Jars are shared per path
+// for the life of the process, so consecutive monitor runs see each other's cookies.
+// An empty path returns a new jar that is never saved.
+func OpenCookieJar(path string) (*CookieJar, error) {
+	if path == "" {
+		return newCookieJar(""), nil
+	}
+	jarsMu.Lock()
+	defer jarsMu.Unlock()
+	if j, ok := jars[path]; ok {
+		return j, nil
+	}
+	j := newCookieJar(path)
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	jars[path] = j
+	return j, nil
+}
+
+func newCookieJar(path string) *CookieJar {
+	jar, _ := cookiejar.New(nil)
+	return &CookieJar{jar: jar, path: path, entries: map[string]cookieEntry{}}
+}
+
+// Cookies implements http.CookieJar.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// SetCookies implements http.CookieJar and remembers the cookies' attributes for Save.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	for _, c := range cookies {
+		e := cookieEntry{
+			Domain:   strings.ToLower(u.Hostname()),
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+			Name:     c.Name,
+			Value:    c.Value,
+		}
+		if c.Domain != "" {
+			e.Domain = strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+			e.Subdomains = true
+		}
+		if e.Path == "" || e.Path[0] != '/' {
+			e.Path = defaultCookiePath(u.Path)
+		}
+		switch {
+		case c.MaxAge < 0:
+			e.Expires = now.Add(-time.Second)
+		case c.MaxAge > 0:
+			e.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			e.Expires = c.Expires
+		}
+
+		key := e.Domain + ";" + e.Path + ";" + e.Name
+		if !e.Expires.IsZero() && !e.Expires.After(now) {
+			delete(j.entries, key)
+			continue
+		}
+		j.entries[key] = e
+	}
+}
+
+// defaultCookiePath is the directory of the request path (RFC 6265 section 5.1.4).
+func defaultCookiePath(p string) string {
+	if p == "" || p[0] != '/' || strings.Count(p, "/") == 1 {
+		return "/"
+	}
+	return path.Dir(p)
+}
+
+// Save writes the jar to its file, if it has one.
+func (j *CookieJar) Save() error {
+	if j.path == "" {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# Netscape HTTP Cookie File\n# Written by pingify.\n\n")
+	now := time.Now()
+	for _, e := range j.entries {
+		if !e.Expires.IsZero() && !e.Expires.After(now) {
+			continue
+		}
+		domain := e.Domain
+		if e.Subdomains {
+			domain = "." + domain
+		}
+		if e.HTTPOnly {
+			domain = "#HttpOnly_" + domain
+		}
+		var expires int64
+		if !e.Expires.IsZero() {
+			expires = e.Expires.Unix()
+		}
+		fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, netscapeBool(e.Subdomains), e.Path, netscapeBool(e.Secure), expires, e.Name, e.Value)
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+func netscapeBool(v bool) string {
+	if v {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// load reads the jar's file; a missing file is an empty jar.
+func (j *CookieJar) load() error {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		httpOnly := strings.HasPrefix(text, "#HttpOnly_")
+		text = strings.TrimPrefix(text, "#HttpOnly_")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return fmt.Errorf("%s:%d: expected 7 tab-separated fields", j.path, line)
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s:%d: invalid expiry %q", j.path, line, fields[4])
+		}
+
+		host := strings.TrimPrefix(fields[0], ".")
+		secure := fields[3] == "TRUE"
+		c := &http.Cookie{Name: fields[5], Value: fields[6], Path: fields[2], Secure: secure, HttpOnly: httpOnly}
+		if fields[1] == "TRUE" {
+			c.Domain = host
+		}
+		if expires > 0 {
+			c.Expires = time.Unix(expires, 0)
+		}
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		j.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: fields[2]}, []*http.Cookie{c})
+	}
+	return scanner.Err()
+}
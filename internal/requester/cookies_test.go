@@ -0,0 +1,134 @@
+package requester
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieJarAcrossRequests(t *testing.T) {
+	// /login sets the cookies and redirects to /me, which echoes the cookies it receives.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+			http.SetCookie(w, &http.Cookie{Name: "pref", Value: "dark", Path: "/", MaxAge: 3600})
+			http.Redirect(w, r, "/me", http.StatusFound)
+		case "/logout":
+			http.SetCookie(w, &http.Cookie{Name: "pref", Value: "", Path: "/", MaxAge: -1})
+		default:
+			var names []string
+			for _, c := range r.Cookies() {
+				names = append(names, c.Name+"="+c.Value)
+			}
+			sort.Strings(names)
+			w.Write([]byte(strings.Join(names, "; ")))
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	jar, err := OpenCookieJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same, _ := OpenCookieJar(path); same != jar {
+		t.Error("OpenCookieJar returned a second jar for the same file")
+	}
+	get := func(jar *CookieJar, p string) string {
+		t.Helper()
+		resp, err := Do(Request{URL: srv.URL + p, Method: http.MethodGet, Timeout: "5s", HTTP: &HTTPOptions{Jar: jar}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Body
+	}
+
+	// Cookies set on a redirect are sent when it is followed.
+	if got := get(jar, "/login"); got != "pref=dark; session=abc" {
+		t.Errorf("after login the server saw %q", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "#HttpOnly_127.0.0.1\tFALSE\t/\tFALSE\t0\tsession\tabc") ||
+		!strings.Contains(string(data), "\tpref\tdark") {
+		t.Errorf("cookie file =\n%s", data)
+	}
+
+	// A new process loads the file.
+	reloaded := newCookieJar(path)
+	if err := reloaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(reloaded, "/me"); got != "pref=dark; session=abc" {
+		t.Errorf("with the saved jar the server saw %q", got)
+	}
+
+	// An expiring Set-Cookie removes the cookie from the jar and the file.
+	get(jar, "/logout")
+	if got := get(jar, "/me"); got != "session=abc" {
+		t.Errorf("after logout the server saw %q", got)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "pref") {
+		t.Errorf("expired cookie still saved:\n%s", data)
+	}
+}
+
+func TestCookieJarLoad(t *testing.T) {
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	tests := []struct {
+		name    string
+		file    string
+		url     string
+		want    string // cookies sent to url
+		errText string
+	}{
+		{"host cookie", "api.example.com\tFALSE\t/\tFALSE\t0\tid\t1\n", "http://api.example.com/x", "id=1", ""},
+		{"not sent to subdomains", "example.com\tFALSE\t/\tFALSE\t0\tid\t1\n", "http://api.example.com/", "", ""},
+		{"domain cookie", ".example.com\tTRUE\t/\tFALSE\t" + future + "\tid\t1\n", "http://api.example.com/", "id=1", ""},
+		{"secure cookie over http", "api.example.com\tFALSE\t/\tTRUE\t0\tid\t1\n", "http://api.example.com/", "", ""},
+		{"secure cookie over https", "api.example.com\tFALSE\t/\tTRUE\t0\tid\t1\n", "https://api.example.com/", "id=1", ""},
+		{"path", "api.example.com\tFALSE\t/v1\tFALSE\t0\tid\t1\n", "http://api.example.com/v2", "", ""},
+		{"expired", "api.example.com\tFALSE\t/\tFALSE\t" + past + "\tid\t1\n", "http://api.example.com/", "", ""},
+		{"comments and http-only", "# Netscape HTTP Cookie File\n\n#HttpOnly_api.example.com\tFALSE\t/\tFALSE\t0\tid\t1\n", "http://api.example.com/", "id=1", ""},
+		{"missing field", "api.example.com\tFALSE\t/\tFALSE\t0\tid\n", "", "", "cookies.txt:1: expected 7 tab-separated fields"},
+		{"bad expiry", "api.example.com\tFALSE\t/\tFALSE\tsoon\tid\t1\n", "", "", `invalid expiry "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cookies.txt")
+			if err := os.WriteFile(path, []byte(tt.file), 0600); err != nil {
+				t.Fatal(err)
+			}
+			jar := newCookieJar(path)
+			err := jar.load()
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Errorf("err = %v, want it to mention %q", err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, _ := url.Parse(tt.url)
+			var got []string
+			for _, c := range jar.Cookies(u) {
+				got = append(got, c.Name+"="+c.Value)
+			}
+			if strings.Join(got, "; ") != tt.want {
+				t.Errorf("cookies for %s = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
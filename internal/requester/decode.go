@@ -0,0 +1,252 @@
+package requester
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// AcceptEncoding is advertised on every request unless the headers set their own.
+const AcceptEncoding = "gzip, deflate, br, zstd"
+
+// decompress wraps r in decoders for a Content-Encoding header, undoing the codings in reverse
+// order of application. Closing the result releases the decoders; it does not close r. An empty
+// body is returned as is, since HEAD, 204 and 304 responses may still name a coding.
+func decompress(encoding string, r io.Reader) (io.ReadCloser, error) {
+	body := &decoder{Reader: r}
+	if encoding == "" {
+		return body, nil
+	}
+	br := bufio.NewReader(r)
+	if _, err := br.Peek(1); err != nil {
+		return body, nil
+	}
+	body.Reader = br
+
+	codings := strings.Split(encoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		var err error
+		switch c := strings.ToLower(strings.TrimSpace(codings[i])); c {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			var zr *gzip.Reader
+			if zr, err = gzip.NewReader(body.Reader); err == nil {
+				body.push(zr, zr.Close)
+			}
+		case "deflate":
+			var fr io.ReadCloser
+			if fr, err = inflate(body.Reader); err == nil {
+				body.push(fr, fr.Close)
+			}
+		case "br":
+			body.push(brotli.NewReader(body.Reader), nil)
+		case "zstd":
+			var d *zstd.Decoder
+			if d, err = zstd.NewReader(body.Reader, zstd.WithDecoderConcurrency(1)); err == nil {
+				body.push(d, func() error { d.Close(); return nil })
+			}
+		default:
+			body.Close()
+			return nil, fmt.Errorf("unsupported Content-Encoding %q", c)
+		}
+		if err != nil {
+			body.Close()
+			return nil, fmt.Errorf("failed to decode %s body: %w", codings[i], err)
+		}
+	}
+	return body, nil
+}
+
+// decoder is a chain of content decoders; Close releases them innermost first.
+type decoder struct {
+	io.Reader
+	closers []func() error
+}
+
+func (d *decoder) push(r io.Reader, close func() error) {
+	d.Reader = r
+	if close != nil {
+		d.closers = append(d.closers, close)
+	}
+}
+
+func (d *decoder) Close() error {
+	for i := len(d.closers) - 1; i >= 0; i-- {
+		d.closers[i]()
+	}
+	d.closers = nil
+	return nil
+}
+
+// inflate reads deflate bodies, which servers send either zlib-wrapped (as the RFC says) or raw.
+func inflate(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil && len(header) < 2 {
+		return io.NopCloser(br), nil // empty body
+	}
+	if (uint16(header[0])<<8|uint16(header[1]))%31 == 0 && header[0]&0x0f == 8 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// toUTF8 converts a text body in a declared non-UTF-8 charset, returning the charset it converted.
+func toUTF8(contentType string, body []byte) ([]byte, string) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return body, ""
+	}
+	charset := strings.ToLower(params["charset"])
+	if charset == "" || charset == "utf-8" || charset == "utf8" || charset == "us-ascii" {
+		return body, ""
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return body, ""
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, ""
+	}
+	return decoded, charset
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence left at the end of a truncated body, and the
+// replacement character a charset decoder writes for one.
+func trimPartialRune(body []byte, converted bool) []byte {
+	for i := 0; i < utf8.UTFMax-1 && len(body) > 0; i++ {
+		if r, size := utf8.DecodeLastRune(body); r != utf8.RuneError || size != 1 {
+			break
+		}
+		body = body[:len(body)-1]
+	}
+	if r, size := utf8.DecodeLastRune(body); converted && r == utf8.RuneError && size == 3 {
+		body = body[:len(body)-size]
+	}
+	return body
+}
+
+// IsBinary reports whether a body should not be printed to a terminal. Textual media types are
+// binary only if they contain NUL bytes; other bodies are sniffed for NULs and invalid UTF-8.
+func IsBinary(contentType string, body []byte) bool {
+	sample := body
+	if len(sample) > 8192 {
+		sample = sample[:8192]
+	}
+	hasNUL := bytes.IndexByte(sample, 0) >= 0
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasPrefix(mediaType, "text/"), strings.HasSuffix(mediaType, "json"),
+			strings.HasSuffix(mediaType, "xml"), strings.HasSuffix(mediaType, "javascript"),
+			mediaType == "application/x-www-form-urlencoded":
+			return hasNUL
+		case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"),
+			strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "font/"),
+			mediaType == "application/octet-stream", mediaType == "application/pdf",
+			mediaType == "application/zip", mediaType == "application/gzip",
+			strings.Contains(mediaType, "protobuf"), strings.HasPrefix(mediaType, "application/grpc"):
+			return true
+		}
+	}
+	if hasNUL {
+		return true
+	}
+	// The sample may end in the middle of a multi-byte rune.
+	for i := 0; i < 3 && len(sample) < len(body) && !utf8.Valid(sample); i++ {
+		sample = sample[:len(sample)-1]
+	}
+	return !utf8.Valid(sample)
+}
+
+// countingReader counts the bytes read through it and reports progress.
+type countingReader struct {
+	r        io.Reader
+	n        int64
+	total    int64
+	progress func(read, total int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.progress != nil && n > 0 {
+		c.progress(c.n, c.total)
+	}
+	return n, err
+}
+
+// limitWriter passes through up to limit bytes (all of them when limit is zero) and drops the rest.
+type limitWriter struct {
+	w       io.Writer
+	limit   int64
+	written int64
+	dropped bool
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if l.limit > 0 && l.written+int64(len(p)) > l.limit {
+		p = p[:l.limit-l.written]
+		l.dropped = true
+	}
+	written, err := l.w.Write(p)
+	l.written += int64(written)
+	if err != nil {
+		return written, err
+	}
+	return n, nil
+}
+
+// ParseSize reads sizes like 512, 64KB, 10MB or 1GiB. KB, MB and GB are powers of 1000 and
+// KiB, MiB and GiB powers of 1024.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+		{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"K", 1e3}, {"M", 1e6}, {"G", 1e9}, {"B", 1},
+	}
+	multiplier := int64(1)
+	upper := strings.ToUpper(s)
+	for _, u := range units {
+		if strings.HasSuffix(upper, u.suffix) {
+			multiplier = u.size
+			s = strings.TrimSpace(s[:len(s)-len(u.suffix)])
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512KB or 10MB)", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// FormatSize renders a byte count for display, e.g. 1.5 MB.
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1f GB", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1f MB", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1f KB", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,188 @@
+package requester
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encode applies a single Content-Encoding to data.
+func encode(t *testing.T, coding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch coding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "deflate-raw":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		zw, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w = zw
+	default:
+		t.Fatalf("unknown coding %q", coding)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDoDecodesBody(t *testing.T) {
+	plain := []byte(strings.Repeat(`{"message":"hello"}`, 50))
+	tests := []struct {
+		name     string
+		header   string   // Content-Encoding sent
+		codings  []string // applied in order
+		method   string
+		status   int
+		wantBody string
+	}{
+		{"identity", "", []string{}, http.MethodGet, 200, string(plain)},
+		{"gzip", "gzip", []string{"gzip"}, http.MethodGet, 200, string(plain)},
+		{"deflate zlib", "deflate", []string{"deflate"}, http.MethodGet, 200, string(plain)},
+		{"deflate raw", "deflate", []string{"deflate-raw"}, http.MethodGet, 200, string(plain)},
+		{"br", "br", []string{"br"}, http.MethodGet, 200, string(plain)},
+		{"zstd", "zstd", []string{"zstd"}, http.MethodGet, 200, string(plain)},
+		{"chained", "gzip, br", []string{"gzip", "br"}, http.MethodGet, 200, string(plain)},
+		{"head with gzip", "gzip", nil, http.MethodHead, 200, ""},
+		{"304 with gzip", "gzip", nil, http.MethodGet, 304, ""},
+		{"204 with zstd", "zstd", nil, http.MethodGet, 204, ""},
+		{"empty 200 with br", "br", nil, http.MethodGet, 200, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			if tt.codings != nil {
+				body = plain
+				for _, c := range tt.codings {
+					body = encode(t, c, body)
+				}
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Content-Encoding", tt.header)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			resp, err := Do(Request{URL: srv.URL, Method: tt.method, Timeout: "5s"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Body != tt.wantBody {
+				t.Errorf("body = %.40q, want %.40q", resp.Body, tt.wantBody)
+			}
+			if resp.Size != int64(len(tt.wantBody)) || resp.WireSize != int64(len(body)) {
+				t.Errorf("size = %d (wire %d), want %d (wire %d)", resp.Size, resp.WireSize, len(tt.wantBody), len(body))
+			}
+		})
+	}
+}
+
+func TestDoReadsBodyLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentType   string
+		body          []byte
+		maxBody       int64
+		wantBody      string
+		wantTruncated bool
+		wantBinary    bool
+	}{
+		{"under limit", "text/plain", []byte("hello"), 10, "hello", false, false},
+		{"cut at limit", "text/plain", []byte("hello world"), 5, "hello", true, false},
+		{"cut inside a utf-8 rune", "text/plain", []byte("abc€"), 5, "abc", true, false},
+		{"cut inside a shift_jis character", "text/plain; charset=shift_jis", []byte("a\x82\xa0\x82\xa2"), 4, "aあ", true, false},
+		{"charset converted", "text/plain; charset=iso-8859-1", []byte("caf\xe9"), 0, "café", false, false},
+		{"binary media type", "image/png", []byte("\x89PNG\r\n"), 0, "\x89PNG\r\n", false, true},
+		{"binary sniffed", "", []byte("ab\x00cd"), 0, "ab\x00cd", false, true},
+		{"binary kept whole when cut", "application/octet-stream", []byte("ab\xe2\x82"), 3, "ab\xe2", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			resp, err := Do(Request{URL: srv.URL, Method: http.MethodGet, Timeout: "5s", MaxBody: tt.maxBody})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Body != tt.wantBody {
+				t.Errorf("body = %q, want %q", resp.Body, tt.wantBody)
+			}
+			if resp.Truncated != tt.wantTruncated || resp.Binary != tt.wantBinary {
+				t.Errorf("truncated = %v, binary = %v, want %v, %v", resp.Truncated, resp.Binary, tt.wantTruncated, tt.wantBinary)
+			}
+		})
+	}
+}
+
+func TestDoOutputTruncatedOnRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("temporarily unavailable, please try again later"))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(encode(t, "gzip", []byte("ok body")))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "out")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	resp, err := Do(Request{URL: srv.URL, Method: http.MethodGet, Timeout: "5s", Output: f, MaxBody: 4,
+		Retry: RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "ok b" || !resp.Truncated || resp.Size != 4 {
+		t.Errorf("file = %q, truncated = %v, size = %d; want %q, true, 4", got, resp.Truncated, resp.Size, "ok b")
+	}
+}
+
+func TestDecompressRejectsUnknownCoding(t *testing.T) {
+	if _, err := decompress("compress", strings.NewReader("x")); err == nil {
+		t.Fatal("unknown coding accepted")
+	}
+}
@@ -0,0 +1,111 @@
+package requester
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Supported values for HTTPOptions.Version.
+const (
+	HTTPVersion1   = "1.1"
+	HTTPVersion2   = "2"
+	HTTPVersionH2C = "h2c"
+)
+
+// HTTPOptions controls redirect following, cookies and the HTTP protocol version.
+type HTTPOptions struct {
+	// NoRedirects returns 3xx responses as they are instead of following them.
+	NoRedirects bool
+	// MaxRedirects caps how many redirects are followed; 0 means Go's default of 10.
+	MaxRedirects int
+	// Jar, when set, stores cookies between requests and is saved after each one.
+	Jar *CookieJar
+	// Version forces HTTP/1.1 ("1.1"), HTTP/2 ("2", prior knowledge on http URLs) or
+	// cleartext HTTP/2 only ("h2c"). Empty negotiates as usual.
+	Version string
+}
+
+// IsZero reports whether o leaves Go's defaults untouched.
+func (o *HTTPOptions) IsZero() bool {
+	return o == nil || *o == HTTPOptions{}
+}
+
+// Hop is one redirect followed while making a request.
+type Hop struct {
+	Method     string        `json:"method,omitempty"`
+	URL        string        `json:"url"`
+	StatusCode int           `json:"status_code"`
+	Location   string        `json:"location"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// ValidateHTTPVersion checks a --http-version value.
+func ValidateHTTPVersion(v string) error {
+	switch v {
+	case "", HTTPVersion1, HTTPVersion2, HTTPVersionH2C:
+		return nil
+	}
+	return fmt.Errorf("unsupported HTTP version %q (use 1.1, 2 or h2c)", v)
+}
+
+// protocols returns the transport protocols for o.Version, or nil to keep the defaults.
+func (o *HTTPOptions) protocols() (*http.Protocols, error) {
+	if o == nil || o.Version == "" {
+		return nil, nil
+	}
+	if err := ValidateHTTPVersion(o.Version); err != nil {
+		return nil, err
+	}
+	p := new(http.Protocols)
+	switch o.Version {
+	case HTTPVersion1:
+		p.SetHTTP1(true)
+	case HTTPVersion2:
+		p.SetHTTP2(true)
+		p.SetUnencryptedHTTP2(true)
+	case HTTPVersionH2C:
+		p.SetUnencryptedHTTP2(true)
+	}
+	return p, nil
+}
+
+// redirectRecorder records the hops of one attempt.
+type redirectRecorder struct {
+	hops []Hop
+	last time.Time
+	// onHop is called with the time each redirect is followed.
+	onHop func(time.Time)
+}
+
+// checkRedirect applies the redirect options and records each hop as it is followed.
+func (o *HTTPOptions) checkRedirect(rec *redirectRecorder) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if o != nil && o.NoRedirects {
+			return http.ErrUseLastResponse
+		}
+		max := 10
+		if o != nil && o.MaxRedirects > 0 {
+			max = o.MaxRedirects
+		}
+		if len(via) > max {
+			return fmt.Errorf("stopped after %d redirects", max)
+		}
+		if resp := req.Response; resp != nil {
+			now := time.Now()
+			prev := via[len(via)-1]
+			rec.hops = append(rec.hops, Hop{
+				Method:     prev.Method,
+				URL:        prev.URL.String(),
+				StatusCode: resp.StatusCode,
+				Location:   resp.Header.Get("Location"),
+				Duration:   now.Sub(rec.last),
+			})
+			rec.last = now
+			if rec.onHop != nil {
+				rec.onHop(now)
+			}
+		}
+		return nil
+	}
+}
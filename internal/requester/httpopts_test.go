@@ -0,0 +1,106 @@
+package requester
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRedirects(t *testing.T) {
+	// /hop/3 redirects to /hop/2 and so on down to /hop/0, which answers "done".
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if n == 0 {
+			w.Write([]byte("done"))
+			return
+		}
+		http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusFound)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		opts       *HTTPOptions
+		wantStatus int
+		wantHops   int
+		errText    string
+	}{
+		{"followed by default", nil, http.StatusOK, 3, ""},
+		{"no redirects", &HTTPOptions{NoRedirects: true}, http.StatusFound, 0, ""},
+		{"within the limit", &HTTPOptions{MaxRedirects: 3}, http.StatusOK, 3, ""},
+		{"over the limit", &HTTPOptions{MaxRedirects: 2}, 0, 0, "stopped after 2 redirects"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Do(Request{URL: srv.URL + "/hop/3", Method: http.MethodGet, Timeout: "5s", HTTP: tt.opts})
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus || len(resp.Redirects) != tt.wantHops {
+				t.Fatalf("status = %d after %d hops, want %d after %d", resp.StatusCode, len(resp.Redirects), tt.wantStatus, tt.wantHops)
+			}
+			for i, hop := range resp.Redirects {
+				from, to := "/hop/"+strconv.Itoa(3-i), "/hop/"+strconv.Itoa(2-i)
+				if hop.URL != srv.URL+from || hop.Location != to || hop.StatusCode != http.StatusFound || hop.Method != http.MethodGet {
+					t.Errorf("hop %d = %+v, want %s -> %s", i, hop, from, to)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+	// A cleartext server that also speaks HTTP/2 with prior knowledge.
+	h2c := httptest.NewUnstartedServer(handler)
+	h2c.Config.Protocols = new(http.Protocols)
+	h2c.Config.Protocols.SetHTTP1(true)
+	h2c.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2c.Start()
+	defer h2c.Close()
+	tlsSrv := httptest.NewUnstartedServer(handler)
+	tlsSrv.EnableHTTP2 = true
+	tlsSrv.StartTLS()
+	defer tlsSrv.Close()
+	caFile := writeCA(t, tlsSrv)
+
+	tests := []struct {
+		name    string
+		url     string
+		version string
+		want    string
+	}{
+		{"cleartext default", h2c.URL, "", "HTTP/1.1"},
+		{"h2c", h2c.URL, HTTPVersionH2C, "HTTP/2.0"},
+		{"http2 prior knowledge", h2c.URL, HTTPVersion2, "HTTP/2.0"},
+		{"tls default", tlsSrv.URL, "", "HTTP/2.0"},
+		{"tls forced http/1.1", tlsSrv.URL, HTTPVersion1, "HTTP/1.1"},
+		{"tls http2", tlsSrv.URL, HTTPVersion2, "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Do(Request{URL: tt.url, Method: http.MethodGet, Timeout: "5s",
+				TLS: &TLSOptions{CAFile: caFile}, HTTP: &HTTPOptions{Version: tt.version}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Body != tt.want || resp.Proto != tt.want {
+				t.Errorf("server saw %s, response is %s; want %s", resp.Body, resp.Proto, tt.want)
+			}
+		})
+	}
+
+	if _, err := Do(Request{URL: h2c.URL, Method: http.MethodGet, HTTP: &HTTPOptions{Version: "3"}}); err == nil {
+		t.Error("HTTP version 3 accepted")
+	}
+}
@@ -0,0 +1,259 @@
+package requester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// NetOptions controls how connections are routed and opened.
+type NetOptions struct {
+	// Proxy is an http://, https://, socks5:// (local DNS) or socks5h:// (proxy DNS) URL, optionally
+	// with user:password. Without it the HTTP(S)_PROXY environment variables apply.
+	Proxy string
+	// NoProxy lists hosts, domains (.example.com) and CIDRs reached directly, like NO_PROXY.
+	NoProxy string
+
+	// Resolve pins host:port to addresses, curl style: "api.example.com:443:10.0.0.5[,10.0.0.6]".
+	Resolve []string
+	// ConnectTo redirects connections, curl style: "host:port:connect-host:connect-port";
+	// an empty host or port matches any.
+	ConnectTo []string
+
+	// IPVersion forces IPv4 (4) or IPv6 (6); zero uses either.
+	IPVersion int
+	// LocalAddress binds outgoing connections to an IP address or a network interface name.
+	LocalAddress string
+	// UnixSocket sends every connection to this Unix domain socket instead.
+	UnixSocket string
+}
+
+// IsZero reports whether the options leave Go's networking defaults alone.
+func (o *NetOptions) IsZero() bool {
+	return o == nil || (o.Proxy == "" && o.NoProxy == "" && len(o.Resolve) == 0 && len(o.ConnectTo) == 0 &&
+		o.IPVersion == 0 && o.LocalAddress == "" && o.UnixSocket == "")
+}
+
+// DialFunc opens a connection to addr.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Dialer returns a dial function applying ConnectTo, Resolve, IPVersion, LocalAddress, UnixSocket
+// and a SOCKS5 Proxy. HTTP proxies are not involved; see ProxyFunc.
+func (o *NetOptions) Dialer() (DialFunc, error) {
+	if o.IPVersion != 0 && o.IPVersion != 4 && o.IPVersion != 6 {
+		return nil, fmt.Errorf("IP version must be 4 or 6, not %d", o.IPVersion)
+	}
+	resolve, err := parseHostMap(o.Resolve, 3, "--resolve host:port:addr")
+	if err != nil {
+		return nil, err
+	}
+	connectTo, err := parseHostMap(o.ConnectTo, 4, "--connect-to host:port:connect-host:connect-port")
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if o.LocalAddress != "" {
+		ip, err := localIP(o.LocalAddress, o.IPVersion)
+		if err != nil {
+			return nil, err
+		}
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+
+	if o.UnixSocket != "" {
+		return func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", o.UnixSocket)
+		}, nil
+	}
+
+	network := "tcp"
+	if o.IPVersion != 0 {
+		network = fmt.Sprintf("tcp%d", o.IPVersion)
+	}
+
+	var socks proxy.ContextDialer
+	var socksBypass func(host string) bool
+	localDNS := false
+	if strings.HasPrefix(o.Proxy, "socks5") {
+		u, err := url.Parse(o.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		var auth *proxy.Auth
+		if u.User != nil {
+			password, _ := u.User.Password()
+			auth = &proxy.Auth{User: u.User.Username(), Password: password}
+		}
+		d, err := proxy.SOCKS5("tcp", u.Host, auth, dialer)
+		if err != nil {
+			return nil, err
+		}
+		socks = d.(proxy.ContextDialer)
+		localDNS = u.Scheme == "socks5"
+		bypass := (&httpproxy.Config{HTTPProxy: o.Proxy, HTTPSProxy: o.Proxy, NoProxy: o.NoProxy}).ProxyFunc()
+		socksBypass = func(host string) bool {
+			p, _ := bypass(&url.URL{Scheme: "https", Host: host})
+			return p == nil
+		}
+	}
+
+	return func(ctx context.Context, _, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		// --noproxy matches the host that was asked for, not where --resolve or --connect-to send it.
+		viaSocks := socks != nil && !socksBypass(addr)
+		for _, m := range connectTo {
+			if (m[0] == "" || strings.EqualFold(m[0], host)) && (m[1] == "" || m[1] == port) {
+				if m[2] != "" {
+					host = m[2]
+				}
+				if m[3] != "" {
+					port = m[3]
+				}
+				break
+			}
+		}
+
+		addrs := []string{host}
+		for _, m := range resolve {
+			if (m[0] == "*" || strings.EqualFold(m[0], host)) && m[1] == port {
+				addrs = strings.Split(strings.Trim(m[2], "[]"), ",")
+				break
+			}
+		}
+
+		var lastErr error
+		for _, a := range addrs {
+			target := net.JoinHostPort(strings.Trim(a, "[] "), port)
+			var conn net.Conn
+			switch {
+			case viaSocks:
+				if localDNS && net.ParseIP(a) == nil {
+					ips, err := net.DefaultResolver.LookupIP(ctx, "ip"+strings.TrimPrefix(network, "tcp"), a)
+					if err != nil {
+						lastErr = err
+						continue
+					}
+					target = net.JoinHostPort(ips[0].String(), port)
+				}
+				conn, err = socks.DialContext(ctx, "tcp", target)
+			default:
+				conn, err = dialer.DialContext(ctx, network, target)
+			}
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}, nil
+}
+
+// GRPCDialer adapts Dialer for grpc.WithContextDialer; it returns nil when o is zero.
+func (o *NetOptions) GRPCDialer() (func(ctx context.Context, addr string) (net.Conn, error), error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	dial, err := o.Dialer()
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return dial(ctx, "tcp", addr)
+	}, nil
+}
+
+// ProxyFunc returns the HTTP(S) proxy selection for an http.Transport: Proxy and NoProxy when
+// Proxy is an http(s) URL, nothing for SOCKS (handled by Dialer), and the environment otherwise.
+func (o *NetOptions) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	switch {
+	case o.UnixSocket != "" || strings.HasPrefix(o.Proxy, "socks5"):
+		return nil, nil
+	case o.Proxy != "":
+		u, err := url.Parse(o.Proxy)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("unsupported proxy %q (use http://, https://, socks5:// or socks5h://)", o.Proxy)
+		}
+	case o.NoProxy == "":
+		return http.ProxyFromEnvironment, nil
+	}
+
+	cfg := httpproxy.FromEnvironment()
+	if o.Proxy != "" {
+		cfg.HTTPProxy, cfg.HTTPSProxy = o.Proxy, o.Proxy
+	}
+	if o.NoProxy != "" {
+		cfg.NoProxy = o.NoProxy
+	}
+	proxyFor := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFor(req.URL)
+	}, nil
+}
+
+// parseHostMap splits curl-style colon-separated entries into n fields; colons inside
+// [brackets] (IPv6 addresses) don't separate fields.
+func parseHostMap(entries []string, n int, usage string) ([][]string, error) {
+	var out [][]string
+	for _, entry := range entries {
+		var fields []string
+		depth, start := 0, 0
+		for i, c := range entry {
+			switch {
+			case c == '[':
+				depth++
+			case c == ']':
+				depth--
+			case c == ':' && depth == 0 && len(fields) < n-1:
+				fields = append(fields, strings.Trim(entry[start:i], "[]"))
+				start = i + 1
+			}
+		}
+		fields = append(fields, entry[start:])
+		if n == 4 && len(fields) == 4 {
+			fields[3] = strings.Trim(fields[3], "[]")
+		}
+		if len(fields) != n || (n == 3 && (fields[1] == "" || fields[2] == "")) {
+			return nil, fmt.Errorf("invalid entry %q, expected %s", entry, usage)
+		}
+		out = append(out, fields)
+	}
+	return out, nil
+}
+
+// localIP resolves an IP address or an interface name to the address to bind.
+func localIP(addr string, version int) (net.IP, error) {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip, nil
+	}
+	iface, err := net.InterfaceByName(addr)
+	if err != nil {
+		return nil, fmt.Errorf("local address %q is neither an IP nor an interface: %w", addr, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		is4 := ipnet.IP.To4() != nil
+		if version == 0 || (version == 4) == is4 {
+			return ipnet.IP, nil
+		}
+	}
+	return nil, errors.New("interface " + addr + " has no usable address")
+}
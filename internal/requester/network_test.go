@@ -0,0 +1,194 @@
+package requester
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// startSOCKS5 runs a minimal SOCKS5 server (no auth, CONNECT only) and counts the connections
+// it relays.
+func startSOCKS5(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	var relayed atomic.Int32
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 262)
+				// Greeting: version, method count, methods.
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				conn.Write([]byte{5, 0})
+				// Request: version, CONNECT, reserved, address type, address, port.
+				if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+					return
+				}
+				var host string
+				switch buf[3] {
+				case 1:
+					io.ReadFull(conn, buf[:4])
+					host = net.IP(buf[:4]).String()
+				case 3:
+					io.ReadFull(conn, buf[:1])
+					n := int(buf[0])
+					io.ReadFull(conn, buf[:n])
+					host = string(buf[:n])
+				case 4:
+					io.ReadFull(conn, buf[:16])
+					host = net.IP(buf[:16]).String()
+				}
+				io.ReadFull(conn, buf[:2])
+				port := binary.BigEndian.Uint16(buf[:2])
+				target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+				if err != nil {
+					conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+				relayed.Add(1)
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+	return lis.Addr().String(), &relayed
+}
+
+func TestNetOptions(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("origin " + r.Host))
+	}))
+	defer origin.Close()
+	_, port, _ := net.SplitHostPort(origin.Listener.Addr().String())
+
+	// An HTTP proxy answers for any host itself, so reaching it is visible in the body.
+	var proxied atomic.Int32
+	httpProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		w.Write([]byte("proxy " + r.URL.String()))
+	}))
+	defer httpProxy.Close()
+	socksAddr, relayed := startSOCKS5(t)
+
+	tests := []struct {
+		name        string
+		url         string
+		opts        NetOptions
+		wantBody    string
+		wantProxied int32
+		wantRelayed int32
+	}{
+		{"resolve", "http://api.test:" + port + "/", NetOptions{Resolve: []string{"api.test:" + port + ":127.0.0.1"}}, "origin api.test:" + port, 0, 0},
+		{"resolve wildcard", "http://any.test:" + port + "/", NetOptions{Resolve: []string{"*:" + port + ":127.0.0.1"}}, "origin any.test:" + port, 0, 0},
+		{"connect-to", "http://api.test/", NetOptions{ConnectTo: []string{"api.test:80:127.0.0.1:" + port}}, "origin api.test", 0, 0},
+		{"connect-to any host", "http://other.test/", NetOptions{ConnectTo: []string{":80:127.0.0.1:" + port}}, "origin other.test", 0, 0},
+		{"http proxy", "http://api.test/items", NetOptions{Proxy: httpProxy.URL}, "proxy http://api.test/items", 1, 0},
+		{"http proxy bypassed", "http://api.test:" + port + "/", NetOptions{Proxy: httpProxy.URL, NoProxy: ".test", Resolve: []string{"api.test:" + port + ":127.0.0.1"}}, "origin api.test:" + port, 0, 0},
+		{"socks5h proxy", "http://api.test:" + port + "/", NetOptions{Proxy: "socks5h://" + socksAddr, Resolve: []string{"api.test:" + port + ":127.0.0.1"}}, "origin api.test:" + port, 0, 1},
+		{"socks5 bypassed by original host", "http://api.test:" + port + "/", NetOptions{Proxy: "socks5://" + socksAddr, NoProxy: "api.test", Resolve: []string{"api.test:" + port + ":127.0.0.1"}}, "origin api.test:" + port, 0, 0},
+		{"ipv4 only", origin.URL + "/", NetOptions{IPVersion: 4}, "origin " + origin.Listener.Addr().String(), 0, 0},
+		{"local address", origin.URL + "/", NetOptions{LocalAddress: "127.0.0.1"}, "origin " + origin.Listener.Addr().String(), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxied.Store(0)
+			relayed.Store(0)
+			resp, err := Do(Request{URL: tt.url, Method: http.MethodGet, Timeout: "5s", Net: &tt.opts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Body != tt.wantBody {
+				t.Errorf("body = %q, want %q", resp.Body, tt.wantBody)
+			}
+			if proxied.Load() != tt.wantProxied || relayed.Load() != tt.wantRelayed {
+				t.Errorf("went through the HTTP proxy %d and SOCKS %d time(s), want %d and %d",
+					proxied.Load(), relayed.Load(), tt.wantProxied, tt.wantRelayed)
+			}
+		})
+	}
+}
+
+func TestNetOptionsUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.sock")
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip("unix sockets unavailable:", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("unix " + r.Host))
+	})}
+	go srv.Serve(lis)
+	defer srv.Close()
+
+	resp, err := Do(Request{URL: "http://docker/v1/info", Method: http.MethodGet, Timeout: "5s", Net: &NetOptions{UnixSocket: path}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "unix docker" {
+		t.Errorf("body = %q, want %q", resp.Body, "unix docker")
+	}
+}
+
+func TestNetOptionsInvalid(t *testing.T) {
+	tests := map[string]NetOptions{
+		"ip version":        {IPVersion: 5},
+		"resolve":           {Resolve: []string{"api.test:443"}},
+		"connect-to":        {ConnectTo: []string{"api.test:443:backend"}},
+		"proxy scheme":      {Proxy: "ftp://proxy:21"},
+		"unknown interface": {LocalAddress: "no-such-interface0"},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Do(Request{URL: "http://api.test/", Method: http.MethodGet, Net: &opts}); err == nil {
+				t.Error("invalid options accepted")
+			}
+		})
+	}
+}
+
+func TestProxyFuncNoProxy(t *testing.T) {
+	opts := NetOptions{Proxy: "http://proxy.example.com:3128", NoProxy: "internal.example.com,.corp,10.0.0.0/8"}
+	proxy, err := opts.ProxyFunc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"http://api.example.com/":      true,
+		"http://internal.example.com/": false,
+		"https://svc.corp/":            false,
+		"http://10.1.2.3/":             false,
+		"http://11.1.2.3/":             true,
+	}
+	for rawURL, want := range tests {
+		u, _ := url.Parse(rawURL)
+		p, err := proxy(&http.Request{URL: u})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (p != nil) != want {
+			t.Errorf("%s proxied = %v, want %v", rawURL, p != nil, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package requester
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryStatuses are the status codes retried when a policy doesn't list its own.
+var DefaultRetryStatuses = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooEarly,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how Do retries failed attempts. The zero value makes a single attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles for every further retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay. A Retry-After asking for longer ends the retries instead.
+	MaxBackoff time.Duration
+	// RetryOn lists retried status codes; empty means DefaultRetryStatuses.
+	RetryOn []int
+}
+
+// Defaults used for the unset fields of a policy that retries.
+const (
+	DefaultBackoff    = 200 * time.Millisecond
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// Attempt records one try of a request made by Do.
+type Attempt struct {
+	Number     int           `json:"number"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	// Retry is why another attempt followed, and Wait how long Do slept before it.
+	Retry string        `json:"retry,omitempty"`
+	Wait  time.Duration `json:"wait,omitempty"`
+}
+
+// Retries is the number of attempts after the first.
+func (r *Response) Retries() int {
+	if len(r.Attempts) == 0 {
+		return 0
+	}
+	return len(r.Attempts) - 1
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = DefaultBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if len(p.RetryOn) == 0 {
+		p.RetryOn = DefaultRetryStatuses
+	}
+	return p
+}
+
+// retryReason explains why the outcome of an attempt is worth retrying, or returns "".
+func (p RetryPolicy) retryReason(resp *Response, err error) string {
+	if err != nil {
+		reason, ok := ClassifyError(err)
+		if !ok {
+			return ""
+		}
+		return reason
+	}
+	if slices.Contains(p.RetryOn, resp.StatusCode) {
+		return fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return ""
+}
+
+// delay is the exponential backoff with jitter before the given retry (1-based): a random
+// duration between half and all of Backoff*2^(retry-1), capped at MaxBackoff.
+// A Retry-After header takes precedence; ok is false when it asks for more than MaxBackoff.
+func (p RetryPolicy) delay(retry int, header http.Header) (time.Duration, bool) {
+	if after, found := retryAfter(header, time.Now()); found {
+		return after, after <= p.MaxBackoff
+	}
+
+	d := p.Backoff << (retry - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1)), true
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// ClassifyError reports whether a transport error is transient and names its class.
+// Timeouts, refused or reset connections and temporary DNS failures are retryable;
+// unknown hosts, certificate problems and malformed requests are not.
+func ClassifyError(err error) (string, bool) {
+	var dnsErr *net.DNSError
+	var certErr *x509.CertificateInvalidError
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &certErr), errors.As(err, &hostErr), errors.As(err, &authErr):
+		return "certificate error", false
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsNotFound {
+			return "unknown host", false
+		}
+		return "DNS failure", dnsErr.IsTimeout || dnsErr.IsTemporary
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout", true
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused", true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return "connection reset", true
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return "connection closed", true
+	}
+	return "transport error", false
+}
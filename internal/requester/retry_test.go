@@ -0,0 +1,153 @@
+package requester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int  // returned in order, the last one repeating
+		retryAfter string // sent with every non-200 response
+		policy     RetryPolicy
+		wantStatus int
+		wantTries  int
+	}{
+		{"no policy", []int{503, 200}, "", RetryPolicy{}, 503, 1},
+		{"recovers", []int{503, 502, 200}, "", RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond}, 200, 3},
+		{"gives up", []int{500}, "", RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}, 500, 3},
+		{"status not retried", []int{404, 200}, "", RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}, 404, 1},
+		{"custom statuses", []int{404, 200}, "", RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryOn: []int{404}}, 200, 2},
+		{"retry-after honoured", []int{429, 200}, "0", RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}, 200, 2},
+		{"retry-after too long", []int{429, 200}, "3600", RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Second}, 429, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1)) - 1
+				status := tt.statuses[min(n, len(tt.statuses)-1)]
+				if status != http.StatusOK && tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			resp, err := Do(Request{URL: srv.URL, Method: http.MethodGet, Timeout: "5s", Retry: tt.policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if len(resp.Attempts) != tt.wantTries || int(calls.Load()) != tt.wantTries {
+				t.Errorf("made %d attempts (%d recorded), want %d", calls.Load(), len(resp.Attempts), tt.wantTries)
+			}
+			for i, a := range resp.Attempts[:len(resp.Attempts)-1] {
+				if a.Retry == "" {
+					t.Errorf("attempt %d has no retry reason", i+1)
+				}
+			}
+			if last := resp.Attempts[len(resp.Attempts)-1]; last.Retry != "" {
+				t.Errorf("last attempt has retry reason %q", last.Retry)
+			}
+		})
+	}
+}
+
+func TestDoRetriesRefusedConnection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	resp, err := Do(Request{URL: "http://" + addr, Method: http.MethodGet, Retry: RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}})
+	if err == nil {
+		t.Fatal("request to a closed port succeeded")
+	}
+	if len(resp.Attempts) != 2 || resp.Attempts[0].Retry != "connection refused" {
+		t.Errorf("attempts = %+v, want a connection refused retry", resp.Attempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		found bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-5", 0, false},
+		{"soon", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, found := retryAfter(header, now)
+		if got != tt.want || found != tt.found {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+		{100, 500 * time.Millisecond, time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			d, ok := p.delay(tt.retry, http.Header{})
+			if !ok || d < tt.min || d > tt.max {
+				t.Fatalf("delay(%d) = %v, %v, want between %v and %v", tt.retry, d, ok, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err       error
+		reason    string
+		retryable bool
+	}{
+		{context.DeadlineExceeded, "timeout", true},
+		{fmt.Errorf("dial: %w", syscall.ECONNREFUSED), "connection refused", true},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), "connection reset", true},
+		{&net.DNSError{Err: "no such host", IsNotFound: true}, "unknown host", false},
+		{&net.DNSError{Err: "server misbehaving", IsTemporary: true}, "DNS failure", true},
+		{errors.New("malformed HTTP response"), "transport error", false},
+	}
+	for _, tt := range tests {
+		reason, retryable := ClassifyError(tt.err)
+		if reason != tt.reason || retryable != tt.retryable {
+			t.Errorf("ClassifyError(%v) = %q, %v, want %q, %v", tt.err, reason, retryable, tt.reason, tt.retryable)
+		}
+	}
+}
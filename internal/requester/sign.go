@@ -0,0 +1,175 @@
+package requester
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer adds a signature over the final request and its body. Signers run after headers and
+// auth are applied, once per attempt, so every retry and monitor run carries a fresh signature.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// SignOptions describes a signer for NewSigner. Secrets may be given as env:NAME or file:path.
+type SignOptions struct {
+	Type string // aws-sigv4 or hmac
+
+	// AWS SigV4. Region and Service are inferred from *.amazonaws.com hosts when empty;
+	// credentials come from the AWS_* environment variables or the shared credentials file.
+	Region  string
+	Service string
+	Profile string
+
+	// HMAC. Parts lists what is signed, joined by Separator (default newline): body, timestamp,
+	// method, path, query, url or header:Name. It defaults to timestamp and body when
+	// TimestampHeader is set, otherwise to the body alone.
+	Secret          string
+	Header          string // default X-Signature
+	Algorithm       string // sha256 (default), sha1 or sha512
+	Encoding        string // hex (default) or base64
+	Prefix          string // e.g. "sha256="
+	TimestampHeader string
+	Parts           []string
+	Separator       string
+}
+
+// Signer types accepted by SignOptions.Type.
+const (
+	SignAWSSigV4 = "aws-sigv4"
+	SignHMAC     = "hmac"
+)
+
+// NewSigner returns the signer described by o, or nil when o.Type is empty.
+func NewSigner(o SignOptions) (Signer, error) {
+	switch strings.ToLower(o.Type) {
+	case "":
+		return nil, nil
+	case SignAWSSigV4, "sigv4", "aws":
+		return &sigV4Signer{region: o.Region, service: o.Service, profile: o.Profile, now: time.Now}, nil
+	case SignHMAC:
+		return newHMACSigner(o)
+	}
+	return nil, fmt.Errorf("unknown signer %q (supported: aws-sigv4, hmac)", o.Type)
+}
+
+type hmacSigner struct {
+	secret          []byte
+	header          string
+	newHash         func() hash.Hash
+	encoding        string
+	prefix          string
+	timestampHeader string
+	parts           []string
+	separator       string
+	now             func() time.Time
+}
+
+func newHMACSigner(o SignOptions) (*hmacSigner, error) {
+	secret, err := ResolveSecret(o.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		return nil, errors.New("hmac signing needs a secret")
+	}
+
+	s := &hmacSigner{
+		secret:          []byte(secret),
+		header:          o.Header,
+		encoding:        strings.ToLower(o.Encoding),
+		prefix:          o.Prefix,
+		timestampHeader: o.TimestampHeader,
+		parts:           o.Parts,
+		separator:       o.Separator,
+		now:             time.Now,
+	}
+	if s.header == "" {
+		s.header = "X-Signature"
+	}
+	switch strings.ToLower(o.Algorithm) {
+	case "", "sha256":
+		s.newHash = sha256.New
+	case "sha1":
+		s.newHash = sha1.New
+	case "sha512":
+		s.newHash = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported hmac algorithm %q (supported: sha256, sha1, sha512)", o.Algorithm)
+	}
+	if s.encoding == "" {
+		s.encoding = "hex"
+	}
+	if s.encoding != "hex" && s.encoding != "base64" {
+		return nil, fmt.Errorf("unsupported hmac encoding %q (supported: hex, base64)", o.Encoding)
+	}
+	if s.separator == "" {
+		s.separator = "\n"
+	}
+	if len(s.parts) == 0 {
+		s.parts = []string{"body"}
+		if s.timestampHeader != "" {
+			s.parts = []string{"timestamp", "body"}
+		}
+	}
+	for _, part := range s.parts {
+		switch {
+		case part == "timestamp" && s.timestampHeader == "":
+			return nil, errors.New("hmac signing of the timestamp needs a timestamp header")
+		case part == "body", part == "timestamp", part == "method", part == "path", part == "query", part == "url",
+			strings.HasPrefix(part, "header:"):
+		default:
+			return nil, fmt.Errorf("unknown hmac part %q (use body, timestamp, method, path, query, url or header:Name)", part)
+		}
+	}
+	return s, nil
+}
+
+func (s *hmacSigner) Sign(req *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	if s.timestampHeader != "" {
+		req.Header.Set(s.timestampHeader, timestamp)
+	}
+
+	mac := hmac.New(s.newHash, s.secret)
+	for i, part := range s.parts {
+		if i > 0 {
+			mac.Write([]byte(s.separator))
+		}
+		switch {
+		case part == "body":
+			mac.Write(body)
+		case part == "timestamp":
+			mac.Write([]byte(timestamp))
+		case part == "method":
+			mac.Write([]byte(req.Method))
+		case part == "path":
+			mac.Write([]byte(req.URL.EscapedPath()))
+		case part == "query":
+			mac.Write([]byte(req.URL.RawQuery))
+		case part == "url":
+			mac.Write([]byte(req.URL.String()))
+		default:
+			mac.Write([]byte(req.Header.Get(strings.TrimPrefix(part, "header:"))))
+		}
+	}
+
+	sum := mac.Sum(nil)
+	encoded := hex.EncodeToString(sum)
+	if s.encoding == "base64" {
+		encoded = base64.StdEncoding.EncodeToString(sum)
+	}
+	req.Header.Set(s.header, s.prefix+encoded)
+	return nil
+}
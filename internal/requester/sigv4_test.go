@@ -0,0 +1,182 @@
+package requester
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vectors from the AWS Signature Version 4 test suite, which signs with these example credentials
+// at 2015-08-30T12:36:00Z for the "service" service in us-east-1.
+const (
+	awsTestKeyID  = "AKIDEXAMPLE"
+	awsTestSecret = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+var awsTestTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+func setAWSTestCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", awsTestKeyID)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", awsTestSecret)
+	t.Setenv("AWS_SESSION_TOKEN", "")
+}
+
+func TestSigV4TestSuite(t *testing.T) {
+	setAWSTestCredentials(t)
+	tests := []struct {
+		name          string
+		method, url   string
+		contentType   string
+		body          string
+		region        string
+		service       string
+		signedHeaders string
+		signature     string
+	}{
+		{
+			name: "get-vanilla", method: "GET", url: "https://example.amazonaws.com/",
+			region: "us-east-1", service: "service", signedHeaders: "host;x-amz-date",
+			signature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name: "post-vanilla", method: "POST", url: "https://example.amazonaws.com/",
+			region: "us-east-1", service: "service", signedHeaders: "host;x-amz-date",
+			signature: "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name: "get-vanilla-query-order-key-case", method: "GET", url: "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			region: "us-east-1", service: "service", signedHeaders: "host;x-amz-date",
+			signature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name: "post-x-www-form-urlencoded", method: "POST", url: "https://example.amazonaws.com/",
+			contentType: "application/x-www-form-urlencoded", body: "Param1=value1",
+			region: "us-east-1", service: "service", signedHeaders: "content-type;host;x-amz-date",
+			signature: "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+		{
+			// The IAM ListUsers example from the SigV4 documentation; region and service come from the host.
+			name: "iam-list-users", method: "GET", url: "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8", signedHeaders: "content-type;host;x-amz-date",
+			signature: "5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			s := &sigV4Signer{region: tt.region, service: tt.service, now: func() time.Time { return awsTestTime }}
+			if err := s.Sign(req, []byte(tt.body)); err != nil {
+				t.Fatal(err)
+			}
+
+			region, service := tt.region, tt.service
+			if region == "" {
+				service, region = awsHostScope(req.URL.Hostname())
+			}
+			want := "AWS4-HMAC-SHA256 Credential=" + awsTestKeyID + "/20150830/" + region + "/" + service + "/aws4_request, " +
+				"SignedHeaders=" + tt.signedHeaders + ", Signature=" + tt.signature
+			if got := req.Header.Get("Authorization"); got != want {
+				t.Errorf("Authorization =\n  %s\nwant\n  %s", got, want)
+			}
+			if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+				t.Errorf("X-Amz-Date = %q", got)
+			}
+		})
+	}
+}
+
+func TestSigV4SessionTokenAndS3(t *testing.T) {
+	setAWSTestCredentials(t)
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+
+	req, _ := http.NewRequest("PUT", "https://bucket.s3.eu-west-1.amazonaws.com/a%20b.txt", nil)
+	s := &sigV4Signer{now: func() time.Time { return awsTestTime }}
+	if err := s.Sign(req, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	auth := req.Header.Get("Authorization")
+	for _, want := range []string{"/20150830/eu-west-1/s3/aws4_request", "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token"} {
+		if !strings.Contains(auth, want) {
+			t.Errorf("Authorization %q does not contain %q", auth, want)
+		}
+	}
+	if got := req.Header.Get("X-Amz-Content-Sha256"); got != sha256Hex([]byte("hello")) {
+		t.Errorf("X-Amz-Content-Sha256 = %q", got)
+	}
+	if got := req.Header.Get("X-Amz-Security-Token"); got != "session" {
+		t.Errorf("X-Amz-Security-Token = %q", got)
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	tests := []struct {
+		url, service, want string
+	}{
+		{"https://example.com", "service", "/"},
+		{"https://example.com/a b/c", "service", "/a%2520b/c"},
+		{"https://example.com/a b/c", "s3", "/a%20b/c"},
+		{"https://example.com/caf%C3%A9", "execute-api", "/caf%25C3%25A9"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		if got := canonicalURI(req, tt.service); got != tt.want {
+			t.Errorf("canonicalURI(%q, %s) = %q, want %q", tt.url, tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestAWSHostScope(t *testing.T) {
+	tests := []struct {
+		host, service, region string
+	}{
+		{"abc.execute-api.eu-west-1.amazonaws.com", "execute-api", "eu-west-1"},
+		{"dynamodb.us-east-2.amazonaws.com", "dynamodb", "us-east-2"},
+		{"iam.amazonaws.com", "iam", "us-east-1"},
+		{"example.com", "", ""},
+		{"amazonaws.com", "", ""},
+		{"eu-west-1.amazonaws.com", "", ""},
+	}
+	for _, tt := range tests {
+		service, region := awsHostScope(tt.host)
+		if service != tt.service || region != tt.region {
+			t.Errorf("awsHostScope(%q) = %q, %q, want %q, %q", tt.host, service, region, tt.service, tt.region)
+		}
+	}
+}
+
+func TestLoadAWSCredentialsProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	data := "[default]\naws_access_key_id = DEFAULTID\naws_secret_access_key = defaultsecret\n\n" +
+		"# comment\n[ci]\naws_access_key_id=CIID\naws_secret_access_key=cisecret\naws_session_token=citoken\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	tests := []struct {
+		profile string
+		want    awsCredentials
+		wantErr bool
+	}{
+		{"", awsCredentials{"DEFAULTID", "defaultsecret", ""}, false},
+		{"ci", awsCredentials{"CIID", "cisecret", "citoken"}, false},
+		{"missing", awsCredentials{}, true},
+	}
+	for _, tt := range tests {
+		got, err := loadAWSCredentials(tt.profile)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("loadAWSCredentials(%q) = %+v, %v, want %+v", tt.profile, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package requester
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event is one message of a streamed response: an SSE event, or a line of NDJSON/chunked text.
+type Event struct {
+	ID    string        `json:"id,omitempty"`
+	Type  string        `json:"type,omitempty"`
+	Data  string        `json:"data"`
+	At    time.Duration `json:"at"`
+	Delay time.Duration `json:"delay"`
+}
+
+// StreamOptions bounds a streamed request. Streams often never end on their own.
+type StreamOptions struct {
+	// MaxEvents stops reading after this many events; zero reads until the stream ends.
+	MaxEvents int
+	// MaxDuration stops reading after this long; zero falls back to the request timeout.
+	MaxDuration time.Duration
+}
+
+// StreamResult summarises a streamed response.
+type StreamResult struct {
+	StatusCode       int
+	Header           http.Header
+	Events           int
+	TimeToFirstEvent time.Duration
+	MaxGap           time.Duration
+	AvgGap           time.Duration
+	Duration         time.Duration
+	Phases           []Phase
+	// Truncated is set when reading stopped because of MaxEvents, MaxDuration or Request.MaxBody.
+	Truncated bool
+	// Attempts lists every try to connect, as for Response.Attempts.
+	Attempts []Attempt
+}
+
+// Stream sends the request and calls onEvent for each event as it arrives instead of buffering
+// the body. text/event-stream bodies are parsed as SSE; anything else is split into lines.
+// Connecting is retried per r.Retry, and reading stops after r.MaxBody decoded bytes.
+func Stream(r Request, opts StreamOptions, onEvent func(Event)) (*StreamResult, error) {
+	limit := opts.MaxDuration
+	if limit == 0 {
+		limit, _ = time.ParseDuration(r.Timeout)
+	}
+	ctx := context.Background()
+	if limit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limit)
+		defer cancel()
+	}
+
+	client, _, err := r.client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only connecting is retried: once the response arrives, events may already have been read.
+	policy := r.Retry.withDefaults()
+	var attempts []Attempt
+	var resp *http.Response
+	var rec *phaseRecorder
+	var start time.Time
+	for n := 1; ; n++ {
+		var req *http.Request
+		req, rec, err = r.build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/event-stream, application/x-ndjson, */*")
+
+		start = time.Now()
+		resp, err = client.Do(req)
+		attempt := Attempt{Number: n, Duration: time.Since(start)}
+		var header http.Header
+		if err != nil {
+			attempt.Error = err.Error()
+		} else {
+			attempt.StatusCode, header = resp.StatusCode, resp.Header
+		}
+		if n < policy.MaxAttempts {
+			if reason := policy.retryReason(&Response{StatusCode: attempt.StatusCode}, err); reason != "" {
+				if wait, ok := policy.delay(n, header); ok {
+					if resp != nil {
+						resp.Body.Close()
+					}
+					attempt.Retry, attempt.Wait = reason, wait
+					attempts = append(attempts, attempt)
+					time.Sleep(wait)
+					continue
+				}
+			}
+		}
+		attempts = append(attempts, attempt)
+		break
+	}
+	if err != nil {
+		return &StreamResult{Duration: time.Since(start), Phases: rec.finish(start, time.Now()), Attempts: attempts}, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	if r.HTTP != nil && r.HTTP.Jar != nil {
+		if err := r.HTTP.Jar.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save cookies: %w", err)
+		}
+	}
+
+	result := &StreamResult{StatusCode: resp.StatusCode, Header: resp.Header, Attempts: attempts}
+	var last time.Duration
+	var gaps time.Duration
+	emit := func(ev Event) bool {
+		ev.At = time.Since(start)
+		if result.Events == 0 {
+			result.TimeToFirstEvent = ev.At
+			ev.Delay = ev.At
+		} else {
+			ev.Delay = ev.At - last
+			gaps += ev.Delay
+			result.MaxGap = max(result.MaxGap, ev.Delay)
+		}
+		last = ev.At
+		result.Events++
+		if onEvent != nil {
+			onEvent(ev)
+		}
+		return opts.MaxEvents == 0 || result.Events < opts.MaxEvents
+	}
+
+	decoded, err := decompress(resp.Header.Get("Content-Encoding"), resp.Body)
+	if err != nil {
+		return result, err
+	}
+	defer decoded.Close()
+	resp.Body = decoded
+	if r.MaxBody > 0 {
+		resp.Body = io.NopCloser(&capReader{r: decoded, left: r.MaxBody})
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		err = readSSE(resp, emit)
+	} else {
+		err = readLines(resp, emit)
+	}
+
+	result.Duration = time.Since(start)
+	result.Phases = rec.finish(start, time.Now())
+	if result.Events > 1 {
+		result.AvgGap = gaps / time.Duration(result.Events-1)
+	}
+
+	switch {
+	case errors.Is(err, errStopped):
+		result.Truncated = true
+		return result, nil
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Truncated = true
+		return result, nil
+	case err != nil:
+		return result, fmt.Errorf("stream interrupted: %w", err)
+	}
+	return result, nil
+}
+
+// errStopped signals that the caller's limits were reached.
+var errStopped = errors.New("stream limit reached")
+
+// capReader stops a stream with errStopped once more than left bytes would be read.
+type capReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (c *capReader) Read(p []byte) (int, error) {
+	if c.left <= 0 {
+		// Distinguish a stream that ends exactly at the limit from one that goes on.
+		if n, err := c.r.Read(make([]byte, 1)); n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, errStopped
+	}
+	if int64(len(p)) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	return n, err
+}
+
+func newScanner(resp *http.Response) *bufio.Scanner {
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	return scanner
+}
+
+// readSSE parses the body per the Server-Sent Events format: fields until a blank line.
+func readSSE(resp *http.Response, emit func(Event) bool) error {
+	scanner := newScanner(resp)
+	var ev Event
+	var data []string
+	pending := false
+
+	// As the spec says, an event without data lines is discarded along with its type.
+	dispatch := func() bool {
+		if !pending {
+			ev.Type = ""
+			return true
+		}
+		ev.Data = strings.Join(data, "\n")
+		more := emit(ev)
+		ev, data, pending = Event{ID: ev.ID}, nil, false
+		return more
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if !dispatch() {
+				return errStopped
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment / keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+			pending = true
+		case "event":
+			ev.Type = value
+		case "id":
+			ev.ID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !dispatch() {
+		return errStopped
+	}
+	return nil
+}
+
+// readLines emits every non-empty line, which covers NDJSON and most chunked streaming APIs.
+func readLines(resp *http.Response, emit func(Event) bool) error {
+	scanner := newScanner(resp)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if !emit(Event{Data: line}) {
+			return errStopped
+		}
+	}
+	return scanner.Err()
+}
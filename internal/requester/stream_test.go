@@ -0,0 +1,152 @@
+package requester
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadSSE(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Event
+	}{
+		{"single event", "data: hello\n\n", []Event{{Data: "hello"}}},
+		{"no space after colon", "data:hello\n\n", []Event{{Data: "hello"}}},
+		{"multi-line data", "data: a\ndata: b\n\n", []Event{{Data: "a\nb"}}},
+		{"typed event", "event: tick\ndata: 1\n\n", []Event{{Type: "tick", Data: "1"}}},
+		{"crlf line endings", "event: tick\r\ndata: 1\r\n\r\n", []Event{{Type: "tick", Data: "1"}}},
+		{"empty data dispatched", "data\n\n", []Event{{Data: ""}}},
+		{"event without data discarded", "event: ping\n\ndata: x\n\n", []Event{{Data: "x"}}},
+		{"comments ignored", ": keep-alive\n\n: again\ndata: x\n\n", []Event{{Data: "x"}}},
+		{"id carried to later events", "id: 7\ndata: a\n\ndata: b\n\n", []Event{{ID: "7", Data: "a"}, {ID: "7", Data: "b"}}},
+		{"unknown fields ignored", "retry: 1000\nfoo: bar\ndata: x\n\n", []Event{{Data: "x"}}},
+		{"last event without blank line", "data: a\n\ndata: b", []Event{{Data: "a"}, {Data: "b"}}},
+		{"only comments", ": nothing here\n\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Event
+			resp := &http.Response{Body: io.NopCloser(strings.NewReader(tt.body))}
+			err := readSSE(resp, func(ev Event) bool {
+				got = append(got, ev)
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	var got []string
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader("{\"a\":1}\r\n\n{\"b\":2}\n{\"c\":3}"))}
+	err := readLines(resp, func(ev Event) bool {
+		got = append(got, ev.Data)
+		return len(got) < 2
+	})
+	if err != errStopped {
+		t.Errorf("err = %v, want errStopped once the callback declines", err)
+	}
+	if want := []string{`{"a":1}`, `{"b":2}`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestStream(t *testing.T) {
+	sse := "event: tick\ndata: 1\n\nevent: tick\ndata: 2\n\nevent: tick\ndata: 3\n\n"
+	tests := []struct {
+		name          string
+		contentType   string
+		encoding      string
+		body          string
+		failFirst     int // status of the first response, 0 for none
+		retry         RetryPolicy
+		maxEvents     int
+		maxBody       int64
+		wantData      []string
+		wantAttempts  int
+		wantTruncated bool
+	}{
+		{"sse", "text/event-stream", "", sse, 0, RetryPolicy{}, 0, 0, []string{"1", "2", "3"}, 1, false},
+		{"ndjson", "application/x-ndjson", "", "{\"n\":1}\n{\"n\":2}\n", 0, RetryPolicy{}, 0, 0, []string{`{"n":1}`, `{"n":2}`}, 1, false},
+		{"gzip", "text/event-stream", "gzip", sse, 0, RetryPolicy{}, 0, 0, []string{"1", "2", "3"}, 1, false},
+		{"max events", "text/event-stream", "", sse, 0, RetryPolicy{}, 2, 0, []string{"1", "2"}, 1, true},
+		{"max body", "text/event-stream", "", sse, 0, RetryPolicy{}, 0, int64(len("event: tick\ndata: 1\n\nevent: tick\n")), []string{"1"}, 1, true},
+		{"max body at end", "text/event-stream", "", sse, 0, RetryPolicy{}, 0, int64(len(sse)), []string{"1", "2", "3"}, 1, false},
+		{"retried connect", "text/event-stream", "", sse, 503, RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}, 0, 0, []string{"1", "2", "3"}, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 && tt.failFirst != 0 {
+					w.WriteHeader(tt.failFirst)
+					return
+				}
+				w.Header().Set("Content-Type", tt.contentType)
+				body := []byte(tt.body)
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+					body = encode(t, tt.encoding, body)
+				}
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			var got []string
+			result, err := Stream(Request{URL: srv.URL, Method: http.MethodGet, Timeout: "5s", Retry: tt.retry, MaxBody: tt.maxBody},
+				StreamOptions{MaxEvents: tt.maxEvents}, func(ev Event) { got = append(got, ev.Data) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.wantData) {
+				t.Errorf("events = %q, want %q", got, tt.wantData)
+			}
+			if result.StatusCode != http.StatusOK || result.Events != len(tt.wantData) {
+				t.Errorf("status = %d, events = %d", result.StatusCode, result.Events)
+			}
+			if len(result.Attempts) != tt.wantAttempts || int(calls.Load()) != tt.wantAttempts {
+				t.Errorf("attempts = %d (server saw %d), want %d", len(result.Attempts), calls.Load(), tt.wantAttempts)
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", result.Truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestStreamTimingAndTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := range 3 {
+			fmt.Fprintf(w, "data: %d\n\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+		<-r.Context().Done() // never ends on its own
+	}))
+	defer srv.Close()
+
+	result, err := Stream(Request{URL: srv.URL, Method: http.MethodGet}, StreamOptions{MaxDuration: 300 * time.Millisecond}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Events != 3 || !result.Truncated {
+		t.Errorf("events = %d, truncated = %v; want 3, true", result.Events, result.Truncated)
+	}
+	if result.MaxGap < 20*time.Millisecond || result.AvgGap > result.MaxGap {
+		t.Errorf("gaps: first %s, max %s, avg %s", result.TimeToFirstEvent, result.MaxGap, result.AvgGap)
+	}
+}
@@ -0,0 +1,152 @@
+package requester
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// TLSOptions tunes the TLS client used for a request.
+type TLSOptions struct {
+	// CertFile and KeyFile are a PEM client certificate and key; PKCS12File is the alternative
+	// .p12/.pfx bundle, unlocked with PKCS12Password (which may be env:NAME or file:path).
+	CertFile       string
+	KeyFile        string
+	PKCS12File     string
+	PKCS12Password string
+
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+	// ServerName overrides the SNI and the name the certificate is verified against.
+	ServerName string
+
+	// MinVersion and MaxVersion are "1.0" to "1.3".
+	MinVersion string
+	MaxVersion string
+	// CipherSuites restricts TLS 1.2 and earlier to these suites (TLS 1.3 suites are not configurable in Go).
+	CipherSuites []string
+
+	// Insecure skips certificate verification. Callers must surface it wherever results are shown.
+	Insecure bool
+}
+
+// IsZero reports whether the options leave Go's TLS defaults alone.
+func (o *TLSOptions) IsZero() bool {
+	return o == nil || (o.CertFile == "" && o.PKCS12File == "" && o.CAFile == "" && o.ServerName == "" &&
+		o.MinVersion == "" && o.MaxVersion == "" && len(o.CipherSuites) == 0 && !o.Insecure)
+}
+
+// Config builds the tls.Config described by the options.
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: o.ServerName, InsecureSkipVerify: o.Insecure}
+
+	switch {
+	case o.PKCS12File != "":
+		cert, err := loadPKCS12(o.PKCS12File, o.PKCS12Password)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case o.CertFile != "":
+		keyFile := o.KeyFile
+		if keyFile == "" {
+			keyFile = o.CertFile // key and certificate in one PEM file
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case o.KeyFile != "":
+		return nil, errors.New("a client key needs a client certificate")
+	}
+
+	if o.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	var err error
+	if cfg.MinVersion, err = ParseTLSVersion(o.MinVersion); err != nil {
+		return nil, err
+	}
+	if cfg.MaxVersion, err = ParseTLSVersion(o.MaxVersion); err != nil {
+		return nil, err
+	}
+	if cfg.MinVersion != 0 && cfg.MaxVersion != 0 && cfg.MinVersion > cfg.MaxVersion {
+		return nil, errors.New("minimum TLS version is above the maximum")
+	}
+
+	for _, name := range o.CipherSuites {
+		id, ok := cipherSuiteID(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		cfg.CipherSuites = append(cfg.CipherSuites, id)
+	}
+	return cfg, nil
+}
+
+// ParseTLSVersion maps "1.0" to "1.3" (optionally prefixed with "TLS") to a tls.Version constant; "" is 0.
+func ParseTLSVersion(v string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(v)), "TLS") {
+	case "":
+		return 0, nil
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q (use 1.0, 1.1, 1.2 or 1.3)", v)
+}
+
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, list := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, suite := range list {
+			if strings.EqualFold(suite.Name, name) {
+				return suite.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func loadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read PKCS#12 file: %w", err)
+	}
+	password, err = ResolveSecret(password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to decode PKCS#12 file: %w", err)
+	}
+
+	cert := tls.Certificate{PrivateKey: key, Leaf: leaf, Certificate: [][]byte{leaf.Raw}}
+	for _, c := range chain {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert, nil
+}
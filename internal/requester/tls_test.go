@@ -0,0 +1,164 @@
+package requester
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// clientCerts issues a client certificate for "pingify-client" from a new CA and writes it as
+// cert.pem and key.pem, combined.pem (both in one file) and client.p12 (password "p12-pass").
+func clientCerts(t *testing.T) (string, *x509.CertPool) {
+	t.Helper()
+	dir := t.TempDir()
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	caKey, clientKey := newKey(), newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "pingify test CA"},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour),
+		IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "pingify-client"},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour),
+		KeyUsage: x509.KeyUsageDigitalSignature, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, ca, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _ := x509.ParseCertificate(clientDER)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	p12, err := pkcs12.Modern.Encode(clientKey, client, []*x509.Certificate{ca}, "p12-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		"cert.pem": certPEM, "key.pem": keyPEM, "combined.pem": append(certPEM, keyPEM...), "client.p12": p12,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return dir, pool
+}
+
+func TestClientCertificates(t *testing.T) {
+	dir, clientCAs := clientCerts(t)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+	caFile := writeCA(t, srv)
+	t.Setenv("PINGIFY_TEST_P12_PASS", "p12-pass")
+
+	tests := []struct {
+		name    string
+		opts    TLSOptions
+		errText string // "" for a request that reaches the handler
+	}{
+		{"pem certificate and key", TLSOptions{CertFile: filepath.Join(dir, "cert.pem"), KeyFile: filepath.Join(dir, "key.pem")}, ""},
+		{"pem certificate with key", TLSOptions{CertFile: filepath.Join(dir, "combined.pem")}, ""},
+		{"pkcs12", TLSOptions{PKCS12File: filepath.Join(dir, "client.p12"), PKCS12Password: "p12-pass"}, ""},
+		{"pkcs12 password from env", TLSOptions{PKCS12File: filepath.Join(dir, "client.p12"), PKCS12Password: "env:PINGIFY_TEST_P12_PASS"}, ""},
+		{"no client certificate", TLSOptions{}, "certificate"},
+		{"pkcs12 wrong password", TLSOptions{PKCS12File: filepath.Join(dir, "client.p12"), PKCS12Password: "nope"}, "failed to decode PKCS#12 file"},
+		{"pkcs12 missing", TLSOptions{PKCS12File: filepath.Join(dir, "missing.p12")}, "failed to read PKCS#12 file"},
+		{"key without certificate", TLSOptions{KeyFile: filepath.Join(dir, "key.pem")}, "needs a client certificate"},
+		{"certificate without key", TLSOptions{CertFile: filepath.Join(dir, "cert.pem")}, "failed to load client certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.CAFile = caFile
+			resp, err := Do(Request{URL: srv.URL, Method: http.MethodGet, Timeout: "5s", TLS: &opts})
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Body != "pingify-client" {
+				t.Errorf("server saw client %q, want pingify-client", resp.Body)
+			}
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    TLSOptions
+		check   func(*tls.Config) bool
+		errText string
+	}{
+		{"versions", TLSOptions{MinVersion: "1.2", MaxVersion: "TLS1.3"}, func(c *tls.Config) bool {
+			return c.MinVersion == tls.VersionTLS12 && c.MaxVersion == tls.VersionTLS13
+		}, ""},
+		{"cipher suites", TLSOptions{CipherSuites: []string{"tls_ecdhe_rsa_with_aes_128_gcm_sha256"}}, func(c *tls.Config) bool {
+			return len(c.CipherSuites) == 1 && c.CipherSuites[0] == tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+		}, ""},
+		{"server name and insecure", TLSOptions{ServerName: "api.test", Insecure: true}, func(c *tls.Config) bool {
+			return c.ServerName == "api.test" && c.InsecureSkipVerify
+		}, ""},
+		{"unknown version", TLSOptions{MinVersion: "1.4"}, nil, "unknown TLS version"},
+		{"min above max", TLSOptions{MinVersion: "1.3", MaxVersion: "1.2"}, nil, "above the maximum"},
+		{"unknown cipher suite", TLSOptions{CipherSuites: []string{"TLS_NOPE"}}, nil, "unknown cipher suite"},
+		{"ca bundle without certificates", TLSOptions{CAFile: "tls_test.go"}, nil, "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.opts.Config()
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Errorf("err = %v, want it to mention %q", err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("config = %+v", cfg)
+			}
+		})
+	}
+}
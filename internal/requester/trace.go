@@ -0,0 +1,89 @@
+package requester
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Phase is one timed step of an HTTP call as reported by httptrace.
+type Phase struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration returns how long the phase took.
+func (p Phase) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// phaseRecorder collects httptrace callbacks; the transport may invoke them from other goroutines.
+type phaseRecorder struct {
+	mu           sync.Mutex
+	hopStart     time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+func (r *phaseRecorder) mark(t *time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t.IsZero() {
+		*t = time.Now()
+	}
+}
+
+// reset starts recording afresh for a redirect sent at t, so the phases describe the final hop.
+func (r *phaseRecorder) reset(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hopStart = t
+	r.dnsStart, r.dnsDone, r.connectStart, r.connectDone = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	r.tlsStart, r.tlsDone, r.wroteRequest, r.firstByte = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+}
+
+func (r *phaseRecorder) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { r.mark(&r.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { r.mark(&r.dnsDone) },
+		ConnectStart:         func(string, string) { r.mark(&r.connectStart) },
+		ConnectDone:          func(string, string, error) { r.mark(&r.connectDone) },
+		TLSHandshakeStart:    func() { r.mark(&r.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { r.mark(&r.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { r.mark(&r.wroteRequest) },
+		GotFirstResponseByte: func() { r.mark(&r.firstByte) },
+	}
+}
+
+// finish turns the recorded timestamps into the ordered list of phases that completed. After
+// redirects, sending is measured from the start of the final hop.
+func (r *phaseRecorder) finish(start, end time.Time) []Phase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.hopStart.IsZero() {
+		start = r.hopStart
+	}
+
+	var phases []Phase
+	add := func(name string, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			phases = append(phases, Phase{Name: name, Start: from, End: to})
+		}
+	}
+
+	add("dns", r.dnsStart, r.dnsDone)
+	add("connect", r.connectStart, r.connectDone)
+	add("tls", r.tlsStart, r.tlsDone)
+	add("send", start, r.wroteRequest)
+	add("wait", r.wroteRequest, r.firstByte)
+	add("receive", r.firstByte, end)
+	return phases
+}
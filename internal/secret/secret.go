@@ -0,0 +1,130 @@
+// Package secret keeps track of secret values, such as tokens read from env:NAME or file:path
+// references and environment secrets, so they can be masked wherever Pingify writes them.
+package secret
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Masked replaces secret values in output.
+const Masked = "****"
+
+// minLength keeps very short values, which would mask unrelated text, from being registered.
+const minLength = 4
+
+var (
+	mu     sync.RWMutex
+	values []string
+)
+
+// Register adds values to mask from now on.
+func Register(secrets ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range secrets {
+		if len(s) < minLength || contains(values, s) {
+			continue
+		}
+		values = append(values, s)
+	}
+	// Longer values first, so a secret containing another is masked whole.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask replaces every registered value in s.
+func Mask(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Masked)
+	}
+	return s
+}
+
+// MaskBytes is Mask for file contents.
+func MaskBytes(data []byte) []byte {
+	mu.RLock()
+	empty := len(values) == 0
+	mu.RUnlock()
+	if empty {
+		return data
+	}
+	return []byte(Mask(string(data)))
+}
+
+// Writer masks secrets in what is written through it. When a write ends with the start of a
+// secret, those bytes are held back until the next write or Flush, so a secret split across
+// writes is still masked.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending string
+}
+
+// NewWriter returns a Writer that masks secrets on their way to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Stdout and Stderr are where Pingify prints; use them instead of os.Stdout and os.Stderr.
+var (
+	Stdout = NewWriter(os.Stdout)
+	Stderr = NewWriter(os.Stderr)
+)
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s := Mask(w.pending + string(p))
+	hold := partialSuffix(s)
+	w.pending = s[len(s)-hold:]
+	if _, err := io.WriteString(w.w, s[:len(s)-hold]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush prints what is held back.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s := Mask(w.pending)
+	w.pending = ""
+	_, err := io.WriteString(w.w, s)
+	return err
+}
+
+// Flush prints what Stdout and Stderr hold back; call it before exiting.
+func Flush() {
+	Stdout.Flush()
+	Stderr.Flush()
+}
+
+// partialSuffix returns the length of the longest end of s that a secret starts with.
+func partialSuffix(s string) int {
+	mu.RLock()
+	defer mu.RUnlock()
+	longest := 0
+	for _, v := range values {
+		for n := min(len(v)-1, len(s)); n > longest; n-- {
+			if strings.HasSuffix(s, v[:n]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriterMasksAcrossWrites(t *testing.T) {
+	Register("s3cr3t-token", "hunter2!")
+
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"whole", []string{"token=s3cr3t-token\n"}, "token=****\n"},
+		{"split", []string{"token=s3c", "r3t-tok", "en\n"}, "token=****\n"},
+		{"split at every byte", strings.Split("a hunter2! b", ""), "a **** b"},
+		{"prefix only", []string{"s3cr3t", " is not the token"}, "s3cr3t is not the token"},
+		{"prefix at end", []string{"ends with hunt"}, "ends with hunt"},
+		{"no secrets", []string{"plain ", "text"}, "plain text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			w := NewWriter(&out)
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				if err != nil || n != len(s) {
+					t.Fatalf("Write(%q) = %d, %v", s, n, err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterHoldsBackOnlyPartialSecrets(t *testing.T) {
+	Register("s3cr3t-token")
+	var out strings.Builder
+	w := NewWriter(&out)
+	w.Write([]byte("line\nnext s3cr"))
+	if got := out.String(); got != "line\nnext " {
+		t.Errorf("before flush got %q", got)
+	}
+	w.Write([]byte("ed"))
+	if got := out.String(); got != "line\nnext s3cred" {
+		t.Errorf("after a non-matching write got %q", got)
+	}
+}
+
+func TestRegisterIgnoresShortValues(t *testing.T) {
+	Register("abc")
+	if got := Mask("abc"); got != "abc" {
+		t.Errorf("Mask(%q) = %q", "abc", got)
+	}
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/monitor"
+	"github.com/Aditya251610/pingify/internal/report"
+)
+
+// errNotFound is returned by config updates that reference an unknown check.
+var errNotFound = errors.New("check not found")
+
+// errUnauthorized is returned for changes made without the API token.
+var errUnauthorized = errors.New("missing or invalid API token")
+
+// CheckStatus is a configured check, with its credentials redacted, together with its latest runtime state.
+type CheckStatus struct {
+	config.Check
+	Failing    bool                `json:"failing"`
+	LastResult *monitor.MonitorLog `json:"last_result,omitempty"`
+}
+
+// Handler returns the REST API:
+//
+//	GET    /api/checks                 list checks and their state
+//	POST   /api/checks                 create a check
+//	GET    /api/checks/{name}          show one check
+//	PUT    /api/checks/{name}          replace a check
+//	DELETE /api/checks/{name}          delete a check
+//	POST   /api/checks/{name}/pause    stop scheduling a check
+//	POST   /api/checks/{name}/resume   resume a paused check
+//	POST   /api/checks/{name}/run      run a check immediately
+//	GET    /api/checks/{name}/results  logged results (?limit=N for the most recent N)
+//	GET    /api/checks/{name}/report   summary report, as computed by `pingify report`
+//	GET    /api/heartbeats             list heartbeats and their state
+//	GET    /api/status                 status page data
+//	POST   /api/status/incidents       post a manual incident note
+//	GET    /status                     rendered status page
+//	*      /ping/{token}               heartbeat ping from a job (/start and /fail variants)
+//
+// Every /api route except GET /api/status needs the token set with RequireToken, and checks are
+// returned with their credentials redacted.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/checks", s.authorized(s.listChecks))
+	mux.HandleFunc("POST /api/checks", s.authorized(s.createCheck))
+	mux.HandleFunc("GET /api/checks/{name}", s.authorized(s.getCheck))
+	mux.HandleFunc("PUT /api/checks/{name}", s.authorized(s.updateCheck))
+	mux.HandleFunc("DELETE /api/checks/{name}", s.authorized(s.deleteCheck))
+	mux.HandleFunc("POST /api/checks/{name}/pause", s.authorized(s.setPaused(true)))
+	mux.HandleFunc("POST /api/checks/{name}/resume", s.authorized(s.setPaused(false)))
+	mux.HandleFunc("POST /api/checks/{name}/run", s.authorized(s.runCheck))
+	mux.HandleFunc("GET /api/checks/{name}/results", s.authorized(s.checkResults))
+	mux.HandleFunc("GET /api/checks/{name}/report", s.authorized(s.checkReport))
+	mux.HandleFunc("GET /api/heartbeats", s.authorized(s.listHeartbeats))
+	mux.HandleFunc("GET /api/status", s.statusData)
+	mux.HandleFunc("POST /api/status/incidents", s.authorized(s.postIncident))
+	mux.HandleFunc("GET /status", s.statusPage)
+	mux.HandleFunc("/ping/{token}", s.handlePing(""))
+	mux.HandleFunc("/ping/{token}/start", s.handlePing("start"))
+	mux.HandleFunc("/ping/{token}/fail", s.handlePing("fail"))
+	return mux
+}
+
+// authorized rejects requests without the API token, when one is required.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pingify"`)
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeUpdateError maps config update failures to HTTP status codes.
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeError(w, http.StatusBadRequest, err)
+}
+
+func (s *Server) status(check config.Check) CheckStatus {
+	st := CheckStatus{Check: check.WithDefaults().Redacted()}
+	if r, ok := s.runners[check.Name]; ok {
+		st.LastResult, st.Failing = r.state()
+	}
+	return st
+}
+
+// lookup returns the named check. Callers must not hold s.mu.
+func (s *Server) lookup(name string) (config.Check, *runner, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.cfg.Find(name)
+	if i < 0 {
+		return config.Check{}, nil, false
+	}
+	return s.cfg.Checks[i], s.runners[name], true
+}
+
+func (s *Server) listChecks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	checks := make([]CheckStatus, 0, len(s.cfg.Checks))
+	for _, check := range s.cfg.Checks {
+		checks = append(checks, s.status(check))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, checks)
+}
+
+func (s *Server) getCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.cfg.Find(name)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.status(s.cfg.Checks[i]))
+}
+
+func decodeCheck(r *http.Request) (config.Check, error) {
+	var check config.Check
+	if err := json.NewDecoder(r.Body).Decode(&check); err != nil {
+		return check, fmt.Errorf("invalid check payload: %w", err)
+	}
+	return check, nil
+}
+
+func (s *Server) createCheck(w http.ResponseWriter, r *http.Request) {
+	check, err := decodeCheck(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.update(func(cfg *config.Config) error {
+		if cfg.Find(check.Name) >= 0 {
+			return fmt.Errorf("check %q already exists", check.Name)
+		}
+		cfg.Checks = append(cfg.Checks, check)
+		return nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, check.WithDefaults().Redacted())
+}
+
+func (s *Server) updateCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	check, err := decodeCheck(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if check.Name == "" {
+		check.Name = name
+	}
+
+	err = s.update(func(cfg *config.Config) error {
+		i := cfg.Find(name)
+		if i < 0 {
+			return errNotFound
+		}
+		cfg.Checks[i] = check
+		return nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, check.WithDefaults().Redacted())
+}
+
+func (s *Server) deleteCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := s.update(func(cfg *config.Config) error {
+		i := cfg.Find(name)
+		if i < 0 {
+			return errNotFound
+		}
+		cfg.Checks = append(cfg.Checks[:i], cfg.Checks[i+1:]...)
+		return nil
+	})
+	if err != nil {
+		writeUpdateError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setPaused(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		var updated config.Check
+		err := s.update(func(cfg *config.Config) error {
+			i := cfg.Find(name)
+			if i < 0 {
+				return errNotFound
+			}
+			cfg.Checks[i].Paused = paused
+			updated = cfg.Checks[i]
+			return nil
+		})
+		if err != nil {
+			writeUpdateError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, updated.WithDefaults().Redacted())
+	}
+}
+
+func (s *Server) runCheck(w http.ResponseWriter, r *http.Request) {
+	_, runner, ok := s.lookup(r.PathValue("name"))
+	if !ok || runner == nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, runner.run())
+}
+
+func (s *Server) checkResults(w http.ResponseWriter, r *http.Request) {
+	check, _, ok := s.lookup(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	logs, err := monitor.ReadLogs(check.LogKey())
+	if errors.Is(err, os.ErrNotExist) {
+		logs = []monitor.MonitorLog{}
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit >= 0 && limit < len(logs) {
+		logs = logs[len(logs)-limit:]
+	}
+	writeJSON(w, http.StatusOK, logs)
+}
+
+func (s *Server) checkReport(w http.ResponseWriter, r *http.Request) {
+	check, _, ok := s.lookup(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	summary, err := report.GenerateReport(check.LogKey())
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
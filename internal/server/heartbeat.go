@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/monitor"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/status"
+)
+
+// heartbeatCheckInterval is how often heartbeats are checked for missed pings.
+const heartbeatCheckInterval = 5 * time.Second
+
+// heartbeat tracks the pings received for one configured push check.
+type heartbeat struct {
+	mu       sync.Mutex
+	cfg      config.Heartbeat
+	lastPing time.Time
+	started  time.Time
+	watching time.Time
+	down     bool // the job reported a failure
+	missed   bool // the deadline passed without a ping
+}
+
+// sendAlert delivers heartbeat alerts; tests replace it.
+var sendAlert = monitor.SendEmailAlert
+
+// HeartbeatStatus is a configured heartbeat together with its latest runtime state.
+type HeartbeatStatus struct {
+	Name     string     `json:"name"`
+	Period   string     `json:"period"`
+	Grace    string     `json:"grace"`
+	LastPing *time.Time `json:"last_ping,omitempty"`
+	Running  bool       `json:"running"`
+	Down     bool       `json:"down"`
+	Missed   bool       `json:"missed"`
+	Deadline time.Time  `json:"deadline"`
+}
+
+// newHeartbeat restores the last ping from the heartbeat's log so a daemon restart doesn't reset it.
+func newHeartbeat(cfg config.Heartbeat) *heartbeat {
+	hb := &heartbeat{cfg: cfg, watching: time.Now()}
+	if logs, err := monitor.ReadLogs(cfg.LogURL()); err == nil {
+		for i := len(logs) - 1; i >= 0; i-- {
+			if logs[i].Method != "MISSED" {
+				hb.lastPing = logs[i].Timestamp
+				break
+			}
+		}
+		if n := len(logs); n > 0 {
+			hb.missed = logs[n-1].Method == "MISSED"
+			hb.down = logs[n-1].Method == "FAIL" || (hb.missed && n > 1 && logs[n-2].Method == "FAIL")
+		}
+	}
+	return hb
+}
+
+// deadline is when the heartbeat is considered missing. Callers must hold hb.mu.
+func (hb *heartbeat) deadline() time.Time {
+	from := hb.lastPing
+	if from.IsZero() {
+		from = hb.watching
+	}
+	return from.Add(hb.cfg.Period.Std() + hb.cfg.Grace.Std())
+}
+
+func (hb *heartbeat) status() HeartbeatStatus {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	st := HeartbeatStatus{
+		Name:     hb.cfg.Name,
+		Period:   hb.cfg.Period.String(),
+		Grace:    hb.cfg.Grace.String(),
+		Running:  !hb.started.IsZero(),
+		Down:     hb.down || hb.missed,
+		Missed:   hb.missed,
+		Deadline: hb.deadline(),
+	}
+	if !hb.lastPing.IsZero() {
+		last := hb.lastPing
+		st.LastPing = &last
+	}
+	return st
+}
+
+// ping records a job signal: "start" marks the beginning of a run, "fail" and "" end it. A ping
+// that comes later than the period alerts, and so does a failure or a recovery.
+func (hb *heartbeat) ping(kind string) {
+	now := time.Now()
+
+	hb.mu.Lock()
+	cfg := hb.cfg
+	if kind == "start" {
+		hb.started = now
+		hb.mu.Unlock()
+		return
+	}
+
+	var runDuration time.Duration
+	if !hb.started.IsZero() {
+		runDuration = now.Sub(hb.started)
+	}
+	var since time.Duration
+	if !hb.lastPing.IsZero() {
+		since = now.Sub(hb.lastPing)
+	}
+	late := since > cfg.Period.Std()
+	failed := kind == "fail"
+	wasDown, wasMissed := hb.down, hb.missed
+
+	hb.lastPing = now
+	hb.started = time.Time{}
+	hb.down = failed
+	hb.missed = false
+	hb.mu.Unlock()
+
+	log := monitor.MonitorLog{
+		Timestamp:     now,
+		URL:           cfg.LogURL(),
+		Method:        "PING",
+		StatusText:    "OK",
+		ExecutionTime: runDuration,
+		Threshold:     cfg.Period.Std(),
+		Exceeded:      late,
+		Success:       !failed,
+	}
+	if late {
+		log.StatusText = "Late"
+	}
+	if failed {
+		log.Method = "FAIL"
+		log.StatusText = "Failed"
+		log.Error = "job reported failure"
+	}
+	if err := monitor.AppendLog(cfg.LogURL(), log); err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Failed to write heartbeat log:", err)
+	}
+
+	switch {
+	case failed && !wasDown:
+		fmt.Fprintf(secret.Stdout, "❌ [%s] Job reported failure\n", cfg.Name)
+		recordTransition(cfg, now, true, "job reported failure")
+		alert(cfg, fmt.Sprintf("⚠️ Heartbeat %q: the job reported a failure.", cfg.Name))
+	case !failed && (wasDown || wasMissed):
+		fmt.Fprintf(secret.Stdout, "✅ [%s] Heartbeat recovered\n", cfg.Name)
+		recordTransition(cfg, now, false, "")
+		alert(cfg, fmt.Sprintf("✅ Heartbeat %q recovered.", cfg.Name))
+	case late:
+		fmt.Fprintf(secret.Stdout, "⏰ [%s] Heartbeat late: %s since the last ping\n", cfg.Name, since.Round(time.Second))
+		alert(cfg, fmt.Sprintf("⚠️ Heartbeat %q pinged late: %s since the last ping (period %s).", cfg.Name, since.Round(time.Second), cfg.Period))
+	}
+}
+
+// checkMissed logs and alerts once when the deadline passes without a ping, whether or not the
+// job last reported a failure.
+func (hb *heartbeat) checkMissed(now time.Time) {
+	hb.mu.Lock()
+	cfg := hb.cfg
+	deadline := hb.deadline()
+	if hb.missed || now.Before(deadline) {
+		hb.mu.Unlock()
+		return
+	}
+	hb.missed = true
+	hb.mu.Unlock()
+
+	message := fmt.Sprintf("no ping since %s (period %s, grace %s)", deadline.Add(-cfg.Period.Std()-cfg.Grace.Std()).Format(time.RFC1123), cfg.Period, cfg.Grace)
+	if err := monitor.AppendLog(cfg.LogURL(), monitor.MonitorLog{
+		Timestamp:  now,
+		URL:        cfg.LogURL(),
+		Method:     "MISSED",
+		StatusText: "Missed",
+		Threshold:  cfg.Period.Std(),
+		Exceeded:   true,
+		Error:      message,
+	}); err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Failed to write heartbeat log:", err)
+	}
+
+	fmt.Fprintf(secret.Stdout, "🚨 [%s] Heartbeat missed: %s\n", cfg.Name, message)
+	recordTransition(cfg, now, true, message)
+	alert(cfg, fmt.Sprintf("⚠️ Heartbeat %q is missing: %s.", cfg.Name, message))
+}
+
+// recordTransition records the heartbeat going down or recovering for the status page.
+func recordTransition(cfg config.Heartbeat, at time.Time, failing bool, message string) {
+	t := status.Transition{Time: at, Check: cfg.Name, Failing: failing, Message: message}
+	if err := status.RecordTransition(t); err != nil {
+		fmt.Fprintln(secret.Stdout, "❌ Failed to record status transition:", err)
+	}
+}
+
+func alert(cfg config.Heartbeat, message string) {
+	if cfg.Alert {
+		sendAlert(message, cfg.Email)
+	}
+}
+
+// reconcileHeartbeats matches the tracked heartbeats to s.cfg, keeping state for unchanged names.
+// Callers must hold s.mu.
+func (s *Server) reconcileHeartbeats() {
+	byName := map[string]*heartbeat{}
+	for _, hb := range s.heartbeats {
+		hb.mu.Lock()
+		byName[hb.cfg.Name] = hb
+		hb.mu.Unlock()
+	}
+
+	next := map[string]*heartbeat{}
+	for _, cfg := range s.cfg.Heartbeats {
+		hb, ok := byName[cfg.Name]
+		if !ok {
+			hb = newHeartbeat(cfg)
+			fmt.Fprintf(secret.Stdout, "💓 Watching heartbeat %q (every %s, grace %s)\n", cfg.Name, cfg.Period, cfg.Grace)
+		} else {
+			hb.mu.Lock()
+			hb.cfg = cfg
+			hb.mu.Unlock()
+		}
+		next[cfg.Token] = hb
+	}
+	s.heartbeats = next
+}
+
+// watchHeartbeats periodically flags heartbeats whose deadline has passed.
+func (s *Server) watchHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			hbs := make([]*heartbeat, 0, len(s.heartbeats))
+			for _, hb := range s.heartbeats {
+				hbs = append(hbs, hb)
+			}
+			s.mu.Unlock()
+
+			for _, hb := range hbs {
+				hb.checkMissed(now)
+			}
+		}
+	}
+}
+
+func (s *Server) handlePing(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		hb, ok := s.heartbeats[r.PathValue("token")]
+		s.mu.Unlock()
+		if !ok {
+			http.Error(w, "unknown heartbeat", http.StatusNotFound)
+			return
+		}
+
+		hb.ping(kind)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("OK\n"))
+	}
+}
+
+func (s *Server) listHeartbeats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	statuses := make([]HeartbeatStatus, 0, len(s.cfg.Heartbeats))
+	for _, cfg := range s.cfg.Heartbeats {
+		if hb, ok := s.heartbeats[cfg.Token]; ok {
+			statuses = append(statuses, hb.status())
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, statuses)
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+)
+
+// recordAlerts replaces sendAlert for the test and returns the alerts sent so far.
+func recordAlerts(t *testing.T) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var alerts []string
+	orig := sendAlert
+	sendAlert = func(message, email string) {
+		mu.Lock()
+		defer mu.Unlock()
+		alerts = append(alerts, message)
+	}
+	t.Cleanup(func() { sendAlert = orig })
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		out := alerts
+		alerts = nil
+		return out
+	}
+}
+
+func testHeartbeat(t *testing.T) *heartbeat {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	return newHeartbeat(config.Heartbeat{
+		Name:   "backup",
+		Token:  "tok",
+		Period: config.Duration(time.Minute),
+		Grace:  config.Duration(10 * time.Second),
+		Alert:  true,
+	})
+}
+
+func TestHeartbeatAlerts(t *testing.T) {
+	period := time.Minute
+	tests := []struct {
+		name  string
+		steps func(hb *heartbeat)
+		want  []string
+	}{
+		{
+			name:  "on time",
+			steps: func(hb *heartbeat) { hb.ping("") },
+		},
+		{
+			name: "late ping",
+			steps: func(hb *heartbeat) {
+				hb.lastPing = time.Now().Add(-2 * period)
+				hb.ping("")
+			},
+			want: []string{"pinged late"},
+		},
+		{
+			name: "missed once",
+			steps: func(hb *heartbeat) {
+				later := time.Now().Add(2 * period)
+				hb.checkMissed(later)
+				hb.checkMissed(later.Add(period))
+			},
+			want: []string{"is missing"},
+		},
+		{
+			name: "failed then silent",
+			steps: func(hb *heartbeat) {
+				hb.ping("fail")
+				hb.checkMissed(time.Now().Add(2 * period))
+			},
+			want: []string{"reported a failure", "is missing"},
+		},
+		{
+			name: "missed then recovered",
+			steps: func(hb *heartbeat) {
+				hb.checkMissed(time.Now().Add(2 * period))
+				hb.ping("")
+			},
+			want: []string{"is missing", "recovered"},
+		},
+		{
+			name: "repeated failures alert once",
+			steps: func(hb *heartbeat) {
+				hb.ping("fail")
+				hb.ping("fail")
+			},
+			want: []string{"reported a failure"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := recordAlerts(t)
+			hb := testHeartbeat(t)
+			tt.steps(hb)
+
+			got := alerts()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got alerts %q, want %d matching %q", got, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("alert %d = %q, want it to mention %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeartbeatStateSurvivesRestart(t *testing.T) {
+	recordAlerts(t)
+	hb := testHeartbeat(t)
+	hb.ping("fail")
+	hb.checkMissed(time.Now().Add(2 * time.Minute))
+
+	restored := newHeartbeat(hb.cfg)
+	st := restored.status()
+	if !st.Down || !st.Missed || st.LastPing == nil {
+		t.Errorf("restored status = %+v, want down and missed with a last ping", st)
+	}
+	if !restored.down {
+		t.Error("restored heartbeat forgot the reported failure")
+	}
+}
+
+func TestHeartbeatReconcileDuringPings(t *testing.T) {
+	recordAlerts(t)
+	hb := testHeartbeat(t)
+	s := &Server{cfg: &config.Config{Heartbeats: []config.Heartbeat{hb.cfg}}, heartbeats: map[string]*heartbeat{"tok": hb}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				hb.ping("")
+				hb.checkMissed(time.Now())
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		s.mu.Lock()
+		s.cfg.Heartbeats[0].Grace = config.Duration(time.Duration(i) * time.Second)
+		s.reconcileHeartbeats()
+		s.mu.Unlock()
+	}
+	wg.Wait()
+}
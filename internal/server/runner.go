@@ -0,0 +1,115 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/monitor"
+	"github.com/Aditya251610/pingify/internal/secret"
+	"github.com/Aditya251610/pingify/internal/status"
+)
+
+// runner schedules one check until it is stopped.
+type runner struct {
+	check config.Check
+	stop  chan struct{}
+
+	mu       sync.Mutex
+	last     *monitor.MonitorLog
+	failures int // consecutive failed runs
+	failing  bool
+}
+
+func newRunner(check config.Check) *runner {
+	return &runner{check: check.WithDefaults(), stop: make(chan struct{})}
+}
+
+func (r *runner) start() {
+	go r.loop()
+}
+
+func (r *runner) loop() {
+	ticker := time.NewTicker(r.check.Interval.Std())
+	defer ticker.Stop()
+
+	if !r.check.Paused {
+		r.run()
+	}
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if !r.check.Paused {
+				r.run()
+			}
+		}
+	}
+}
+
+// run executes the check once. When the check starts failing, after FailAfter failed runs in a row,
+// or recovers, the change is recorded for the status page and alerted.
+func (r *runner) run() monitor.MonitorLog {
+	log, _, err := monitor.RunCheck(r.check)
+	if err != nil {
+		fmt.Fprintf(secret.Stdout, "❌ [%s] Request Error: %v\n", r.check.Name, err)
+	} else if log.Error != "" {
+		fmt.Fprintf(secret.Stdout, "🚨 [%s] %s\n", r.check.Name, log.Error)
+	} else if log.Exceeded {
+		fmt.Fprintf(secret.Stdout, "🚨 [%s] exceeded threshold (%s > %s)\n", r.check.Name, log.ExecutionTime, log.Threshold)
+	}
+
+	r.mu.Lock()
+	r.last = &log
+	if log.Success {
+		r.failures = 0
+	} else {
+		r.failures++
+	}
+	failing := r.failures >= r.check.FailAfter
+	changed := failing != r.failing
+	r.failing = failing
+	r.mu.Unlock()
+
+	if changed {
+		transition := status.Transition{Time: log.Timestamp, Check: r.check.Name, Failing: failing, Message: reason(log)}
+		if err := status.RecordTransition(transition); err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Failed to record status transition:", err)
+		}
+		if r.check.Alert {
+			r.alert(log)
+		}
+	}
+	return log
+}
+
+// reason describes why a check result failed.
+func reason(log monitor.MonitorLog) string {
+	if log.Success {
+		return ""
+	}
+	if log.Error != "" {
+		return log.Error
+	}
+	return fmt.Sprintf("%s %s in %s (threshold %s)", log.Method, log.StatusText, log.ExecutionTime, log.Threshold)
+}
+
+func (r *runner) alert(log monitor.MonitorLog) {
+	var message string
+	if log.Success {
+		message = fmt.Sprintf("✅ Check %q recovered (%s %s in %s).\nURL: %s",
+			r.check.Name, log.Method, log.StatusText, log.ExecutionTime, r.check.URL)
+	} else {
+		message = fmt.Sprintf("⚠️ Check %q is failing: %s.\nURL: %s", r.check.Name, reason(log), r.check.URL)
+	}
+	monitor.SendEmailAlert(message, r.check.Email)
+}
+
+// state returns the last result and whether the check is currently failing.
+func (r *runner) state() (*monitor.MonitorLog, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, r.failing
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/status"
+)
+
+func TestRunnerRecordsTransitionsAfterFailAfter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	results := []string{"down", "up", "down", "down", "down", "up"}
+	var i atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(results[i.Add(1)-1]))
+	}))
+	defer srv.Close()
+
+	r := newRunner(config.Check{Name: "api", URL: srv.URL, Expect: "up", FailAfter: 2})
+	var failing []bool
+	for range results {
+		r.run()
+		_, f := r.state()
+		failing = append(failing, f)
+	}
+
+	want := []bool{false, false, false, true, true, false}
+	for n := range want {
+		if failing[n] != want[n] {
+			t.Errorf("after run %d failing = %v, want %v", n+1, failing[n], want[n])
+		}
+	}
+
+	transitions, err := status.ReadTransitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transitions) != 2 || !transitions[0].Failing || transitions[1].Failing {
+		t.Fatalf("transitions = %+v, want one failing and one recovery", transitions)
+	}
+	if transitions[0].Check != "api" || transitions[0].Message == "" {
+		t.Errorf("failing transition = %+v, want check api with a reason", transitions[0])
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/monitor"
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+// reloadInterval is how often the config file is checked for changes.
+const reloadInterval = 2 * time.Second
+
+// Server runs the checks of a config file indefinitely and exposes them over a local REST API.
+type Server struct {
+	configPath string
+
+	mu         sync.Mutex
+	cfg        *config.Config
+	runners    map[string]*runner
+	heartbeats map[string]*heartbeat
+	modTime    time.Time
+
+	// token, when set, must be sent as a bearer token with every request that changes state.
+	token string
+}
+
+// RequireToken makes the API reject requests that change checks or post incidents unless they
+// carry "Authorization: Bearer <token>". Reads, the status page and heartbeat pings stay open.
+func (s *Server) RequireToken(token string) {
+	s.token = token
+}
+
+// New loads the config file at path. A missing file starts the daemon with no checks;
+// the file is created on the first change made through the API.
+func New(path string) (*Server, error) {
+	s := &Server{configPath: path, cfg: &config.Config{}, runners: map[string]*runner{}, heartbeats: map[string]*heartbeat{}}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	s.cfg = cfg
+	s.modTime = info.ModTime()
+	return s, nil
+}
+
+// loadConfig reads the config file and makes sure every check type has a probe.
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := monitor.ValidateTypes(cfg.Checks); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Run starts every check, watches the config file and serves the API on addr until ctx is cancelled.
+func (s *Server) Run(ctx context.Context, addr string) error {
+	s.mu.Lock()
+	s.reconcile()
+	s.mu.Unlock()
+
+	go s.watch(ctx)
+	go s.watchHeartbeats(ctx)
+
+	srv := &http.Server{Addr: addr, Handler: s.Handler()}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		s.stopAll()
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.stopAll()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// reconcile starts, restarts and stops runners so they match s.cfg. Callers must hold s.mu.
+func (s *Server) reconcile() {
+	s.reconcileHeartbeats()
+
+	wanted := map[string]config.Check{}
+	for _, check := range s.cfg.Checks {
+		wanted[check.Name] = check
+	}
+
+	restarted := map[string]*runner{}
+	for name, r := range s.runners {
+		check, ok := wanted[name]
+		if ok && reflect.DeepEqual(r.check, check.WithDefaults()) {
+			continue
+		}
+		close(r.stop)
+		delete(s.runners, name)
+		if ok {
+			restarted[name] = r
+		} else {
+			fmt.Fprintf(secret.Stdout, "🗑️  Stopped check %q\n", name)
+		}
+	}
+
+	for _, check := range s.cfg.Checks {
+		if _, ok := s.runners[check.Name]; ok {
+			continue
+		}
+		r := newRunner(check)
+		if prev, ok := restarted[check.Name]; ok {
+			r.last, r.failing = prev.state()
+		}
+		s.runners[check.Name] = r
+		r.start()
+		if check.Paused {
+			fmt.Fprintf(secret.Stdout, "⏸️  Loaded paused check %q\n", check.Name)
+		} else {
+			fmt.Fprintf(secret.Stdout, "📡 Monitoring %q (%s every %s)\n", check.Name, r.check.URL, r.check.Interval)
+		}
+	}
+}
+
+func (s *Server) stopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, r := range s.runners {
+		close(r.stop)
+		delete(s.runners, name)
+	}
+}
+
+// watch reloads the config file whenever its modification time changes.
+func (s *Server) watch(ctx context.Context) {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(s.configPath)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		if info.ModTime().Equal(s.modTime) {
+			s.mu.Unlock()
+			continue
+		}
+		cfg, err := loadConfig(s.configPath)
+		if err != nil {
+			fmt.Fprintln(secret.Stdout, "❌ Config reload failed, keeping previous checks:", err)
+		} else {
+			fmt.Fprintln(secret.Stdout, "🔄 Reloaded", s.configPath)
+			s.cfg = cfg
+			s.reconcile()
+		}
+		s.modTime = info.ModTime()
+		s.mu.Unlock()
+	}
+}
+
+// update applies fn to a copy of the config, then validates, persists and applies the result.
+// The running config is left untouched when any step fails.
+func (s *Server) update(fn func(cfg *config.Config) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := *s.cfg
+	next.Checks = append([]config.Check(nil), s.cfg.Checks...)
+	if err := fn(&next); err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	if err := monitor.ValidateTypes(next.Checks); err != nil {
+		return err
+	}
+	if err := config.Save(s.configPath, &next); err != nil {
+		return fmt.Errorf("failed to save %s: %w", s.configPath, err)
+	}
+	if info, err := os.Stat(s.configPath); err == nil {
+		s.modTime = info.ModTime()
+	}
+
+	s.cfg = &next
+	s.reconcile()
+	return nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Aditya251610/pingify/internal/status"
+)
+
+func (s *Server) buildStatus() (*status.Page, error) {
+	s.mu.Lock()
+	cfg := s.cfg
+	s.mu.Unlock()
+	return status.Build(cfg)
+}
+
+func (s *Server) statusData(w http.ResponseWriter, r *http.Request) {
+	page, err := s.buildStatus()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) statusPage(w http.ResponseWriter, r *http.Request) {
+	page, err := s.buildStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = status.Render(w, page)
+}
+
+func (s *Server) postIncident(w http.ResponseWriter, r *http.Request) {
+	var note status.Note
+	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid incident payload: %w", err))
+		return
+	}
+
+	note, err := status.AddNote(note)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, note)
+}
@@ -0,0 +1,75 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// NotesPath is where manually posted incident notes are stored.
+var NotesPath = filepath.Join("status", "incidents.json")
+
+var notesMu sync.Mutex
+
+// Note is an incident update posted by a person rather than derived from check results.
+type Note struct {
+	Time      time.Time `json:"time"`
+	Component string    `json:"component,omitempty"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message,omitempty"`
+	State     string    `json:"state,omitempty"`
+}
+
+// ReadNotes returns every posted note, oldest first. A missing file means no notes.
+func ReadNotes() ([]Note, error) {
+	notesMu.Lock()
+	defer notesMu.Unlock()
+	return readNotes()
+}
+
+func readNotes() ([]Note, error) {
+	data, err := os.ReadFile(NotesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []Note
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// AddNote appends a note to the notes file, stamping it with the current time when unset.
+func AddNote(note Note) (Note, error) {
+	if note.Title == "" {
+		return note, errors.New("note title is required")
+	}
+	if note.Time.IsZero() {
+		note.Time = time.Now()
+	}
+
+	notesMu.Lock()
+	defer notesMu.Unlock()
+
+	notes, err := readNotes()
+	if err != nil {
+		return note, err
+	}
+	notes = append(notes, note)
+
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return note, err
+	}
+	if err := os.MkdirAll(filepath.Dir(NotesPath), os.ModePerm); err != nil {
+		return note, err
+	}
+	return note, os.WriteFile(NotesPath, data, 0644)
+}
@@ -0,0 +1,108 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var stateLabels = map[string]string{
+	StateOperational: "Operational",
+	StateDegraded:    "Degraded Performance",
+	StateOutage:      "Major Outage",
+	StatePaused:      "Paused",
+	StateUnknown:     "No Data",
+}
+
+var pageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"label": func(state string) string { return stateLabels[state] },
+	"dayClass": func(d Day) string {
+		switch {
+		case d.Checks == 0:
+			return "none"
+		case d.Uptime() >= 99.5:
+			return "up"
+		case d.Uptime() >= 95:
+			return "partial"
+		default:
+			return "down"
+		}
+	},
+	"pct": func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,sans-serif;max-width:860px;margin:2rem auto;padding:0 1rem;color:#1f2933}
+.banner{padding:1rem 1.25rem;border-radius:6px;color:#fff;font-weight:600;margin-bottom:2rem}
+.operational,.up{background:#2f9e44}.degraded,.partial{background:#f59f00}.outage,.down{background:#e03131}.paused,.unknown,.none{background:#adb5bd}
+.component{border:1px solid #e4e7eb;border-radius:6px;padding:1rem;margin-bottom:1rem}
+.component header{display:flex;justify-content:space-between;margin-bottom:.5rem}
+.state{font-size:.9rem;padding:.1rem .5rem;border-radius:4px;color:#fff}
+.bars{display:flex;gap:2px}.bars span{flex:1;height:32px;border-radius:2px}
+.legend{display:flex;justify-content:space-between;font-size:.8rem;color:#7b8794;margin-top:.25rem}
+.incident{border-left:4px solid #e4e7eb;padding:.25rem 1rem;margin-bottom:1rem}
+.incident.manual{border-color:#1c7ed6}.incident small{color:#7b8794}
+footer{font-size:.8rem;color:#7b8794;margin-top:2rem}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="banner {{.State}}">{{if eq .State "operational"}}All Systems Operational{{else}}{{label .State}}{{end}}</div>
+{{range .Components}}
+<section class="component">
+<header><strong>{{.Name}}</strong><span class="state {{.State}}">{{label .State}}</span></header>
+<div class="bars">{{range .Days}}<span class="{{dayClass .}}" title="{{.Date.Format "2006-01-02"}}{{if .Checks}}: {{pct .Uptime}} uptime{{else}}: no data{{end}}"></span>{{end}}</div>
+<div class="legend"><span>90 days ago</span><span>{{pct .Uptime}} uptime</span><span>Today</span></div>
+</section>
+{{end}}
+<h2>Incident History</h2>
+{{range .Incidents}}
+<div class="incident{{if .Manual}} manual{{end}}">
+<strong>{{.Title}}</strong>{{if .Component}} · {{.Component}}{{end}}{{if .State}} · {{.State}}{{end}}<br>
+{{if .Message}}{{.Message}}<br>{{end}}
+<small>{{.Start.Format "2006-01-02 15:04 MST"}}{{if .End}} – {{.End.Format "2006-01-02 15:04 MST"}}{{end}}</small>
+</div>
+{{else}}
+<p>No incidents in the last 90 days.</p>
+{{end}}
+<footer>Generated by Pingify at {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</footer>
+</body>
+</html>
+`))
+
+// Render writes the page as HTML.
+func Render(w io.Writer, page *Page) error {
+	return pageTemplate.Execute(w, page)
+}
+
+// WriteSite writes a static site (index.html plus status.json) into dir.
+func WriteSite(dir string, page *Page) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(dir, "index.html"))
+	if err != nil {
+		return err
+	}
+	if err := Render(file, page); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "status.json"), data, 0644)
+}
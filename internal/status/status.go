@@ -0,0 +1,270 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/config"
+	"github.com/Aditya251610/pingify/internal/monitor"
+)
+
+// HistoryDays is the number of daily uptime bars shown per component.
+const HistoryDays = 90
+
+// Component states, from best to worst.
+const (
+	StateOperational = "operational"
+	StateDegraded    = "degraded"
+	StateOutage      = "outage"
+	StatePaused      = "paused"
+	StateUnknown     = "unknown"
+)
+
+// Page is everything needed to render the status page.
+type Page struct {
+	Title       string      `json:"title"`
+	State       string      `json:"state"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Components  []Component `json:"components"`
+	Incidents   []Incident  `json:"incidents"`
+}
+
+// Component is one row of the status page.
+type Component struct {
+	Name   string  `json:"name"`
+	State  string  `json:"state"`
+	Uptime float64 `json:"uptime"`
+	Days   []Day   `json:"days"`
+}
+
+// Day is the uptime of a component over one UTC day; Checks is zero when nothing ran that day.
+type Day struct {
+	Date    time.Time `json:"date"`
+	Checks  int       `json:"checks"`
+	Success int       `json:"success"`
+}
+
+// Uptime returns the share of successful checks that day as a percentage.
+func (d Day) Uptime() float64 {
+	if d.Checks == 0 {
+		return 0
+	}
+	return float64(d.Success) / float64(d.Checks) * 100
+}
+
+// Incident is either an outage derived from the recorded check transitions or a manually posted note.
+type Incident struct {
+	Component string     `json:"component,omitempty"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message,omitempty"`
+	State     string     `json:"state,omitempty"`
+	Start     time.Time  `json:"start"`
+	End       *time.Time `json:"end,omitempty"`
+	Manual    bool       `json:"manual"`
+}
+
+// Build assembles the status page from the stored monitor logs and transitions of every
+// configured check and the posted incident notes.
+func Build(cfg *config.Config) (*Page, error) {
+	now := time.Now().UTC()
+	page := &Page{Title: "Service Status", GeneratedAt: now}
+	if cfg.Status != nil && cfg.Status.Title != "" {
+		page.Title = cfg.Status.Title
+	}
+
+	transitions, err := ReadTransitions()
+	if err != nil {
+		return nil, err
+	}
+	byCheck := map[string][]Transition{}
+	for _, t := range transitions {
+		byCheck[t.Check] = append(byCheck[t.Check], t)
+	}
+
+	for _, group := range groups(cfg) {
+		component, incidents, err := buildComponent(group, cfg, byCheck, now)
+		if err != nil {
+			return nil, err
+		}
+		page.Components = append(page.Components, component)
+		page.Incidents = append(page.Incidents, incidents...)
+	}
+
+	notes, err := ReadNotes()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := now.AddDate(0, 0, -HistoryDays)
+	for _, note := range notes {
+		if note.Time.Before(cutoff) {
+			continue
+		}
+		page.Incidents = append(page.Incidents, Incident{
+			Component: note.Component,
+			Title:     note.Title,
+			Message:   note.Message,
+			State:     note.State,
+			Start:     note.Time,
+			Manual:    true,
+		})
+	}
+	sort.SliceStable(page.Incidents, func(i, j int) bool {
+		return page.Incidents[i].Start.After(page.Incidents[j].Start)
+	})
+
+	page.State = overallState(page.Components)
+	return page, nil
+}
+
+// groups returns the configured components, or one component per check when none are configured.
+func groups(cfg *config.Config) []config.Component {
+	if cfg.Status != nil && len(cfg.Status.Components) > 0 {
+		return cfg.Status.Components
+	}
+	var out []config.Component
+	for _, check := range cfg.Checks {
+		out = append(out, config.Component{Name: check.Name, Checks: []string{check.Name}})
+	}
+	for _, hb := range cfg.Heartbeats {
+		out = append(out, config.Component{Name: hb.Name, Checks: []string{hb.Name}})
+	}
+	return out
+}
+
+func buildComponent(group config.Component, cfg *config.Config, transitions map[string][]Transition, now time.Time) (Component, []Incident, error) {
+	today := now.Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -(HistoryDays - 1))
+
+	component := Component{Name: group.Name, Days: make([]Day, HistoryDays)}
+	for i := range component.Days {
+		component.Days[i].Date = first.AddDate(0, 0, i)
+	}
+
+	var incidents []Incident
+	var states []string
+	var total, success int
+	for _, name := range group.Checks {
+		logKey, ok := cfg.LogKey(name)
+		if !ok {
+			continue
+		}
+		paused := false
+		if i := cfg.Find(name); i >= 0 {
+			paused = cfg.Checks[i].Paused
+		}
+
+		logs, err := monitor.ReadLogs(logKey)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return component, nil, err
+		}
+
+		for _, log := range logs {
+			i := int(log.Timestamp.UTC().Truncate(24*time.Hour).Sub(first) / (24 * time.Hour))
+			if i < 0 || i >= HistoryDays {
+				continue
+			}
+			component.Days[i].Checks++
+			total++
+			if log.Success {
+				component.Days[i].Success++
+				success++
+			}
+		}
+
+		switch {
+		case paused:
+			states = append(states, StatePaused)
+		case len(logs) == 0:
+			states = append(states, StateUnknown)
+		case logs[len(logs)-1].Success:
+			states = append(states, StateOperational)
+		default:
+			states = append(states, StateOutage)
+		}
+
+		incidents = append(incidents, deriveIncidents(group.Name, name, transitions[name], first)...)
+	}
+
+	if total > 0 {
+		component.Uptime = float64(success) / float64(total) * 100
+	}
+	component.State = combineStates(states)
+	return component, incidents, nil
+}
+
+// deriveIncidents turns each time a check started failing into an incident that ends when it
+// recovered; one that never recovered is an ongoing incident.
+func deriveIncidents(component, check string, transitions []Transition, since time.Time) []Incident {
+	var incidents []Incident
+	var open *Incident
+	for _, t := range transitions {
+		if t.Failing && open == nil {
+			open = &Incident{
+				Component: component,
+				Title:     check + " check failing",
+				Message:   t.Message,
+				Start:     t.Time,
+			}
+		} else if !t.Failing && open != nil {
+			end := t.Time
+			open.End = &end
+			open.State = "resolved"
+			if end.After(since) {
+				incidents = append(incidents, *open)
+			}
+			open = nil
+		}
+	}
+	if open != nil {
+		open.State = "ongoing"
+		incidents = append(incidents, *open)
+	}
+	return incidents
+}
+
+// combineStates summarises the states of a component's checks.
+func combineStates(states []string) string {
+	counts := map[string]int{}
+	for _, s := range states {
+		counts[s]++
+	}
+	active := len(states) - counts[StatePaused]
+	switch {
+	case len(states) == 0:
+		return StateUnknown
+	case active == 0:
+		return StatePaused
+	case counts[StateOutage] == active:
+		return StateOutage
+	case counts[StateOutage] > 0:
+		return StateDegraded
+	case counts[StateUnknown] == active:
+		return StateUnknown
+	default:
+		return StateOperational
+	}
+}
+
+// overallState reports an outage only when every component is down; any other problem is degraded.
+func overallState(components []Component) string {
+	outages, problems := 0, 0
+	for _, c := range components {
+		switch c.State {
+		case StateOutage:
+			outages++
+			problems++
+		case StateDegraded:
+			problems++
+		}
+	}
+	switch {
+	case len(components) > 0 && outages == len(components):
+		return StateOutage
+	case problems > 0:
+		return StateDegraded
+	default:
+		return StateOperational
+	}
+}
@@ -0,0 +1,38 @@
+package status
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeriveIncidents(t *testing.T) {
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name        string
+		transitions []Transition
+		want        []string // state of each incident
+	}{
+		{"none", nil, nil},
+		{"resolved", []Transition{{Time: at(0), Failing: true}, {Time: at(5)}}, []string{"resolved"}},
+		{"ongoing", []Transition{{Time: at(0), Failing: true}}, []string{"ongoing"}},
+		{"repeated failing opens one", []Transition{{Time: at(0), Failing: true}, {Time: at(1), Failing: true}, {Time: at(2)}}, []string{"resolved"}},
+		{"recovery without failure", []Transition{{Time: at(0)}}, nil},
+		{"two outages", []Transition{{Time: at(0), Failing: true}, {Time: at(1)}, {Time: at(2), Failing: true}}, []string{"resolved", "ongoing"}},
+		{"resolved before the window", []Transition{{Time: at(-200 * 24 * 60), Failing: true}, {Time: at(-199 * 24 * 60)}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deriveIncidents("API", "api", tt.transitions, base.AddDate(0, 0, -HistoryDays))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d incidents %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, state := range tt.want {
+				if got[i].State != state {
+					t.Errorf("incident %d state %q, want %q", i, got[i].State, state)
+				}
+			}
+		})
+	}
+}
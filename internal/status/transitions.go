@@ -0,0 +1,72 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TransitionsPath is where the daemon records checks starting and stopping to fail.
+var TransitionsPath = filepath.Join("status", "transitions.json")
+
+var transitionsMu sync.Mutex
+
+// Transition is a check or heartbeat changing state, the same change that raises an alert.
+// Incidents on the status page are built from these rather than from single results.
+type Transition struct {
+	Time    time.Time `json:"time"`
+	Check   string    `json:"check"`
+	Failing bool      `json:"failing"`
+	Message string    `json:"message,omitempty"`
+}
+
+// ReadTransitions returns every recorded transition, oldest first. A missing file means none.
+func ReadTransitions() ([]Transition, error) {
+	transitionsMu.Lock()
+	defer transitionsMu.Unlock()
+	return readTransitions()
+}
+
+func readTransitions() ([]Transition, error) {
+	data, err := os.ReadFile(TransitionsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var transitions []Transition
+	if err := json.Unmarshal(data, &transitions); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// RecordTransition appends a transition to the transitions file, stamping it with the current time when unset.
+func RecordTransition(t Transition) error {
+	if t.Time.IsZero() {
+		t.Time = time.Now()
+	}
+
+	transitionsMu.Lock()
+	defer transitionsMu.Unlock()
+
+	transitions, err := readTransitions()
+	if err != nil {
+		return err
+	}
+	transitions = append(transitions, t)
+
+	data, err := json.MarshalIndent(transitions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(TransitionsPath), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(TransitionsPath, data, 0644)
+}
@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+const (
+	// flushInterval and maxBatch bound how long and how many finished checks wait to be exported.
+	flushInterval = 2 * time.Second
+	maxBatch      = 100
+
+	// queueSize is how many finished checks may wait for the exporter before new ones are dropped,
+	// so a slow collector never holds up a check.
+	queueSize = 1000
+)
+
+// item is one finished check waiting to be exported.
+type item struct {
+	target  string
+	spans   []otlpSpan
+	metrics []otlpMetric
+}
+
+var (
+	queue     = make(chan item, queueSize)
+	flushReqs = make(chan chan struct{})
+	startOnce sync.Once
+	started   atomic.Bool
+)
+
+// enqueue hands a finished check to the background exporter, dropping it when the queue is full.
+func enqueue(it item) {
+	startOnce.Do(func() {
+		started.Store(true)
+		go exporter()
+	})
+	select {
+	case queue <- it:
+	default:
+		fmt.Fprintln(secret.Stdout, "⚠️ Telemetry export is falling behind; a check was not exported")
+	}
+}
+
+// Flush exports the checks still waiting, giving up after timeout. Call it before exiting.
+func Flush(timeout time.Duration) {
+	if !started.Load() {
+		return
+	}
+	deadline := time.After(timeout)
+	done := make(chan struct{})
+	select {
+	case flushReqs <- done:
+	case <-deadline:
+		return
+	}
+	select {
+	case <-done:
+	case <-deadline:
+	}
+}
+
+// exporter batches finished checks and posts them, one traces and one metrics request per
+// collector and batch.
+func exporter() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	var batch []item
+	send := func() {
+		if len(batch) > 0 {
+			export(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case it := <-queue:
+			batch = append(batch, it)
+			if len(batch) >= maxBatch {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case done := <-flushReqs:
+			for drained := false; !drained; {
+				select {
+				case it := <-queue:
+					batch = append(batch, it)
+				default:
+					drained = true
+				}
+			}
+			send()
+			close(done)
+		}
+	}
+}
+
+func export(batch []item) {
+	var targets []string
+	byTarget := map[string][]item{}
+	for _, it := range batch {
+		if _, ok := byTarget[it.target]; !ok {
+			targets = append(targets, it.target)
+		}
+		byTarget[it.target] = append(byTarget[it.target], it)
+	}
+
+	res := resource{Attributes: []keyValue{stringAttr("service.name", serviceName())}}
+	for _, target := range targets {
+		var spans []otlpSpan
+		var metrics []otlpMetric
+		for _, it := range byTarget[target] {
+			spans = append(spans, it.spans...)
+			metrics = append(metrics, it.metrics...)
+		}
+		traces := tracesPayload{ResourceSpans: []resourceSpans{{
+			Resource:   res,
+			ScopeSpans: []scopeSpans{{Scope: scope{Name: scopeName}, Spans: spans}},
+		}}}
+		if err := post(client, target, "/v1/traces", traces); err != nil {
+			fmt.Fprintln(secret.Stdout, "⚠️ Trace export failed:", err)
+		}
+		payload := metricsPayload{ResourceMetrics: []resourceMetrics{{
+			Resource:     res,
+			ScopeMetrics: []scopeMetrics{{Scope: scope{Name: scopeName}, Metrics: metrics}},
+		}}}
+		if err := post(client, target, "/v1/metrics", payload); err != nil {
+			fmt.Fprintln(secret.Stdout, "⚠️ Metric export failed:", err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+)
+
+const scopeName = "github.com/Aditya251610/pingify"
+
+// durationBounds are the histogram bucket boundaries (in milliseconds) for check durations.
+var durationBounds = []float64{50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+var (
+	mu       sync.Mutex
+	endpoint string
+	client   = &http.Client{Timeout: 5 * time.Second} // used by the background exporter only
+)
+
+// SetEndpoint overrides the OTLP/HTTP collector base URL (e.g. http://localhost:4318).
+// An empty endpoint keeps the OTEL_EXPORTER_OTLP_ENDPOINT value; export is disabled when neither is set.
+func SetEndpoint(url string) {
+	mu.Lock()
+	defer mu.Unlock()
+	endpoint = strings.TrimRight(url, "/")
+}
+
+// Enabled reports whether checks are exported to a collector.
+func Enabled() bool {
+	return currentEndpoint() != ""
+}
+
+// currentEndpoint is resolved lazily so values loaded from .env after startup are honoured.
+func currentEndpoint() string {
+	mu.Lock()
+	defer mu.Unlock()
+	if endpoint != "" {
+		return endpoint
+	}
+	return strings.TrimRight(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/")
+}
+
+func serviceName() string {
+	if v := os.Getenv("OTEL_SERVICE_NAME"); v != "" {
+		return v
+	}
+	return "pingify"
+}
+
+// Check is a single probe being traced. Create it with StartCheck before sending the request.
+type Check struct {
+	name    string
+	method  string
+	url     string
+	traceID [16]byte
+	spanID  [8]byte
+	start   time.Time
+}
+
+// StartCheck begins a span for a probe of the given URL.
+func StartCheck(name, method, url string) *Check {
+	c := &Check{name: name, method: method, url: url, start: time.Now()}
+	_, _ = rand.Read(c.traceID[:])
+	_, _ = rand.Read(c.spanID[:])
+	return c
+}
+
+// TraceParent returns the W3C traceparent header value linking the outgoing request to this span.
+func (c *Check) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(c.traceID[:]), hex.EncodeToString(c.spanID[:]))
+}
+
+// Header returns the propagation headers to inject into the outgoing request, or nil when
+// telemetry is disabled so no trace context leaves the machine.
+func (c *Check) Header() http.Header {
+	if !Enabled() {
+		return nil
+	}
+	return http.Header{"Traceparent": []string{c.TraceParent()}}
+}
+
+// End finishes the span and queues it, together with the check metrics, for export when
+// telemetry is enabled. Export happens in the background and never delays or fails the check.
+func (c *Check) End(resp *requester.Response, err error) {
+	target := currentEndpoint()
+	if target == "" {
+		return
+	}
+
+	end := time.Now()
+	status := 0
+	var phases []requester.Phase
+	if resp != nil {
+		status = resp.StatusCode
+		phases = resp.Phases
+	}
+	class := ErrorClass(status, err)
+
+	attrs := []keyValue{
+		stringAttr("http.request.method", c.method),
+		stringAttr("url.full", c.url),
+		stringAttr("pingify.check", c.name),
+	}
+	if status != 0 {
+		attrs = append(attrs, intAttr("http.response.status_code", int64(status)))
+	}
+	if class != "" {
+		attrs = append(attrs, stringAttr("error.type", class))
+	}
+
+	spanStatusValue := spanStatus{Code: statusUnset}
+	if class != "" {
+		spanStatusValue = spanStatus{Code: statusError, Message: class}
+		if err != nil {
+			spanStatusValue.Message = err.Error()
+		}
+	}
+
+	traceID := hex.EncodeToString(c.traceID[:])
+	parentID := hex.EncodeToString(c.spanID[:])
+
+	root := otlpSpan{
+		TraceID:           traceID,
+		SpanID:            parentID,
+		Name:              c.name,
+		Kind:              spanKindClient,
+		StartTimeUnixNano: unixNano(c.start),
+		EndTimeUnixNano:   unixNano(end),
+		Attributes:        attrs,
+		Status:            spanStatusValue,
+	}
+	if err != nil {
+		root.Events = append(root.Events, spanEvent{
+			Name:         "exception",
+			TimeUnixNano: unixNano(end),
+			Attributes:   []keyValue{stringAttr("exception.message", err.Error())},
+		})
+	}
+
+	spans := []otlpSpan{root}
+	for _, p := range phases {
+		var id [8]byte
+		_, _ = rand.Read(id[:])
+		spans = append(spans, otlpSpan{
+			TraceID:           traceID,
+			SpanID:            hex.EncodeToString(id[:]),
+			ParentSpanID:      parentID,
+			Name:              "http." + p.Name,
+			Kind:              spanKindClient,
+			StartTimeUnixNano: unixNano(p.Start),
+			EndTimeUnixNano:   unixNano(p.End),
+			Status:            spanStatus{Code: statusUnset},
+		})
+		root.Events = append(root.Events, spanEvent{
+			Name:         p.Name,
+			TimeUnixNano: unixNano(p.End),
+			Attributes:   []keyValue{intAttr("duration_ms", p.Duration().Milliseconds())},
+		})
+	}
+	spans[0] = root
+
+	enqueue(item{target: target, spans: spans, metrics: c.metrics(end, status, class)})
+}
+
+func (c *Check) metrics(end time.Time, status int, class string) []otlpMetric {
+	attrs := []keyValue{
+		stringAttr("url.full", c.url),
+		stringAttr("http.request.method", c.method),
+		stringAttr("pingify.check", c.name),
+		boolAttr("success", class == ""),
+	}
+	if status != 0 {
+		attrs = append(attrs, intAttr("http.response.status_code", int64(status)))
+	}
+
+	elapsed := float64(end.Sub(c.start)) / float64(time.Millisecond)
+	buckets := make([]string, len(durationBounds)+1)
+	bucket := len(durationBounds)
+	for i, bound := range durationBounds {
+		if elapsed <= bound {
+			bucket = i
+			break
+		}
+	}
+	for i := range buckets {
+		buckets[i] = "0"
+	}
+	buckets[bucket] = "1"
+
+	start, now := unixNano(c.start), unixNano(end)
+	return []otlpMetric{
+		{
+			Name: "pingify.check.count",
+			Unit: "{check}",
+			Sum: &sumData{
+				DataPoints:             []numberDataPoint{{Attributes: attrs, StartTimeUnixNano: start, TimeUnixNano: now, AsInt: "1"}},
+				AggregationTemporality: temporalityDelta,
+				IsMonotonic:            true,
+			},
+		},
+		{
+			Name: "pingify.check.duration",
+			Unit: "ms",
+			Histogram: &histogramData{
+				DataPoints: []histogramDataPoint{{
+					Attributes:        attrs,
+					StartTimeUnixNano: start,
+					TimeUnixNano:      now,
+					Count:             "1",
+					Sum:               elapsed,
+					BucketCounts:      buckets,
+					ExplicitBounds:    durationBounds,
+				}},
+				AggregationTemporality: temporalityDelta,
+			},
+		},
+	}
+}
+
+// ErrorClass buckets a check failure into a low-cardinality class suitable for span and metric attributes.
+// It returns "" for successful checks.
+func ErrorClass(status int, err error) string {
+	if err != nil {
+		var dnsErr *net.DNSError
+		var opErr *net.OpError
+		var netErr net.Error
+		switch {
+		case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+			return "timeout"
+		case errors.As(err, &dnsErr):
+			return "dns"
+		case strings.Contains(err.Error(), "tls:"), strings.Contains(err.Error(), "x509:"):
+			return "tls"
+		case errors.As(err, &opErr) && opErr.Op == "dial":
+			return "connection"
+		default:
+			return "request"
+		}
+	}
+	if status >= 400 {
+		return strconv.Itoa(status/100) + "xx"
+	}
+	return ""
+}
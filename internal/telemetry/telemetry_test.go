@@ -0,0 +1,172 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aditya251610/pingify/internal/requester"
+	"github.com/Aditya251610/pingify/internal/secret"
+)
+
+// collector is an OTLP/HTTP collector stand-in recording what it receives.
+type collector struct {
+	mu      sync.Mutex
+	traces  []tracesPayload
+	metrics []metricsPayload
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch r.URL.Path {
+	case "/v1/traces":
+		var p tracesPayload
+		json.Unmarshal(data, &p)
+		c.traces = append(c.traces, p)
+	case "/v1/metrics":
+		var p metricsPayload
+		json.Unmarshal(data, &p)
+		c.metrics = append(c.metrics, p)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestHeaderOnlyWhenEnabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	SetEndpoint("")
+	if h := StartCheck("x", "GET", "http://example.com").Header(); h != nil {
+		t.Errorf("Header() = %v with telemetry disabled, want nil", h)
+	}
+
+	SetEndpoint("http://127.0.0.1:4318")
+	defer SetEndpoint("")
+	c := StartCheck("x", "GET", "http://example.com")
+	if got, want := c.Header().Get("Traceparent"), c.TraceParent(); got != want {
+		t.Errorf("Traceparent = %q, want %q", got, want)
+	}
+}
+
+func TestExportBatchesChecks(t *testing.T) {
+	var col collector
+	srv := httptest.NewServer(&col)
+	defer srv.Close()
+	SetEndpoint(srv.URL)
+	defer SetEndpoint("")
+
+	start := time.Now()
+	checks := []struct {
+		name   string
+		status int
+		err    error
+	}{
+		{"ok", 200, nil},
+		{"server error", 503, nil},
+		{"refused", 0, errors.New("connection refused")},
+	}
+	for _, c := range checks {
+		span := StartCheck(c.name, "GET", "http://example.com/"+c.name)
+		var resp *requester.Response
+		if c.status != 0 {
+			resp = &requester.Response{StatusCode: c.status}
+		}
+		span.End(resp, c.err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("End took %s; export must not block the check", elapsed)
+	}
+	Flush(5 * time.Second)
+
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	if len(col.traces) != 1 || len(col.metrics) != 1 {
+		t.Fatalf("got %d trace and %d metric requests, want one batch of each", len(col.traces), len(col.metrics))
+	}
+	spans := col.traces[0].ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != len(checks) {
+		t.Fatalf("got %d spans, want %d", len(spans), len(checks))
+	}
+	for i, c := range checks {
+		if spans[i].Name != c.name {
+			t.Errorf("span %d is %q, want %q", i, spans[i].Name, c.name)
+		}
+		wantCode := statusUnset
+		if c.status >= 400 || c.err != nil {
+			wantCode = statusError
+		}
+		if spans[i].Status.Code != wantCode {
+			t.Errorf("span %q status %d, want %d", c.name, spans[i].Status.Code, wantCode)
+		}
+	}
+	if n := len(col.metrics[0].ResourceMetrics[0].ScopeMetrics[0].Metrics); n != 2*len(checks) {
+		t.Errorf("got %d metrics, want %d", n, 2*len(checks))
+	}
+}
+
+func TestExportMasksSecrets(t *testing.T) {
+	var col collector
+	srv := httptest.NewServer(&col)
+	defer srv.Close()
+	SetEndpoint(srv.URL)
+	defer SetEndpoint("")
+
+	const key = "k3y-from-env"
+	secret.Register(key)
+	span := StartCheck("api", "GET", "http://example.com/items?api_key="+key)
+	span.End(nil, fmt.Errorf(`Get "http://example.com/items?api_key=%s": connection refused`, key))
+	Flush(5 * time.Second)
+
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	data, err := json.Marshal([]any{col.traces, col.metrics})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), key) {
+		t.Errorf("exported payload contains the secret: %s", data)
+	}
+	if !strings.Contains(string(data), "api_key="+secret.Masked) {
+		t.Errorf("exported payload lost the masked URL: %s", data)
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		status int
+		err    error
+		want   string
+	}{
+		{200, nil, ""},
+		{302, nil, ""},
+		{404, nil, "4xx"},
+		{503, nil, "5xx"},
+		{0, context.DeadlineExceeded, "timeout"},
+		{0, timeoutError{}, "timeout"},
+		{0, &net.DNSError{Err: "no such host", Name: "nope.invalid"}, "dns"},
+		{0, errors.New("tls: handshake failure"), "tls"},
+		{0, fmt.Errorf("get: %w", &net.OpError{Op: "dial", Err: errors.New("refused")}), "connection"},
+		{0, errors.New("something else"), "request"},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(tt.status, tt.err); got != tt.want {
+			t.Errorf("ErrorClass(%d, %v) = %q, want %q", tt.status, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package tlsinfo
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Certificate summarises one certificate of the served chain.
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	SANs      []string  `json:"sans,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	KeyType   string    `json:"key_type"`
+}
+
+// DaysLeft returns the whole days remaining until the certificate expires (negative once expired).
+func (c Certificate) DaysLeft(now time.Time) int {
+	return int(c.NotAfter.Sub(now).Hours() / 24)
+}
+
+// Info is the result of inspecting a TLS endpoint.
+type Info struct {
+	Address       string        `json:"address"`
+	ServerName    string        `json:"server_name"`
+	Version       string        `json:"version"`
+	CipherSuite   string        `json:"cipher_suite"`
+	OCSPStapled   bool          `json:"ocsp_stapled"`
+	Chain         []Certificate `json:"chain"`
+	ChainError    string        `json:"chain_error,omitempty"`
+	HostnameError string        `json:"hostname_error,omitempty"`
+	Handshake     time.Duration `json:"handshake"`
+
+	version     uint16
+	cipherSuite uint16
+}
+
+// Options tunes Inspect.
+type Options struct {
+	// ServerName overrides the SNI and the hostname the certificate is verified against.
+	ServerName string
+	Timeout    time.Duration
+	// Roots replaces the system roots when verifying the chain (e.g. for an httptest server).
+	Roots *x509.CertPool
+}
+
+// Address turns an https:// URL or a host[:port] into a dialable host:port and its hostname.
+func Address(target string) (string, string, error) {
+	host := target
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid URL: %w", err)
+		}
+		if u.Scheme != "https" && u.Scheme != "wss" {
+			return "", "", fmt.Errorf("TLS inspection needs an https URL, got %s", u.Scheme)
+		}
+		host = u.Host
+	}
+	if host == "" {
+		return "", "", errors.New("TLS target is required")
+	}
+
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname, port = host, "443"
+	}
+	return net.JoinHostPort(hostname, port), hostname, nil
+}
+
+// Inspect connects to target, completes a TLS handshake and reports the served chain.
+// Verification problems are recorded in the result rather than failing the handshake,
+// so expired or mismatched certificates can still be described.
+func Inspect(target string, opts Options) (*Info, error) {
+	addr, hostname, err := Address(target)
+	if err != nil {
+		return nil, err
+	}
+	serverName := opts.ServerName
+	if serverName == "" {
+		serverName = hostname
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true, // verified below so invalid chains can still be reported
+		MinVersion:         tls.VersionTLS10,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake with %s failed: %w", addr, err)
+	}
+	defer conn.Close()
+	handshake := time.Since(start)
+
+	state := conn.ConnectionState()
+	info := &Info{
+		Address:     addr,
+		ServerName:  serverName,
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		OCSPStapled: len(state.OCSPResponse) > 0,
+		Handshake:   handshake,
+		version:     state.Version,
+		cipherSuite: state.CipherSuite,
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("%s presented no certificates", addr)
+	}
+
+	for _, cert := range state.PeerCertificates {
+		info.Chain = append(info.Chain, Certificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			SANs:      sans(cert),
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			KeyType:   keyType(cert),
+		})
+	}
+
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: opts.Roots, Intermediates: intermediates}); err != nil {
+		info.ChainError = err.Error()
+	}
+	if err := leaf.VerifyHostname(serverName); err != nil {
+		info.HostnameError = err.Error()
+	}
+	return info, nil
+}
+
+// Problems lists everything that should raise a warning: expiry within warnDays, an untrusted chain,
+// a hostname mismatch, or a protocol/cipher considered weak.
+func (i *Info) Problems(warnDays int, now time.Time) []string {
+	var problems []string
+	for n, cert := range i.Chain {
+		label := "certificate"
+		if n > 0 {
+			label = fmt.Sprintf("chain certificate #%d (%s)", n, cert.Subject)
+		}
+		switch days := cert.DaysLeft(now); {
+		case now.After(cert.NotAfter):
+			problems = append(problems, fmt.Sprintf("%s expired on %s", label, cert.NotAfter.Format("2006-01-02")))
+		case now.Before(cert.NotBefore):
+			problems = append(problems, fmt.Sprintf("%s is not valid until %s", label, cert.NotBefore.Format("2006-01-02")))
+		case days < warnDays:
+			problems = append(problems, fmt.Sprintf("%s expires in %d days (%s)", label, days, cert.NotAfter.Format("2006-01-02")))
+		}
+	}
+	if i.HostnameError != "" {
+		problems = append(problems, "hostname mismatch: "+i.HostnameError)
+	}
+	if i.ChainError != "" {
+		problems = append(problems, "untrusted chain: "+i.ChainError)
+	}
+	if i.version < tls.VersionTLS12 {
+		problems = append(problems, "weak protocol "+i.Version)
+	}
+	if slices.ContainsFunc(tls.InsecureCipherSuites(), func(s *tls.CipherSuite) bool { return s.ID == i.cipherSuite }) {
+		problems = append(problems, "weak cipher suite "+i.CipherSuite)
+	}
+	return problems
+}
+
+// Summary renders the inspection as terminal output.
+func (i *Info) Summary(now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔒 %s · %s · OCSP stapled: %t · handshake %s\n", i.Version, i.CipherSuite, i.OCSPStapled, i.Handshake)
+	for n, cert := range i.Chain {
+		fmt.Fprintf(&b, "📜 [%d] %s\n", n, cert.Subject)
+		fmt.Fprintf(&b, "     Issuer:   %s\n", cert.Issuer)
+		if len(cert.SANs) > 0 {
+			fmt.Fprintf(&b, "     SANs:     %s\n", strings.Join(cert.SANs, ", "))
+		}
+		fmt.Fprintf(&b, "     Valid:    %s → %s (%d days left)\n",
+			cert.NotBefore.Format("2006-01-02"), cert.NotAfter.Format("2006-01-02"), cert.DaysLeft(now))
+		fmt.Fprintf(&b, "     Key:      %s\n", cert.KeyType)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func sans(cert *x509.Certificate) []string {
+	out := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		out = append(out, ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		out = append(out, email)
+	}
+	for _, uri := range cert.URIs {
+		out = append(out, uri.String())
+	}
+	return out
+}
+
+func keyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA-%d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
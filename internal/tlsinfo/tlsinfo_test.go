@@ -0,0 +1,100 @@
+package tlsinfo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTLSServer(t *testing.T, conf *tls.Config) (*httptest.Server, *x509.CertPool) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = conf
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	return srv, roots
+}
+
+func TestProblems(t *testing.T) {
+	srv, roots := newTLSServer(t, nil)
+	cert := srv.Certificate()
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		opts     Options
+		warnDays int
+		now      time.Time
+		want     []string // one substring per expected problem
+	}{
+		{"trusted", Options{Roots: roots}, 14, now, nil},
+		{"untrusted chain", Options{}, 14, now, []string{"untrusted chain"}},
+		{"hostname mismatch", Options{Roots: roots, ServerName: "wrong.test"}, 14, now, []string{"hostname mismatch"}},
+		{"expires within warn days", Options{Roots: roots}, 1 << 20, now, []string{"expires in"}},
+		{"expired", Options{Roots: roots}, 14, cert.NotAfter.Add(time.Hour), []string{"expired on"}},
+		{"not yet valid", Options{Roots: roots}, 14, cert.NotBefore.Add(-time.Hour), []string{"not valid until"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Inspect(srv.URL, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertProblems(t, info.Problems(tt.warnDays, tt.now), tt.want)
+		})
+	}
+}
+
+func TestProblemsWeakProtocol(t *testing.T) {
+	srv, roots := newTLSServer(t, &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS11})
+	info, err := Inspect(srv.URL, Options{Roots: roots})
+	if err != nil {
+		t.Skipf("TLS 1.1 is not available: %v", err)
+	}
+	assertProblems(t, info.Problems(14, time.Now()), []string{"weak protocol"})
+}
+
+func assertProblems(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got problems %q, want %d matching %q", got, len(want), want)
+	}
+	for i := range want {
+		if !strings.Contains(got[i], want[i]) {
+			t.Errorf("problem %d = %q, want it to mention %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		target   string
+		addr     string
+		hostname string
+		wantErr  bool
+	}{
+		{"https://example.com", "example.com:443", "example.com", false},
+		{"https://example.com:8443/path", "example.com:8443", "example.com", false},
+		{"wss://example.com/live", "example.com:443", "example.com", false},
+		{"example.com", "example.com:443", "example.com", false},
+		{"127.0.0.1:8443", "127.0.0.1:8443", "127.0.0.1", false},
+		{"http://example.com", "", "", true},
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		addr, hostname, err := Address(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Address(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if addr != tt.addr || hostname != tt.hostname {
+			t.Errorf("Address(%q) = %q, %q, want %q, %q", tt.target, addr, hostname, tt.addr, tt.hostname)
+		}
+	}
+}
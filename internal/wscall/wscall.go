@@ -0,0 +1,177 @@
+package wscall
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Session is an open WebSocket connection whose incoming messages are buffered for Expect.
+type Session struct {
+	conn      *websocket.Conn
+	Handshake time.Duration
+
+	messages chan string
+	done     chan struct{}
+	pongs    chan struct{}
+
+	mu       sync.Mutex
+	closeErr error
+	closing  bool
+}
+
+// Options tunes how Dial connects. The zero value uses a 10s handshake timeout, the default TLS
+// settings and the proxy from the environment.
+type Options struct {
+	Timeout time.Duration
+	TLS     *tls.Config
+	// NetDial replaces the TCP dialer, e.g. to pin addresses or go through SOCKS.
+	NetDial func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Proxy replaces the environment's HTTP proxy selection when NetDial is set.
+	Proxy func(*http.Request) (*url.URL, error)
+}
+
+// Dial opens a WebSocket connection and starts reading messages in the background.
+func Dial(rawURL string, header http.Header, opts Options) (*Session, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := websocket.Dialer{HandshakeTimeout: timeout, Proxy: http.ProxyFromEnvironment, TLSClientConfig: opts.TLS}
+	if opts.NetDial != nil {
+		dialer.NetDialContext = opts.NetDial
+		dialer.Proxy = opts.Proxy
+	}
+
+	start := time.Now()
+	conn, resp, err := dialer.Dial(rawURL, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("WebSocket handshake failed with HTTP %d: %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("WebSocket handshake failed: %w", err)
+	}
+
+	s := &Session{
+		conn:      conn,
+		Handshake: time.Since(start),
+		messages:  make(chan string, 64),
+		done:      make(chan struct{}),
+		pongs:     make(chan struct{}, 1),
+	}
+	conn.SetPongHandler(func(string) error {
+		select {
+		case s.pongs <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go s.read()
+	return s, nil
+}
+
+func (s *Session) read() {
+	defer close(s.done)
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.mu.Lock()
+			s.closeErr = err
+			s.mu.Unlock()
+			close(s.messages)
+			return
+		}
+		s.messages <- string(data)
+	}
+}
+
+// Messages delivers every incoming message; it is closed when the connection ends.
+// Use either Messages or Expect on a session, not both.
+func (s *Session) Messages() <-chan string {
+	return s.messages
+}
+
+// Send writes a text message.
+func (s *Session) Send(text string) error {
+	return s.conn.WriteMessage(websocket.TextMessage, []byte(text))
+}
+
+// Expect waits for a message containing substr, skipping others, and returns it with the wait time.
+// An empty substr accepts the next message.
+func (s *Session) Expect(substr string, timeout time.Duration) (string, time.Duration, error) {
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case msg, ok := <-s.messages:
+			if !ok {
+				return "", time.Since(start), s.UnexpectedClose()
+			}
+			if strings.Contains(msg, substr) {
+				return msg, time.Since(start), nil
+			}
+		case <-timer.C:
+			if substr == "" {
+				return "", timeout, fmt.Errorf("no message within %s", timeout)
+			}
+			return "", timeout, fmt.Errorf("no message containing %q within %s", substr, timeout)
+		}
+	}
+}
+
+// Ping sends a ping control frame and measures the round trip to the matching pong.
+func (s *Session) Ping(timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	if err := s.conn.WriteControl(websocket.PingMessage, []byte("pingify"), start.Add(timeout)); err != nil {
+		return 0, fmt.Errorf("failed to send ping: %w", err)
+	}
+	select {
+	case <-s.pongs:
+		return time.Since(start), nil
+	case <-s.done:
+		return time.Since(start), s.UnexpectedClose()
+	case <-time.After(timeout):
+		return timeout, fmt.Errorf("no pong within %s", timeout)
+	}
+}
+
+// UnexpectedClose describes how the server ended the connection, or returns nil while it is open
+// or after Close was called.
+func (s *Session) UnexpectedClose() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closeErr == nil || s.closing {
+		return nil
+	}
+	var ce *websocket.CloseError
+	if errors.As(s.closeErr, &ce) {
+		return fmt.Errorf("connection closed by server (code %d %s)", ce.Code, ce.Text)
+	}
+	return fmt.Errorf("connection lost: %w", s.closeErr)
+}
+
+// Close sends a normal close frame and waits briefly for the server to acknowledge it.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	select {
+	case <-s.done:
+	case <-time.After(time.Second):
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,122 @@
+package wscall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// startEchoServer greets each connection with "welcome" and echoes text messages back prefixed
+// with "echo: ". A "bye" message makes it close the connection with code 4000. Handshakes without
+// an X-Token header are rejected.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte("welcome"))
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "bye" {
+				msg := websocket.FormatCloseMessage(4000, "done")
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, append([]byte("echo: "), data...))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestSession(t *testing.T) {
+	url := startEchoServer(t)
+	header := http.Header{"X-Token": {"t0k"}}
+
+	tests := []struct {
+		name    string
+		send    string
+		expect  string
+		want    string
+		errText string
+	}{
+		{"next message", "", "", "welcome", ""},
+		{"echo", "hello", "echo: hello", "echo: hello", ""},
+		{"skips other messages", "a", "echo: a", "echo: a", ""},
+		{"no match", "a", "echo: b", "", `no message containing "echo: b"`},
+		{"closed by server", "bye", "echo", "", "code 4000 done"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Dial(url, header, Options{Timeout: 2 * time.Second})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			if s.Handshake <= 0 {
+				t.Errorf("handshake = %s", s.Handshake)
+			}
+			if tt.send != "" {
+				if err := s.Send(tt.send); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, _, err := s.Expect(tt.expect, 200*time.Millisecond)
+			if tt.errText == "" && err != nil {
+				t.Fatalf("Expect(%q): %v", tt.expect, err)
+			}
+			if tt.errText != "" && (err == nil || !strings.Contains(err.Error(), tt.errText)) {
+				t.Fatalf("Expect(%q) = %q, %v; want an error mentioning %q", tt.expect, got, err, tt.errText)
+			}
+			if got != tt.want {
+				t.Errorf("Expect(%q) = %q, want %q", tt.expect, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionPingAndClose(t *testing.T) {
+	s, err := Dial(startEchoServer(t), http.Header{"X-Token": {"t0k"}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rtt, err := s.Ping(time.Second); err != nil || rtt <= 0 {
+		t.Errorf("Ping = %s, %v", rtt, err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := s.UnexpectedClose(); err != nil {
+		t.Errorf("UnexpectedClose after Close = %v, want nil", err)
+	}
+	// Messages still buffered are delivered before the channel closes.
+	var got []string
+	for msg := range s.Messages() {
+		got = append(got, msg)
+	}
+	if len(got) != 1 || got[0] != "welcome" {
+		t.Errorf("messages = %q, want the greeting", got)
+	}
+}
+
+func TestDialHandshakeFailure(t *testing.T) {
+	_, err := Dial(startEchoServer(t), nil, Options{Timeout: time.Second})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("err = %v, want the HTTP status reported", err)
+	}
+}